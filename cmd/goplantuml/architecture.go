@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// checkArchitecture returns the dependencies between the parsed packages that the layers of the given architecture
+// file do not allow, one per line, and an error if there is any
+func checkArchitecture(result *goplantuml.ClassParser, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	architecture, err := goplantuml.ReadArchitecture(f)
+	if err != nil {
+		return "", err
+	}
+	violations := result.LayerViolations(architecture)
+	if len(violations) == 0 {
+		return "", nil
+	}
+	report := &goplantuml.LineStringBuilder{}
+	for _, violation := range violations {
+		report.WriteLineWithDepth(0, violation.String())
+	}
+	return report.String(), fmt.Errorf("%d dependencies are not allowed by the architecture %s", len(violations), path)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+	"github.com/jfeliu007/goplantuml/parser/diff"
+)
+
+// getBaseline returns the baseline of the parsed structures as JSON
+func getBaseline(result *goplantuml.ClassParser) (string, error) {
+	buffer := &bytes.Buffer{}
+	if err := diff.NewBaseline(result).WriteJSON(buffer); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// checkBaseline returns the dependencies and public API of the parsed structures that are not in the approved
+// baseline file, one per line, and an error if there is any
+func checkBaseline(result *goplantuml.ClassParser, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	approved, err := diff.ReadBaseline(f)
+	if err != nil {
+		return "", err
+	}
+	changes := diff.CheckBaseline(approved, diff.NewBaseline(result))
+	if len(changes) > 0 {
+		return diff.RenderText(changes), fmt.Errorf("%d changes are not in the baseline %s", len(changes), path)
+	}
+	return "", nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// sortableSource declares a slice type with methods, which is both an alias and the holder of its methods
+const sortableSource = `package sortable
+
+type Item struct {
+	Name string
+}
+
+type Items []Item
+
+func (s Items) Len() int { return len(s) }
+
+func (s Items) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+func (s Items) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+`
+
+func TestCheckBaselineOfUnchangedCode(t *testing.T) {
+	parse := func() *goplantuml.ClassParser {
+		result, err := goplantuml.NewClassDiagramFromSources(map[string]string{"sortable/sortable.go": sortableSource}, &goplantuml.ClassDiagramOptions{})
+		if err != nil {
+			t.Fatalf("expected no error parsing but got %s", err.Error())
+		}
+		return result
+	}
+	exported, err := getBaseline(parse())
+	if err != nil {
+		t.Fatalf("expected no error exporting the baseline but got %s", err.Error())
+	}
+	path := filepath.Join(t.TempDir(), "approved.json")
+	if err := os.WriteFile(path, []byte(exported), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		result := parse()
+		if again, err := getBaseline(result); err != nil || again != exported {
+			t.Fatalf("expected the same baseline to be exported every time, got %s instead of %s (%v)", again, exported, err)
+		}
+		if report, err := checkBaseline(result, path); err != nil {
+			t.Fatalf("expected the unchanged code to match its baseline, got %s: %s", err.Error(), report)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// runConfig runs the goplantuml config subcommand with the given arguments
+func runConfig(args []string) {
+	flags := flag.NewFlagSet("config", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage:\ngoplantuml config validate <ARCHITECTURE_FILE>")
+		flags.PrintDefaults()
+	}
+	parseSubcommandFlags(flags, args)
+	if flags.NArg() != 2 || flags.Arg(0) != "validate" {
+		flags.Usage()
+		os.Exit(1)
+	}
+	if err := validateArchitecture(flags.Arg(1)); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", flags.Arg(1), err.Error())
+		os.Exit(1)
+	}
+	fmt.Printf("%s is valid\n", flags.Arg(1))
+}
+
+// validateArchitecture returns an error if the given file is not a valid architecture for -architecture
+func validateArchitecture(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = goplantuml.ReadArchitecture(f)
+	return err
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+	"github.com/jfeliu007/goplantuml/parser/diff"
+	"github.com/spf13/afero"
+)
+
+// runDiff runs the goplantuml diff subcommand with the given arguments
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage:\ngoplantuml diff [options] <DIR_BEFORE> <DIR_AFTER>\ngoplantuml diff [options] -rev <REVISION> <DIR>")
+		flags.PrintDefaults()
+	}
+	recursive := flags.Bool("recursive", false, "walk all directories recursively")
+	rev := flags.String("rev", "", "git revision (e.g. a commit, tag or branch) to compare the given directory against, instead of comparing two directories")
+	format := flags.String("format", "text", "output format. One of text or plantuml")
+	output := flags.String("output", "", "output file path. If omitted, then this will default to standard output")
+	parseSubcommandFlags(flags, args)
+	if *format != "text" && *format != "plantuml" {
+		fmt.Fprintf(os.Stderr, "unknown format %s\n", *format)
+		os.Exit(1)
+	}
+	before, after, err := getDiffModels(flags.Args(), *rev, *recursive)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		flags.Usage()
+		os.Exit(1)
+	}
+	changes := diff.Compare(before, after)
+	if *format == "plantuml" {
+		writeOutput(*output, diff.RenderPlantUML(before, after, changes))
+		return
+	}
+	writeOutput(*output, diff.RenderText(changes))
+}
+
+// getDiffModels parses the two directories to compare or, if rev is not empty, the given directory as it is in the
+// revision and in the working tree
+func getDiffModels(args []string, rev string, recursive bool) (diff.Model, diff.Model, error) {
+	expected := 2
+	if rev != "" {
+		expected = 1
+	}
+	if len(args) != expected {
+		return nil, nil, fmt.Errorf("expected %d directories, got %d", expected, len(args))
+	}
+	dirs := make([]string, 0, len(args))
+	for _, arg := range args {
+		dir, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	if rev != "" {
+		revisionDir, revisionDirs, _, err := exportRevision(rev, dirs, []string{})
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.RemoveAll(revisionDir)
+		dirs = append(revisionDirs, dirs...)
+	}
+	before, err := getDiffModel(dirs[0], recursive)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := getDiffModel(dirs[1], recursive)
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+func getDiffModel(dir string, recursive bool) (diff.Model, error) {
+	result, err := goplantuml.NewClassDiagramWithOptions(&goplantuml.ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{dir},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[goplantuml.RenderingOption]interface{}{},
+		Recursive:          recursive,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diff.NewModel(result), nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// getDocCoverageReport returns the documentation coverage of every parsed package as a table or as JSON
+func getDocCoverageReport(result *goplantuml.ClassParser, format string) (string, error) {
+	coverage := result.DocCoverage()
+	buffer := &bytes.Buffer{}
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(buffer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(coverage); err != nil {
+			return "", err
+		}
+	case "table":
+		writer := tabwriter.NewWriter(buffer, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "PACKAGE\tDOCUMENTED\tTOTAL\tCOVERAGE")
+		for _, packageCoverage := range coverage {
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%.1f%%\n", packageCoverage.PackageName, packageCoverage.Documented, packageCoverage.Total, packageCoverage.Coverage)
+		}
+		writer.Flush()
+		for _, packageCoverage := range coverage {
+			for _, name := range packageCoverage.Undocumented {
+				fmt.Fprintf(buffer, "%s is not documented\n", name)
+			}
+		}
+	default:
+		return "", fmt.Errorf("unknown doc coverage format %s. One of table or json", format)
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envPrefix starts the names of the environment variables setting the flags
+const envPrefix = "GOPLANTUML_"
+
+// getEnvName returns the name of the environment variable starting with prefix setting the given flag, e.g.
+// GOPLANTUML_SHOW_ALIASES for -show-aliases
+func getEnvName(prefix string, name string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// getSubcommandEnvPrefix returns the prefix of the environment variables setting the flags of the given subcommand,
+// e.g. GOPLANTUML_SERVE_ for serve, so they do not set the flags of the same name of the main command
+func getSubcommandEnvPrefix(subcommand string) string {
+	return envPrefix + strings.ToUpper(subcommand) + "_"
+}
+
+// parseSubcommandFlags parses the arguments of a subcommand, named after its flags, and sets the flags that are not
+// given from the environment. It exits when the environment has invalid values.
+func parseSubcommandFlags(flags *flag.FlagSet, args []string) {
+	flags.Parse(args)
+	if err := setFlagsFromEnv(flags, getSubcommandEnvPrefix(flags.Name())); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// setFlagsFromEnv sets the flags that are not given on the command line from their environment variable starting with
+// prefix, if set. The command line wins over the environment which wins over the defaults.
+func setFlagsFromEnv(flags *flag.FlagSet, prefix string) error {
+	given := map[string]struct{}{}
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = struct{}{}
+	})
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if _, ok := given[f.Name]; ok || err != nil {
+			return
+		}
+		value, ok := os.LookupEnv(getEnvName(prefix, f.Name))
+		if !ok {
+			return
+		}
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %s", value, getEnvName(prefix, f.Name), setErr.Error())
+		}
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestSetFlagsFromEnv(t *testing.T) {
+	tt := []struct {
+		name             string
+		args             []string
+		env              map[string]string
+		expectedOutput   string
+		expectedAliases  bool
+		expectedErrorSet bool
+	}{
+		{
+			name:           "defaults",
+			expectedOutput: "",
+		},
+		{
+			name:            "environment over the defaults",
+			env:             map[string]string{"GOPLANTUML_OUTPUT": "env.puml", "GOPLANTUML_SHOW_ALIASES": "true"},
+			expectedOutput:  "env.puml",
+			expectedAliases: true,
+		},
+		{
+			name:            "command line over the environment",
+			args:            []string{"-output", "flag.puml"},
+			env:             map[string]string{"GOPLANTUML_OUTPUT": "env.puml", "GOPLANTUML_SHOW_ALIASES": "true"},
+			expectedOutput:  "flag.puml",
+			expectedAliases: true,
+		},
+		{
+			name:           "hook variables are not flags",
+			env:            map[string]string{"GOPLANTUML_HOOK_OUTPUT": "parent.puml", "GOPLANTUML_HOOK_FORMAT": "svg"},
+			expectedOutput: "",
+		},
+		{
+			name:             "invalid value",
+			env:              map[string]string{"GOPLANTUML_SHOW_ALIASES": "maybe"},
+			expectedErrorSet: true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+			flags := flag.NewFlagSet("goplantuml", flag.ContinueOnError)
+			output := flags.String("output", "", "")
+			flags.String("format", "puml", "")
+			showAliases := flags.Bool("show-aliases", false, "")
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			err := setFlagsFromEnv(flags, envPrefix)
+			if tc.expectedErrorSet {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err.Error())
+			}
+			if *output != tc.expectedOutput {
+				t.Errorf("expected output %q, got %q", tc.expectedOutput, *output)
+			}
+			if *showAliases != tc.expectedAliases {
+				t.Errorf("expected show-aliases %t, got %t", tc.expectedAliases, *showAliases)
+			}
+		})
+	}
+}
+
+func TestParseSubcommandFlags(t *testing.T) {
+	t.Setenv("GOPLANTUML_FORMAT", "svg")
+	t.Setenv("GOPLANTUML_DIFF_FORMAT", "plantuml")
+	t.Setenv("GOPLANTUML_DIFF_RECURSIVE", "true")
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	recursive := flags.Bool("recursive", false, "")
+	format := flags.String("format", "text", "")
+	output := flags.String("output", "", "")
+	parseSubcommandFlags(flags, []string{"-output", "diff.txt", "before", "after"})
+	if !*recursive || *format != "plantuml" || *output != "diff.txt" {
+		t.Errorf("expected recursive true, format plantuml and output diff.txt, got %t, %s and %s", *recursive, *format, *output)
+	}
+	if flags.NArg() != 2 {
+		t.Errorf("expected the directories to remain arguments, got %v", flags.Args())
+	}
+}
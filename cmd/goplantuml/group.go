@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+var groupAliasReplacer = regexp.MustCompile(`\W`)
+
+// getGroups returns the directories matching the given pattern (e.g. services/*) inside every one of the given
+// directories, keyed by their path relative to it
+func getGroups(dirs []string, pattern string) (map[string]string, error) {
+	groups := map[string]string{}
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if fi, err := os.Stat(match); err != nil || !fi.IsDir() {
+				continue
+			}
+			name, err := filepath.Rel(dir, match)
+			if err != nil {
+				return nil, err
+			}
+			groups[filepath.ToSlash(name)] = match
+		}
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no directory matches the group pattern %s", pattern)
+	}
+	return groups, nil
+}
+
+// getGroupsDiagram renders the dependencies between the groups matching the given pattern in the directories of the
+// given options
+func getGroupsDiagram(pattern string, options goplantuml.ClassDiagramOptions, diagramsDir string) (string, error) {
+	groups, err := getGroups(options.Directories, pattern)
+	if err != nil {
+		return "", err
+	}
+	return getGroupReport(groups, options, diagramsDir)
+}
+
+// getGroupReport parses every group recursively and renders a diagram with the dependencies between them. A group
+// depends on another one when its structures reference a package declared by the other group and not by itself.
+// When diagramsDir is not empty, the class diagram of every group is written in it.
+func getGroupReport(groups map[string]string, options goplantuml.ClassDiagramOptions, diagramsDir string) (string, error) {
+	names := []string{}
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	declared := map[string]map[string]struct{}{}
+	dependencies := map[string]map[string]struct{}{}
+	for _, name := range names {
+		options.Directories = []string{groups[name]}
+		options.Recursive = true
+		result, err := goplantuml.NewClassDiagramWithOptions(&options)
+		if err != nil {
+			return "", err
+		}
+		declared[name] = map[string]struct{}{}
+		for _, pack := range result.Packages() {
+			declared[name][pack] = struct{}{}
+		}
+		dependencies[name] = map[string]struct{}{}
+		for _, packageDependencies := range result.PackageDependencies() {
+			for _, dependency := range packageDependencies {
+				dependencies[name][dependency] = struct{}{}
+			}
+		}
+		if diagramsDir != "" {
+			diagram := filepath.Join(diagramsDir, getGroupAlias(name)+".puml")
+			if err := ioutil.WriteFile(diagram, []byte(result.Render()), 0644); err != nil {
+				return "", err
+			}
+		}
+	}
+	report := &goplantuml.LineStringBuilder{}
+	report.WriteLineWithDepth(0, "@startuml")
+	for _, name := range names {
+		report.WriteLineWithDepth(0, fmt.Sprintf(`package "%s" as %s {`, name, getGroupAlias(name)))
+		report.WriteLineWithDepth(0, "}")
+	}
+	for _, name := range names {
+		for _, other := range names {
+			if other != name && dependsOnGroup(dependencies[name], declared[name], declared[other]) {
+				report.WriteLineWithDepth(0, fmt.Sprintf(`%s ..> %s`, getGroupAlias(name), getGroupAlias(other)))
+			}
+		}
+	}
+	report.WriteLineWithDepth(0, "@enduml")
+	return report.String(), nil
+}
+
+func dependsOnGroup(dependencies map[string]struct{}, own map[string]struct{}, other map[string]struct{}) bool {
+	for dependency := range dependencies {
+		if _, ok := own[dependency]; ok {
+			continue
+		}
+		if _, ok := other[dependency]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getGroupAlias returns a name for the group that can be used as a PlantUML alias and as a file name
+func getGroupAlias(name string) string {
+	return groupAliasReplacer.ReplaceAllString(name, "_")
+}
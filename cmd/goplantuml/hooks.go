@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// runHook runs the given -pre-render or -post-render command with the shell, passing the output path and format as
+// the GOPLANTUML_HOOK_OUTPUT and GOPLANTUML_HOOK_FORMAT environment variables. They are not named after flags so a
+// goplantuml run by the hook does not take them as its -output and -format. Its output is forwarded to the standard
+// error so it does not mix with the rendered diagram.
+func runHook(name string, command string, output string, format string) error {
+	if command == "" {
+		return nil
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), "GOPLANTUML_HOOK_OUTPUT="+output, "GOPLANTUML_HOOK_FORMAT="+format)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook failed: %s", name, err.Error())
+	}
+	return nil
+}
+
+// getOutputFormat returns the format of the written output given to the hooks: the image format when -render is used
+// and the diagram format otherwise
+func getOutputFormat(render string, format string) string {
+	if render != "" {
+		return render
+	}
+	return format
+}
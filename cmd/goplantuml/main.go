@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	goplantuml "github.com/jfeliu007/goplantuml/parser"
+	"go/build"
 	"io"
+	"io/ioutil"
+	"log"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+
+	"github.com/spf13/afero"
 )
 
 // RenderingOptionSlice will implements the sort interface
@@ -32,8 +41,29 @@ func (as RenderingOptionSlice) Swap(i, j int) {
 }
 
 func main() {
-	recursive := flag.Bool("recursive", false, "walk all directories recursively")
-	ignore := flag.String("ignore", "", "comma separated list of folders to ignore")
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serve(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfig(os.Args[2:])
+		return
+	}
+	recursive := flag.Bool("recursive", false, "walk all directories recursively. The vendor, testdata, node_modules and hidden directories are skipped unless included with -include-vendor, -include-hidden or an -ignore pattern starting with ! (e.g. !testdata)")
+	includeVendor := flag.Bool("include-vendor", false, "walk the vendor directories too when -recursive is used")
+	expandAnonymousStructs := flag.Bool("expand-anonymous-structs", false, "renders the anonymous struct types of the fields as classes named after their structure and field (e.g. Outer_Config) composing it")
+	followSymlinks := flag.Bool("follow-symlinks", false, "follow the symbolic links to directories when -recursive is used. The directories reached again through a link are parsed once")
+	includeHidden := flag.Bool("include-hidden", false, "walk the directories whose name starts with a dot too when -recursive is used")
+	ignoreAggregations := flag.String("ignore-aggregations", "default", "comma separated list of patterns (e.g. uuid.UUID or sync.*) of the types whose aggregations are not rendered. default stands for the standard library types most structures hold (context.Context, sync.* and time.*). Empty to render them all")
+	loadModel := flag.String("load-model", "", "comma separated model files written by -save-model to merge and render instead of parsing directories. The parsing flags are ignored")
+	saveModel := flag.String("save-model", "", "file the parsed model is written to, to be rendered later with -load-model")
+	savePackageModels := flag.String("save-package-models", "", "directory the parsed model is written to as one file per package, loadable with -load-model, and a manifest.json listing them, so large models can be loaded package by package")
+	file := flag.String("file", "", "go file to parse instead of directories. A single - argument parses the go source given on the standard input")
+	ignore := flag.String("ignore", "", "comma separated list of folders to ignore. Glob patterns (e.g. **/mocks, *_gen or internal/*/testdata) are matched against the paths relative to the given directories when -recursive is used, the ones starting with ! (e.g. !testdata) including the directories back")
 	showAggregations := flag.Bool("show-aggregations", false, "renders public aggregations even when -hide-connections is used (do not render by default)")
 	hideFields := flag.Bool("hide-fields", false, "hides fields")
 	hideMethods := flag.Bool("hide-methods", false, "hides methods")
@@ -44,19 +74,139 @@ func main() {
 	showConnectionLabels := flag.Bool("show-connection-labels", false, "Shows labels in the connections to identify the connections types (e.g. extends, implements, aggregates, alias of")
 	title := flag.String("title", "", "Title of the generated diagram")
 	notes := flag.String("notes", "", "Comma separated list of notes to be added to the diagram")
+	direction := flag.String("direction", "", "direction of the diagram. One of top-to-bottom or left-to-right (by default the one of PlantUML)")
+	hideEmptyMembers := flag.Bool("hide-empty-members", false, "hides the empty fields and methods compartments of the classes")
+	hiddenNamespaceEdges := flag.Bool("hidden-namespace-edges", false, "chains the namespaces with hidden edges so they are laid out one after another in the direction of the diagram")
+	colorPackages := flag.Bool("color-packages", false, "gives the classes of every package a background color so they can be told apart even when connected across namespaces")
+	packageKeyword := flag.Bool("package-keyword", false, "renders the packages as quoted package blocks instead of namespaces, for package names that break namespaces")
+	packageColors := flag.String("package-colors", "", "comma separated list of package name patterns and the background color of their classes (e.g. *controller=#DAE8FC,*repository=#D5E8D4). They take precedence over -color-packages")
+	theme := flag.String("theme", "", "PlantUML theme of the diagram (e.g. cerulean)")
+	skinParams := flag.String("skinparams", "", "Comma separated list of skinparams added to the diagram (e.g. linetype ortho,ArrowColor #333333)")
+	check := flag.String("check", "", "golden file path. The output is compared with it instead of being written and the command fails if they differ (e.g. to check in CI that a committed diagram is up to date)")
 	output := flag.String("output", "", "output file path. If omitted, then this will default to standard output")
 	showOptionsAsNote := flag.Bool("show-options-as-note", false, "Show a note in the diagram with the none evident options ran with this CLI")
 	aggregatePrivateMembers := flag.Bool("aggregate-private-members", false, "Show aggregations for private members. Ignored if -show-aggregations is not used.")
 	hidePrivateMembers := flag.Bool("hide-private-members", false, "Hide private fields and methods")
+	include := flag.String("include", "", "regular expression. Only the types whose package qualified name (e.g. parser.Struct) matches it are rendered")
+	exclude := flag.String("exclude", "", "regular expression. The types whose package qualified name (e.g. parser.Struct) matches it are not rendered")
+	includeExternal := flag.Bool("include-external", false, "Render the types of imported packages that are referenced or implemented by the parsed types in an external namespace")
+	cleanSignatures := flag.Bool("clean-signatures", false, "Omit the trailing error return value from the rendered methods")
+	showBuiltinNotes := flag.Bool("show-builtin-notes", false, "Shows relationships to builtin types (e.g. embedded error, alias of int) as notes. These are never rendered as connections")
+	showConversions := flag.Bool("show-conversions", false, "Shows the explicit conversions between the parsed types (e.g. UserDTO(user)) as connections")
+	showDependencies := flag.Bool("show-dependencies", false, "Connect the structures to the types whose methods they call or that they construct in their methods, unless they are already connected. It makes the parsing slower")
+	showDocComments := flag.Bool("show-doc-comments", false, "Render the doc comments of structs, interfaces and methods as notes")
+	docCommentsMaxLength := flag.Int("doc-comments-max-length", 80, "maximum length of the rendered doc comments. Longer comments are truncated. 0 disables the truncation")
+	flattenInterfaces := flag.Bool("flatten-interfaces", false, "Inline the methods of embedded interfaces in the embedding interface instead of connecting them")
+	showFunctions := flag.Bool("show-functions", false, "Render the functions without receiver and the exported variables of every package in a <<functions>> class")
+	showFieldTags := flag.Bool("show-field-tags", false, "Render the struct field tags next to the fields")
+	publicMemberSymbol := flag.String("public-member-symbol", "+", "symbol rendered before the exported fields and methods. Empty for none")
+	privateMemberSymbol := flag.String("private-member-symbol", "-", "symbol rendered before the unexported fields and methods (e.g. ~). Empty for none")
+	showVisibilityLegend := flag.Bool("show-visibility-legend", false, "Render a legend explaining the symbols rendered before the fields and methods")
+	showEmbeds := flag.Bool("show-embeds", false, "Render the variables initialized with a //go:embed directive as artifacts linked to the structures using them")
+	showContextWarnings := flag.Bool("show-context-warnings", false, "Annotate the exported methods without a context.Context first parameter in packages where most exported methods have one")
+	contextReport := flag.Bool("context-report", false, "prints the exported methods without a context.Context first parameter in packages where most exported methods have one instead of the diagram")
+	showQualifiedAssociations := flag.Bool("show-qualified-associations", false, "Label the aggregations of the values of map fields with the key type of the map (e.g. per UserID for map[UserID]*Session). Ignored if -show-aggregations is not used")
+	collapsePackageThreshold := flag.Int("collapse-package-threshold", 0, "packages with more types are rendered as a single node with the number of their types, the relationships with their types being drawn to and from that node. 0 for no limit")
+	showCycles := flag.Bool("show-cycles", false, "Render in red the compositions, extensions and aggregations between packages of a dependency cycle")
+	showMetrics := flag.Bool("show-metrics", false, "Render a legend with the number of structs, interfaces and methods and the coupling of every package")
+	showMultiplicity := flag.Bool("show-multiplicity", false, "Annotate the aggregations of the types held by slice, map and array fields with their multiplicity (e.g. 0..* for []*Seat or 4 for [4]Wheel). Ignored if -show-aggregations is not used")
+	linkTemplate := flag.String("link-template", "", "url every class links to, {path} being replaced by the path of the file declaring it relative to the root of the git repository and {line} by its line (e.g. https://github.com/org/repo/blob/main/{path}#L{line})")
+	packageRelations := flag.String("package-relations", "", "comma separated list of the kinds of relationships between types that make the relationships between packages of -format c4. Any of composition, extends, aggregation, private aggregation, conversion and dependency. Empty for all but conversion and dependency")
+	showPackageWeights := flag.Bool("show-package-weights", false, "Label the relationships between packages of -format c4 with the number of relationships between their types")
+	showDeprecations := flag.Bool("show-deprecations", false, "Render the types documented as Deprecated: with the deprecated stereotype and such methods struck through")
+	showAsync := flag.Bool("show-async", false, "Render the methods launching goroutines with the <<async>> stereotype")
+	showReceivers := flag.Bool("show-receivers", false, "Annotate the methods declared on a pointer receiver with {ptr}")
+	showChannels := flag.Bool("show-channels", false, "Render the aggregations of the types carried by channel fields as edges labeled sends, receives or sends/receives. Ignored if -show-aggregations is not used")
+	channelArrow := flag.String("channel-arrow", "-->", "PlantUML arrow (e.g. ..> or -[#blue]->) of the edges rendered by -show-channels")
+	showSourceLinks := flag.Bool("show-source-links", false, "Link every class to the file and line ([[file:line]]) declaring it")
+	showSingletons := flag.Bool("show-singletons", false, "Render package level variables holding one of the parsed structs as singleton objects")
+	typeCheckImports := flag.Bool("type-check-imports", false, "type checks the imported packages from their source, so their methods and the interfaces they implement are known too (e.g. a struct embedding sync.Mutex implements sync.Locker). Much slower. Implied by -include-external, -show-dependencies, -sequence and -providers")
+	matchUnderlyingTypes := flag.Bool("match-underlying-types", false, "Consider that a method implements an interface method when their parameters and return values have the same underlying types (e.g. MyString declared as type MyString string matches string). By default only aliases (type MyString = string) do, like for the compiler")
+	tags := flag.String("tags", "", "comma separated list of build tags. Only the files matching them and the target platform are parsed (by default every go file is)")
+	goos := flag.String("goos", "", "target operating system (e.g. windows). Only the files built for it are parsed. Defaults to the current one when -tags or -goarch is used")
+	goarch := flag.String("goarch", "", "target architecture (e.g. arm64). Only the files built for it are parsed. Defaults to the current one when -tags or -goos is used")
+	architecture := flag.String("architecture", "", "JSON file declaring the layers of packages and the layers each of them may depend on. Prints the dependencies between packages that are not allowed instead of the diagram and fails if there are any")
+	baseline := flag.String("baseline", "", "approved baseline file written by -export-baseline. Prints the dependencies between packages and the public API that are not in it instead of the diagram and fails if there are any")
+	exportBaseline := flag.Bool("export-baseline", false, "prints the dependencies between packages and the public API as JSON instead of the diagram, to be approved and checked later with -baseline")
+	progress := flag.Bool("progress", false, "prints the progress (directories scanned with their number of files and types, and the time taken by every step) to stderr")
+	verbose := flag.Bool("v", false, "prints every parsed file to stderr on top of the progress printed by -progress")
+	timeout := flag.Duration("timeout", 0, "stops the parsing with an error after the given duration (e.g. 5m). 0 for no timeout. The parsing can be interrupted with Ctrl+C too")
+	strict := flag.Bool("strict", false, "fail if any go file cannot be parsed. By default these files are left out of the diagram with a warning")
+	includeTests := flag.Bool("include-tests", false, "Parse the _test.go files too. Their types are rendered with the test stereotype and the external test packages (e.g. parser_test) in their own namespace")
+	interfaces := flag.Bool("interfaces", false, "prints, for every parsed interface, the structures implementing it and the ones missing a single method instead of the diagram")
+	globals := flag.Bool("globals", false, "prints the package level variables (global mutable state) of every package instead of the diagram")
+	format := flag.String("format", "plantuml", "output format. One of plantuml, dot or c4 (a C4-PlantUML component diagram of the packages)")
+	rev := flag.String("rev", "", "git revision (e.g. a commit, tag or branch) to parse instead of the working tree. The directories must be inside the repository")
+	groupBy := flag.String("group-by", "", "path pattern (e.g. services/*) relative to the given directories. Every matching directory is treated as a group and a diagram of the dependencies between the groups is rendered instead of the class diagram")
+	groupByFile := flag.Bool("group-by-file", false, "Nest the types of every package in a package block per file declaring them")
+	groupDiagramsDir := flag.String("group-diagrams-dir", "", "existing directory where the class diagram of every group is written when -group-by is used")
+	trend := flag.String("trend", "", "git revision to start from. Prints the metrics (packages, types, methods, relationships) of every revision since the given one as CSV instead of the diagram")
+	trendStep := flag.String("trend-step", "tag", "revisions analyzed by -trend. One of tag or commit")
+	render := flag.String("render", "", "renders the PlantUML diagram as an image instead of printing it. One of svg or png. Requires -plantuml-jar or -plantuml-server")
+	plantUMLJar := flag.String("plantuml-jar", "", "path of the plantuml.jar used by -render (java must be in the PATH)")
+	plantUMLServer := flag.String("plantuml-server", "", "url of the PlantUML server used by -render (e.g. http://www.plantuml.com/plantuml). Ignored if -plantuml-jar is used")
+	preRender := flag.String("pre-render", "", "shell command run before parsing (e.g. to generate code). The output path and format are given in the GOPLANTUML_HOOK_OUTPUT and GOPLANTUML_HOOK_FORMAT environment variables")
+	postRender := flag.String("post-render", "", "shell command run after the diagram is written (e.g. plantuml -tsvg \"$GOPLANTUML_HOOK_OUTPUT\" or copying it to docs/). The output path and format are given in the GOPLANTUML_HOOK_OUTPUT and GOPLANTUML_HOOK_FORMAT environment variables")
+	sequence := flag.String("sequence", "", "function or method (e.g. parser.ClassParser.Render or parser.NewClassDiagram) whose calls between the parsed types and packages are rendered as a sequence diagram instead of the class diagram")
+	sequenceDepth := flag.Int("sequence-depth", 3, "maximum depth of the calls followed by -sequence")
+	providers := flag.Bool("providers", false, "renders the dependency injection graph of the google/wire and uber/fx providers instead of the class diagram. Fails listing the types without provider if there are any")
+	lifecycle := flag.Bool("lifecycle", false, "experimental. Renders a state diagram of the lifecycle (constructor, Start, Run, Stop and Close methods) of every structure having one instead of the class diagram")
+	metrics := flag.String("metrics", "", "prints the number of structs, interfaces and methods, the coupling and the number of relationships of every kind of every package, and the largest, most depended upon and most dependent types, instead of the diagram. One of table or json")
+	templateFile := flag.String("template", "", "file of a Go text/template executed on the parsed code instead of rendering the diagram, to write any text format (see example/templates). The template is given the parser, e.g. {{range $name, $type := .Structs}} or {{range .Relations}}")
+	metricsTop := flag.Int("metrics-top", 10, "number of largest, most depended upon and most dependent types listed by -metrics")
+	docCoverage := flag.String("doc-coverage", "", "prints the exported types and methods without doc comment and the documentation coverage of every package instead of the diagram. One of table or json")
+	relations := flag.String("relations", "", "file listing, one per line, relationships to add to the diagram as source arrow target, optionally followed by a colon and a label (e.g. orders.Service -> queue.Client : publishes). The arrow is one of ->, ..>, <|--, *-- and o--")
+	cycles := flag.Bool("cycles", false, "prints the dependency cycles between packages instead of the diagram and fails if there are any")
+	impact := flag.String("impact", "", "prints the structures and packages that reference the given type (e.g. parser.Struct) instead of the diagram")
 	flag.Parse()
+	if err := setFlagsFromEnv(flag.CommandLine, envPrefix); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
 	renderingOptions := map[goplantuml.RenderingOption]interface{}{
-		goplantuml.RenderConnectionLabels:  *showConnectionLabels,
-		goplantuml.RenderFields:            !*hideFields,
-		goplantuml.RenderMethods:           !*hideMethods,
-		goplantuml.RenderAggregations:      *showAggregations,
-		goplantuml.RenderTitle:             *title,
-		goplantuml.AggregatePrivateMembers: *aggregatePrivateMembers,
-		goplantuml.RenderPrivateMembers:    !*hidePrivateMembers,
+		goplantuml.RenderConnectionLabels:      *showConnectionLabels,
+		goplantuml.RenderFields:                !*hideFields,
+		goplantuml.RenderMethods:               !*hideMethods,
+		goplantuml.RenderAggregations:          *showAggregations,
+		goplantuml.RenderTitle:                 *title,
+		goplantuml.AggregatePrivateMembers:     *aggregatePrivateMembers,
+		goplantuml.RenderPrivateMembers:        !*hidePrivateMembers,
+		goplantuml.CleanSignatures:             *cleanSignatures,
+		goplantuml.RenderSingletons:            *showSingletons,
+		goplantuml.RenderFieldTags:             *showFieldTags,
+		goplantuml.RenderBuiltinNotes:          *showBuiltinNotes,
+		goplantuml.RenderDocComments:           *showDocComments,
+		goplantuml.DocCommentsMaxLength:        *docCommentsMaxLength,
+		goplantuml.CollapsePackageThreshold:    *collapsePackageThreshold,
+		goplantuml.RenderConversions:           *showConversions,
+		goplantuml.RenderPackageFunctions:      *showFunctions,
+		goplantuml.FlattenInterfaces:           *flattenInterfaces,
+		goplantuml.PublicMemberSymbol:          *publicMemberSymbol,
+		goplantuml.PrivateMemberSymbol:         *privateMemberSymbol,
+		goplantuml.RenderVisibilityLegend:      *showVisibilityLegend,
+		goplantuml.RenderEmbeddedAssets:        *showEmbeds,
+		goplantuml.RenderContextWarnings:       *showContextWarnings,
+		goplantuml.RenderQualifiedAssociations: *showQualifiedAssociations,
+		goplantuml.RenderMultiplicity:          *showMultiplicity,
+		goplantuml.RenderMetricsLegend:         *showMetrics,
+		goplantuml.RenderCycles:                *showCycles,
+		goplantuml.RenderFileGroups:            *groupByFile,
+		goplantuml.RenderSourceLinks:           *showSourceLinks,
+		goplantuml.LinkTemplate:                *linkTemplate,
+		goplantuml.RenderChannels:              *showChannels,
+		goplantuml.RenderReceivers:             *showReceivers,
+		goplantuml.RenderAsync:                 *showAsync,
+		goplantuml.RenderDeprecations:          *showDeprecations,
+		goplantuml.RenderPackageWeights:        *showPackageWeights,
+		goplantuml.ChannelArrow:                *channelArrow,
+		goplantuml.RenderDependencies:          *showDependencies,
+		goplantuml.RenderTheme:                 *theme,
+		goplantuml.RenderDirection:             *direction,
+		goplantuml.HideEmptyMembers:            *hideEmptyMembers,
+		goplantuml.RenderHiddenNamespaceEdges:  *hiddenNamespaceEdges,
+		goplantuml.RenderPackageColors:         *colorPackages,
+		goplantuml.RenderPackageKeyword:        *packageKeyword,
+		goplantuml.SkinParams:                  strings.Join(strings.Split(*skinParams, ","), "\n"),
 	}
 	if *hideConnections {
 		renderingOptions[goplantuml.RenderAliases] = *showAliases
@@ -84,41 +234,268 @@ func main() {
 		}
 	}
 	renderingOptions[goplantuml.RenderNotes] = strings.Join(noteList, "\n")
-	dirs, err := getDirectories()
+	source := getSourceFile(*file)
+	dirs, err := getDirectories(source != "" || *loadModel != "", *rev == "" && *trend == "")
 
 	if err != nil {
 		fmt.Println("usage:\ngoplantuml <DIR>\nDIR Must be a valid directory")
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
-	ignoredDirectories, err := getIgnoredDirectories(*ignore)
+	ignoredDirectories, ignoredPatterns, err := getIgnoredDirectories(*ignore)
 	if err != nil {
 
-		fmt.Println("usage:\ngoplantuml [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
+		fmt.Println("usage:\ngoplantuml [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories or glob patterns")
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 
-	result, err := goplantuml.NewClassDiagram(dirs, ignoredDirectories, *recursive)
-	result.SetRenderingOptions(renderingOptions)
+	if *direction != "" && *direction != goplantuml.DirectionTopToBottom && *direction != goplantuml.DirectionLeftToRight {
+		fmt.Println("usage:\ngoplantuml [-direction=<DIRECTION>]\nDIRECTION Must be one of top-to-bottom or left-to-right")
+		os.Exit(1)
+	}
+	renderingOptions[goplantuml.PackageColors], err = getPackageColors(*packageColors)
+	if err != nil {
+		fmt.Println("usage:\ngoplantuml [-package-colors=<COLORLIST>]\nCOLORLIST Must be a comma separated list of <PATTERN>=<COLOR>")
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	renderingOptions[goplantuml.IgnoredAggregations] = getIgnoredAggregations(*ignoreAggregations)
+	renderingOptions[goplantuml.PackageRelationKinds] = getPackageRelationKinds(*packageRelations)
+	renderingOptions[goplantuml.CustomRelations], err = getCustomRelations(*relations)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	includeTypes, err := getTypesRegexp(*include)
+	if err != nil {
+		fmt.Println("usage:\ngoplantuml [-include=<REGEXP>]\nREGEXP Must be a valid regular expression")
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	excludeTypes, err := getTypesRegexp(*exclude)
+	if err != nil {
+		fmt.Println("usage:\ngoplantuml [-exclude=<REGEXP>]\nREGEXP Must be a valid regular expression")
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	options := &goplantuml.ClassDiagramOptions{
+		FileSystem:             afero.NewOsFs(),
+		Directories:            dirs,
+		IgnoredDirectories:     ignoredDirectories,
+		IgnoredPatterns:        ignoredPatterns,
+		RenderingOptions:       renderingOptions,
+		Recursive:              *recursive,
+		IncludeVendor:          *includeVendor,
+		IncludeHidden:          *includeHidden,
+		FollowSymlinks:         *followSymlinks,
+		ExpandAnonymousStructs: *expandAnonymousStructs,
+		IncludeExternal:        *includeExternal,
+		IncludeTypes:           includeTypes,
+		ExcludeTypes:           excludeTypes,
+		MatchUnderlyingTypes:   *matchUnderlyingTypes,
+		TypeCheckImports:       *typeCheckImports,
+		BuildContext:           getBuildContext(*tags, *goos, *goarch),
+		IncludeTests:           *includeTests,
+		FindDependencies:       *showDependencies,
+		FindCalls:              *sequence != "",
+		FindProviders:          *providers,
+		FindGoroutines:         *showAsync,
+		Strict:                 *strict,
+		Verbose:                *verbose,
+	}
+	if *progress || *verbose {
+		options.Logger = log.New(os.Stderr, "", log.Ltime)
+	}
+	if *trend != "" {
+		report, err := getTrendReport(*trend, *trendStep, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		writeOutput(*output, report)
+		return
+	}
+	if *groupBy != "" {
+		report, err := getGroupsDiagram(*groupBy, *options, *groupDiagramsDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		writeOutput(*output, report)
+		return
+	}
+	if *rev != "" {
+		var revisionDir string
+		revisionDir, options.Directories, options.IgnoredDirectories, err = exportRevision(*rev, dirs, ignoredDirectories)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		defer os.RemoveAll(revisionDir)
+		// the revision is exported with the layout of the repository
+		renderingOptions[goplantuml.LinkRoot] = revisionDir
+	} else if *linkTemplate != "" {
+		renderingOptions[goplantuml.LinkRoot] = getLinkRoot(dirs)
+	}
+	outputFormat := getOutputFormat(*render, *format)
+	if err := runHook("pre-render", *preRender, *output, outputFormat); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	result, err := newClassDiagram(ctx, options, source, *loadModel)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("the parsing timed out after %s", *timeout)
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
-	rendered := result.Render()
-	var writer io.Writer
-	if *output != "" {
-		writer, err = os.Create(*output)
+	for _, diagnostic := range result.Diagnostics() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", diagnostic)
+	}
+	for _, parseError := range result.Errors().Errors {
+		// the files that cannot be parsed are left out of the diagram, unless -strict is used
+		fmt.Fprintf(os.Stderr, "warning: %s\n", parseError.Error())
+	}
+	if *saveModel != "" {
+		if err := result.SaveModel(*saveModel); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	if *savePackageModels != "" {
+		if err := result.SavePackageModels(*savePackageModels); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	var rendered string
+	renderTo := func(w io.Writer) error {
+		_, err := io.WriteString(w, rendered)
+		return err
+	}
+	switch {
+	case *impact != "":
+		rendered = getImpactReport(result, *impact)
+	case *globals:
+		rendered = getGlobalsReport(result)
+	case *contextReport:
+		rendered = getContextReport(result)
+	case *interfaces:
+		rendered = getInterfacesReport(result)
+	case *sequence != "":
+		rendered, err = result.RenderSequence(*sequence, *sequenceDepth)
+	case *metrics != "":
+		rendered, err = getMetricsReport(result, *metrics, *metricsTop)
+	case *templateFile != "":
+		rendered, err = renderTemplate(result, *templateFile)
+	case *docCoverage != "":
+		rendered, err = getDocCoverageReport(result, *docCoverage)
+	case *lifecycle:
+		rendered = result.RenderLifecycles()
+	case *providers:
+		rendered = result.RenderProviders()
+		err = checkProviders(result)
+		if err != nil {
+			writeOutput(*output, rendered)
+		}
+	case *cycles:
+		rendered, err = checkCycles(result)
+		if err != nil {
+			writeOutput(*output, rendered)
+		}
+	case *exportBaseline:
+		rendered, err = getBaseline(result)
+	case *architecture != "":
+		rendered, err = checkArchitecture(result, *architecture)
+		if err != nil {
+			writeOutput(*output, rendered)
+		}
+	case *baseline != "":
+		rendered, err = checkBaseline(result, *baseline)
+		if err != nil {
+			writeOutput(*output, rendered)
+		}
+	case *render != "":
+		renderer, err := getRenderer(*render, *plantUMLJar, *plantUMLServer)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
 		}
-	} else {
-		writer = os.Stdout
+		image, err := renderer.Render(result.Render(), *render)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		rendered = string(image)
+	case *format == "plantuml" && *check == "":
+		// streamed to the output so the diagram is never held in memory as a whole
+		renderTo = result.RenderTo
+	case *format == "plantuml":
+		rendered = result.Render()
+	case *format == "dot":
+		rendered = result.RenderDot()
+	case *format == "c4":
+		rendered = result.RenderC4()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %s\n", *format)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if *check != "" {
+		if err := checkOutput(*check, rendered); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+	if err := writeOutputTo(*output, renderTo); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if err := runHook("post-render", *postRender, *output, outputFormat); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
 	}
-	fmt.Fprint(writer, rendered)
 }
 
-func getDirectories() ([]string, error) {
+// checkOutput returns an error describing the first difference between the rendered text and the given golden file
+func checkOutput(golden string, rendered string) error {
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		return err
+	}
+	if string(expected) == rendered {
+		return nil
+	}
+	expectedLines := strings.Split(string(expected), "\n")
+	renderedLines := strings.Split(rendered, "\n")
+	for i := 0; i < len(expectedLines) && i < len(renderedLines); i++ {
+		if expectedLines[i] != renderedLines[i] {
+			return fmt.Errorf("%s is not up to date, line %d differs:\n- %s\n+ %s", golden, i+1, expectedLines[i], renderedLines[i])
+		}
+	}
+	return fmt.Errorf("%s is not up to date, it has %d lines instead of %d", golden, len(expectedLines), len(renderedLines))
+}
+
+// getDirectories returns the absolute paths of the directories given as arguments, none when noDirectories is true
+// (e.g. a single go file is parsed instead). When mustExist is false (e.g. parsing a git revision) they are not
+// checked against the working tree.
+func getDirectories(noDirectories bool, mustExist bool) ([]string, error) {
+	if noDirectories {
+		return nil, nil
+	}
 
 	args := flag.Args()
 	if len(args) < 1 {
@@ -126,12 +503,14 @@ func getDirectories() ([]string, error) {
 	}
 	dirs := []string{}
 	for _, dir := range args {
-		fi, err := os.Stat(dir)
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("could not find directory %s", dir)
-		}
-		if !fi.Mode().IsDir() {
-			return nil, fmt.Errorf("%s is not a directory", dir)
+		if mustExist {
+			fi, err := os.Stat(dir)
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("could not find directory %s", dir)
+			}
+			if !fi.Mode().IsDir() {
+				return nil, fmt.Errorf("%s is not a directory", dir)
+			}
 		}
 		dirAbs, err := filepath.Abs(dir)
 		if err != nil {
@@ -142,21 +521,253 @@ func getDirectories() ([]string, error) {
 	return dirs, nil
 }
 
-func getIgnoredDirectories(list string) ([]string, error) {
+// getIgnoredDirectories splits the given list into the absolute paths of the ignored directories and the glob
+// patterns, which are the entries containing *, ? or [ or starting with !
+func getIgnoredDirectories(list string) ([]string, []string, error) {
 	result := []string{}
+	patterns := []string{}
 	list = strings.TrimSpace(list)
 	if list == "" {
-		return result, nil
+		return result, patterns, nil
 	}
 	split := strings.Split(list, ",")
 	for _, dir := range split {
-		dirAbs, err := filepath.Abs(strings.TrimSpace(dir))
+		dir = strings.TrimSpace(dir)
+		if strings.ContainsAny(dir, "*?[") || strings.HasPrefix(dir, "!") {
+			if _, err := path.Match(filepath.ToSlash(strings.TrimPrefix(dir, "!")), ""); err != nil {
+				return nil, nil, fmt.Errorf("invalid pattern %s", dir)
+			}
+			patterns = append(patterns, dir)
+			continue
+		}
+		dirAbs, err := filepath.Abs(dir)
 		if err != nil {
-			return nil, fmt.Errorf("could not find directory %s", dir)
+			return nil, nil, fmt.Errorf("could not find directory %s", dir)
 		}
 		result = append(result, dirAbs)
 	}
-	return result, nil
+	return result, patterns, nil
+}
+
+// checkProviders returns an error listing the types required by the dependency injection providers that none of them
+// provides
+func checkProviders(result *goplantuml.ClassParser) error {
+	missing := result.MissingProviders()
+	if len(missing) == 0 {
+		return nil
+	}
+	lines := []string{"missing providers:"}
+	for _, provider := range missing {
+		lines = append(lines, fmt.Sprintf("    %s required by %s", provider.Type, strings.Join(provider.RequiredBy, ", ")))
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// checkCycles returns the dependency cycles between the parsed packages, one per line, and an error if there are any
+func checkCycles(result *goplantuml.ClassParser) (string, error) {
+	packageCycles := result.PackageCycles()
+	if len(packageCycles) == 0 {
+		return "no dependency cycles\n", nil
+	}
+	report := &goplantuml.LineStringBuilder{}
+	for _, cycle := range packageCycles {
+		report.WriteLineWithDepth(0, fmt.Sprintf("cycle between %s", strings.Join(cycle, ", ")))
+	}
+	return report.String(), fmt.Errorf("found %d dependency cycles", len(packageCycles))
+}
+
+func getInterfacesReport(result *goplantuml.ClassParser) string {
+	report := &goplantuml.LineStringBuilder{}
+	for _, satisfaction := range result.InterfaceSatisfactions() {
+		report.WriteLineWithDepth(0, satisfaction.Interface)
+		for _, implementation := range satisfaction.Implementations {
+			report.WriteLineWithDepth(1, fmt.Sprintf("implemented by %s", implementation))
+		}
+		for _, nearMiss := range satisfaction.NearMisses {
+			report.WriteLineWithDepth(1, fmt.Sprintf("one method away: %s is missing %s", nearMiss.Struct, nearMiss.MissingMethod))
+		}
+	}
+	return report.String()
+}
+
+// getBuildContext returns the build context matching the given constraints, or nil if none was given
+func getBuildContext(tags string, goos string, goarch string) *build.Context {
+	if tags == "" && goos == "" && goarch == "" {
+		return nil
+	}
+	context := build.Default
+	if goos != "" {
+		context.GOOS = goos
+	}
+	if goarch != "" {
+		context.GOARCH = goarch
+	}
+	if tags != "" {
+		context.BuildTags = strings.Split(tags, ",")
+	}
+	return &context
+}
+
+// getPackageColors returns the colors of the packages keyed by their pattern from a comma separated list of
+// pattern=color
+func getPackageColors(list string) (map[string]string, error) {
+	colors := map[string]string{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		split := strings.SplitN(entry, "=", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, fmt.Errorf("invalid package color %s", entry)
+		}
+		if _, err := path.Match(split[0], ""); err != nil {
+			return nil, fmt.Errorf("invalid package pattern %s: %s", split[0], err.Error())
+		}
+		colors[split[0]] = split[1]
+	}
+	return colors, nil
+}
+
+// getSourceFile returns the go file parsed instead of directories: the one given with -file, - for the standard input
+// when it is the only argument, or an empty string if directories are parsed
+func getSourceFile(file string) string {
+	if file == "" && flag.NArg() == 1 && flag.Arg(0) == "-" {
+		return "-"
+	}
+	return file
+}
+
+// newClassDiagram loads and merges the given comma separated model files if any, else parses the given go file, or
+// the standard input for -, or the directories of the options if there is none
+func newClassDiagram(ctx context.Context, options *goplantuml.ClassDiagramOptions, source string, models string) (*goplantuml.ClassParser, error) {
+	if models != "" {
+		return loadModels(strings.Split(models, ","), options.RenderingOptions)
+	}
+	switch source {
+	case "":
+		return goplantuml.NewClassDiagramWithContext(ctx, options)
+	case "-":
+		return goplantuml.NewClassDiagramFromReader("stdin.go", os.Stdin, options)
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return goplantuml.NewClassDiagramFromReader(source, f, options)
+}
+
+// loadModels merges the given model files, written by -save-model, into one diagram
+func loadModels(paths []string, renderingOptions map[goplantuml.RenderingOption]interface{}) (*goplantuml.ClassParser, error) {
+	var result *goplantuml.ClassParser
+	for _, path := range paths {
+		model, err := goplantuml.LoadModel(path)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = model
+		} else {
+			result.Merge(model)
+		}
+	}
+	return result, result.SetRenderingOptions(renderingOptions)
+}
+
+// getLinkRoot returns the root of the git repository of the given directories, which the paths of the links are
+// relative to, or the working directory if they are not in a repository
+func getLinkRoot(dirs []string) string {
+	if len(dirs) > 0 {
+		if root, err := runGit(dirs[0], "rev-parse", "--show-toplevel"); err == nil {
+			return strings.TrimSpace(root)
+		}
+	}
+	root, _ := os.Getwd()
+	return root
+}
+
+// getIgnoredAggregations returns the patterns of a comma separated list, default standing for the ones of
+// DefaultIgnoredAggregations
+func getIgnoredAggregations(list string) []string {
+	patterns := []string{}
+	for _, pattern := range strings.Split(list, ",") {
+		pattern = strings.TrimSpace(pattern)
+		switch pattern {
+		case "":
+		case "default":
+			patterns = append(patterns, goplantuml.DefaultIgnoredAggregations...)
+		default:
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// getPackageRelationKinds returns the relation kinds of a comma separated list
+func getPackageRelationKinds(list string) []goplantuml.RelationKind {
+	kinds := []goplantuml.RelationKind{}
+	for _, kind := range strings.Split(list, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, goplantuml.RelationKind(kind))
+		}
+	}
+	return kinds
+}
+
+// getCustomRelations returns the relationships listed in the given file, none if it is empty
+func getCustomRelations(file string) ([]*goplantuml.CustomRelation, error) {
+	if file == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return goplantuml.ParseCustomRelations(string(content))
+}
+
+func getTypesRegexp(expression string) (*regexp.Regexp, error) {
+	if expression == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expression)
+}
+
+func getImpactReport(result *goplantuml.ClassParser, typeName string) string {
+	references := result.FindReferences(typeName)
+	report := &goplantuml.LineStringBuilder{}
+	report.WriteLineWithDepth(0, fmt.Sprintf("Impact of renaming %s: %d references", typeName, len(references)))
+	for _, r := range references {
+		reference := fmt.Sprintf("%s.%s %s", r.PackageName, r.StructName, r.Kind)
+		if r.Member != "" {
+			reference = fmt.Sprintf("%s %s", reference, r.Member)
+		}
+		report.WriteLineWithDepth(1, reference)
+	}
+	report.WriteLineWithDepth(0, fmt.Sprintf("Packages: %s", strings.Join(goplantuml.ReferencingPackages(references), ", ")))
+	return report.String()
+}
+
+func getGlobalsReport(result *goplantuml.ClassParser) string {
+	report := &goplantuml.LineStringBuilder{}
+	currentPackage := ""
+	for _, global := range result.GlobalVariables() {
+		if global.PackageName != currentPackage {
+			currentPackage = global.PackageName
+			report.WriteLineWithDepth(0, currentPackage)
+		}
+		report.WriteLineWithDepth(1, strings.TrimSpace(fmt.Sprintf("%s %s", global.Name, global.Type)))
+	}
+	return report.String()
+}
+
+func getContextReport(result *goplantuml.ClassParser) string {
+	report := &goplantuml.LineStringBuilder{}
+	for _, method := range result.ContextlessMethods() {
+		report.WriteLineWithDepth(0, fmt.Sprintf("%s.%s.%s does not take a context.Context", method.PackageName, method.StructName, method.Method))
+	}
+	return report.String()
 }
 
 func getLegend(ro map[goplantuml.RenderingOption]interface{}) (string, error) {
@@ -183,6 +794,60 @@ func getLegend(ro map[goplantuml.RenderingOption]interface{}) (string, error) {
 			result = fmt.Sprintf("%sRender Methods: %t\n", result, val.(bool))
 		case goplantuml.AggregatePrivateMembers:
 			result = fmt.Sprintf("%sPrivate Aggregations: %t\n", result, val.(bool))
+		case goplantuml.CleanSignatures:
+			result = fmt.Sprintf("%sClean Signatures: %t\n", result, val.(bool))
+		case goplantuml.RenderSingletons:
+			result = fmt.Sprintf("%sRender Singletons: %t\n", result, val.(bool))
+		case goplantuml.RenderFieldTags:
+			result = fmt.Sprintf("%sRender Field Tags: %t\n", result, val.(bool))
+		case goplantuml.RenderBuiltinNotes:
+			result = fmt.Sprintf("%sRender Builtin Notes: %t\n", result, val.(bool))
+		case goplantuml.RenderDocComments:
+			result = fmt.Sprintf("%sRender Doc Comments: %t\n", result, val.(bool))
+		case goplantuml.RenderConversions:
+			result = fmt.Sprintf("%sRender Conversions: %t\n", result, val.(bool))
+		case goplantuml.RenderPackageFunctions:
+			result = fmt.Sprintf("%sRender Functions: %t\n", result, val.(bool))
+		case goplantuml.FlattenInterfaces:
+			result = fmt.Sprintf("%sFlatten Interfaces: %t\n", result, val.(bool))
+		case goplantuml.RenderEmbeddedAssets:
+			result = fmt.Sprintf("%sRender Embedded Assets: %t\n", result, val.(bool))
+		case goplantuml.RenderContextWarnings:
+			result = fmt.Sprintf("%sRender Context Warnings: %t\n", result, val.(bool))
+		case goplantuml.RenderSourceLinks:
+			result = fmt.Sprintf("%sRender Source Links: %t\n", result, val.(bool))
+		case goplantuml.RenderChannels:
+			result = fmt.Sprintf("%sRender Channels: %t\n", result, val.(bool))
+		case goplantuml.RenderReceivers:
+			result = fmt.Sprintf("%sRender Receivers: %t\n", result, val.(bool))
+		case goplantuml.RenderAsync:
+			result = fmt.Sprintf("%sRender Async: %t\n", result, val.(bool))
+		case goplantuml.RenderDeprecations:
+			result = fmt.Sprintf("%sRender Deprecations: %t\n", result, val.(bool))
+		case goplantuml.RenderPackageWeights:
+			result = fmt.Sprintf("%sRender Package Weights: %t\n", result, val.(bool))
+		case goplantuml.RenderFileGroups:
+			result = fmt.Sprintf("%sRender File Groups: %t\n", result, val.(bool))
+		case goplantuml.RenderCycles:
+			result = fmt.Sprintf("%sRender Cycles: %t\n", result, val.(bool))
+		case goplantuml.RenderMetricsLegend:
+			result = fmt.Sprintf("%sRender Metrics Legend: %t\n", result, val.(bool))
+		case goplantuml.RenderMultiplicity:
+			result = fmt.Sprintf("%sRender Multiplicity: %t\n", result, val.(bool))
+		case goplantuml.RenderQualifiedAssociations:
+			result = fmt.Sprintf("%sRender Qualified Associations: %t\n", result, val.(bool))
+		case goplantuml.RenderDependencies:
+			result = fmt.Sprintf("%sRender Dependencies: %t\n", result, val.(bool))
+		case goplantuml.HideEmptyMembers:
+			result = fmt.Sprintf("%sHide Empty Members: %t\n", result, val.(bool))
+		case goplantuml.RenderHiddenNamespaceEdges:
+			result = fmt.Sprintf("%sHidden Namespace Edges: %t\n", result, val.(bool))
+		case goplantuml.RenderPackageColors:
+			result = fmt.Sprintf("%sColor Packages: %t\n", result, val.(bool))
+		case goplantuml.RenderPackageKeyword:
+			result = fmt.Sprintf("%sPackage Keyword: %t\n", result, val.(bool))
+		case goplantuml.RenderVisibilityLegend:
+			result = fmt.Sprintf("%sRender Visibility Legend: %t\n", result, val.(bool))
 		}
 	}
 	return strings.TrimSpace(result), nil
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// getMetricsReport returns the size and coupling figures and the relationships by kind of every parsed package, and
+// the given number of largest, most depended upon and most dependent types, as a table or as JSON
+func getMetricsReport(result *goplantuml.ClassParser, format string, top int) (string, error) {
+	packages := result.PackageMetrics()
+	types := result.LargestTypes(top)
+	fanIn := result.MostDependedUponTypes(top)
+	fanOut := result.MostDependentTypes(top)
+	buffer := &bytes.Buffer{}
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(buffer)
+		encoder.SetIndent("", "  ")
+		report := struct {
+			Packages              []*goplantuml.PackageMetrics `json:"packages"`
+			LargestTypes          []*goplantuml.TypeMetrics    `json:"largestTypes"`
+			MostDependedUponTypes []*goplantuml.TypeCoupling   `json:"mostDependedUponTypes"`
+			MostDependentTypes    []*goplantuml.TypeCoupling   `json:"mostDependentTypes"`
+		}{packages, types, fanIn, fanOut}
+		if err := encoder.Encode(report); err != nil {
+			return "", err
+		}
+	case "table":
+		writer := tabwriter.NewWriter(buffer, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "PACKAGE\tSTRUCTS\tINTERFACES\tMETHODS\tCA\tCE\tINSTABILITY")
+		for _, metrics := range packages {
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%d\t%d\t%.2f\n", metrics.PackageName, metrics.Structs, metrics.Interfaces, metrics.Methods, metrics.AfferentCoupling, metrics.EfferentCoupling, metrics.Instability)
+		}
+		fmt.Fprintln(writer)
+		writeRelationshipsTable(writer, packages)
+		fmt.Fprintln(writer)
+		fmt.Fprintln(writer, "TYPE\tFIELDS\tMETHODS\tIMPLEMENTATIONS")
+		for _, metrics := range types {
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%d\n", metrics.Name, metrics.Fields, metrics.Methods, metrics.Implementations)
+		}
+		fmt.Fprintln(writer)
+		writeCouplingsTable(writer, "MOST DEPENDED UPON", fanIn)
+		fmt.Fprintln(writer)
+		writeCouplingsTable(writer, "MOST DEPENDENT", fanOut)
+		writer.Flush()
+	default:
+		return "", fmt.Errorf("unknown metrics format %s. One of table or json", format)
+	}
+	return buffer.String(), nil
+}
+
+// writeRelationshipsTable writes the number of relationships of every kind of every package as a table
+func writeRelationshipsTable(writer io.Writer, packages []*goplantuml.PackageMetrics) {
+	fmt.Fprint(writer, "PACKAGE")
+	for _, kind := range goplantuml.RelationKinds {
+		fmt.Fprintf(writer, "\t%s", strings.ToUpper(string(kind)))
+	}
+	fmt.Fprintln(writer)
+	for _, metrics := range packages {
+		fmt.Fprint(writer, metrics.PackageName)
+		for _, kind := range goplantuml.RelationKinds {
+			fmt.Fprintf(writer, "\t%d", metrics.Relationships[kind])
+		}
+		fmt.Fprintln(writer)
+	}
+}
+
+// writeCouplingsTable writes the fan-in and fan-out of the given types as a table under the given title
+func writeCouplingsTable(writer io.Writer, title string, couplings []*goplantuml.TypeCoupling) {
+	fmt.Fprintf(writer, "%s\tFAN-IN\tFAN-OUT\n", title)
+	for _, coupling := range couplings {
+		fmt.Fprintf(writer, "%s\t%d\t%d\n", coupling.Name, coupling.FanIn, coupling.FanOut)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeOutputTo writes, with the given function, to the output file or to the standard output if there is none. The
+// missing parent directories of the file are created and it is written to a temporary file renamed over it once
+// complete, so a failure never leaves a truncated output behind.
+func writeOutputTo(output string, write func(io.Writer) error) error {
+	if output == "" {
+		return write(os.Stdout)
+	}
+	dir := filepath.Dir(output)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create the output directory: %s", err.Error())
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(output)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot write the output: %s", err.Error())
+	}
+	if err := writeTempOutput(file, getOutputMode(output), write); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("cannot write the output: %s", err.Error())
+	}
+	if err := os.Rename(file.Name(), output); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("cannot write the output: %s", err.Error())
+	}
+	return nil
+}
+
+// writeTempOutput writes, with the given function, to the given temporary file and closes it with the given permissions
+func writeTempOutput(file *os.File, mode os.FileMode, write func(io.Writer) error) error {
+	writer := bufio.NewWriter(file)
+	if err := write(writer); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// getOutputMode returns the permissions of the output file if it exists, the ones os.Create gives under the usual
+// umask otherwise
+func getOutputMode(output string) os.FileMode {
+	if info, err := os.Stat(output); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
+
+// writeOutput writes the rendered text into the given file or into the standard output if output is empty, exiting
+// if it cannot be written
+func writeOutput(output string, rendered string) {
+	err := writeOutputTo(output, func(w io.Writer) error {
+		_, err := io.WriteString(w, rendered)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
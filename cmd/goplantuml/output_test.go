@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeString returns a write function for writeOutputTo writing the given text
+func writeString(text string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+}
+
+func TestWriteOutputToCreatesParentDirectories(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "docs", "diagrams", "diagram.puml")
+	if err := writeOutputTo(output, writeString("@startuml\n@enduml\n")); err != nil {
+		t.Fatalf("expected no error, got %s", err.Error())
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("expected no error reading the output, got %s", err.Error())
+	}
+	if string(content) != "@startuml\n@enduml\n" {
+		t.Errorf("expected the output to be written, got %q", string(content))
+	}
+}
+
+func TestWriteOutputToUnwritablePath(t *testing.T) {
+	dir := t.TempDir()
+	parent := filepath.Join(dir, "file")
+	if err := os.WriteFile(parent, []byte("not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := writeOutputTo(filepath.Join(parent, "diagram.puml"), writeString("@startuml\n@enduml\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot create the output directory: ") {
+		t.Errorf("expected an error creating the output directory, got %v", err)
+	}
+}
+
+func TestWriteOutputToFailureKeepsTheOutput(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "diagram.puml")
+	if err := os.WriteFile(output, []byte("previous"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := writeOutputTo(output, func(w io.Writer) error {
+		io.WriteString(w, "@startuml\n")
+		return errors.New("render failed")
+	})
+	if err == nil || err.Error() != "cannot write the output: render failed" {
+		t.Errorf("expected the write error, got %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil || string(content) != "previous" {
+		t.Errorf("expected the previous output to be left intact, got %q (%v)", string(content), err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("expected no temporary file to be left behind, got %v", names)
+	}
+}
+
+func TestWriteOutputToKeepsThePermissions(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "diagram.puml")
+	if err := os.WriteFile(output, []byte("previous"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeOutputTo(output, writeString("@startuml\n@enduml\n")); err != nil {
+		t.Fatalf("expected no error, got %s", err.Error())
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the permissions 0600 to be kept, got %o", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jfeliu007/goplantuml/plantuml"
+)
+
+// getRenderer returns the renderer used by -render. A local plantuml.jar takes precedence over a PlantUML server.
+func getRenderer(format string, jar string, server string) (plantuml.Renderer, error) {
+	if format != "svg" && format != "png" {
+		return nil, fmt.Errorf("unknown render format %s. One of svg or png", format)
+	}
+	switch {
+	case jar != "":
+		return plantuml.NewJarRenderer(jar), nil
+	case server != "":
+		return plantuml.NewServerRenderer(server), nil
+	}
+	return nil, fmt.Errorf("-render requires either -plantuml-jar or -plantuml-server")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// exportRevision writes the given directories, as they are in the given git revision, into a temporary directory
+// so the revision can be parsed without checking it out. It returns the temporary directory, which must be removed by
+// the caller, and the directories translated into it in the same order.
+func exportRevision(rev string, dirs []string, ignoredDirectories []string) (string, []string, []string, error) {
+	if len(dirs) == 0 {
+		return "", nil, nil, fmt.Errorf("no directories to export")
+	}
+	root, err := runGit(dirs[0], "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	root = strings.TrimSpace(root)
+	relativeDirs, err := getRelativeDirectories(root, dirs)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	tempDir, err := ioutil.TempDir("", "goplantuml")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	archive, err := runGit(root, append([]string{"archive", "--format=tar", rev, "--"}, relativeDirs...)...)
+	if err == nil {
+		err = extractTar(strings.NewReader(archive), tempDir)
+	}
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", nil, nil, err
+	}
+	return tempDir, moveDirectories(root, tempDir, dirs), moveDirectories(root, tempDir, ignoredDirectories), nil
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// getRelativeDirectories returns the directories relative to the root of the repository, using / as separator as
+// expected by git
+func getRelativeDirectories(root string, dirs []string) ([]string, error) {
+	result := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		relative, err := filepath.Rel(root, dir)
+		if err != nil || strings.HasPrefix(relative, "..") {
+			return nil, fmt.Errorf("%s is not inside the repository %s", dir, root)
+		}
+		result = append(result, filepath.ToSlash(relative))
+	}
+	return result, nil
+}
+
+// moveDirectories translates the directories inside root into the same directories inside tempDir
+func moveDirectories(root string, tempDir string, dirs []string) []string {
+	result := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		relative, err := filepath.Rel(root, dir)
+		if err != nil || strings.HasPrefix(relative, "..") {
+			// Directories outside of the repository (e.g. ignored ones) are kept as they are
+			result = append(result, dir)
+			continue
+		}
+		result = append(result, filepath.Join(tempDir, relative))
+	}
+	return result
+}
+
+func extractTar(reader io.Reader, destination string) error {
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(destination)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path %s in the archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeFile(target, archive)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(path string, content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, content)
+	return err
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+	"github.com/jfeliu007/goplantuml/server"
+)
+
+// The timeouts of the server. Writing the response includes parsing the requested directories, so it is given more
+// time than reading the request.
+const (
+	serveReadTimeout  = 30 * time.Second
+	serveWriteTimeout = 5 * time.Minute
+	serveIdleTimeout  = 2 * time.Minute
+)
+
+// serve runs the goplantuml serve subcommand with the given arguments
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	address := flags.String("address", "localhost:8080", "address the server listens on")
+	plantUMLServer := flags.String("plantuml-server", "", "url of the PlantUML server used to render svg diagrams (e.g. http://www.plantuml.com/plantuml). svg is not available if omitted")
+	allow := flags.String("allow", ".", "comma separated list of the directories that can be requested, including their subdirectories")
+	token := flags.String("token", "", "bearer token the requests must be authorized with. Requests are not authenticated if omitted. As it is visible in the process list, prefer GOPLANTUML_SERVE_TOKEN or -token-file")
+	tokenFile := flags.String("token-file", "", "file holding the bearer token the requests must be authorized with, instead of -token")
+	parseSubcommandFlags(flags, args)
+	diagramServer := server.NewServer(*plantUMLServer, map[goplantuml.RenderingOption]interface{}{})
+	diagramServer.AllowedDirectories = strings.Split(*allow, ",")
+	serverToken, err := getServeToken(*token, *tokenFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	diagramServer.Token = serverToken
+	fmt.Fprintf(os.Stderr, "serving diagrams on http://%s/diagram?dir=<DIR>&format=puml|dot|svg\n", *address)
+	httpServer := &http.Server{
+		Addr:         *address,
+		Handler:      diagramServer.Handler(),
+		ReadTimeout:  serveReadTimeout,
+		WriteTimeout: serveWriteTimeout,
+		IdleTimeout:  serveIdleTimeout,
+	}
+	if err := httpServer.ListenAndServe(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// getServeToken returns the bearer token of the server, given directly or read from tokenFile without its surrounding
+// white space. Only one of them can be given.
+func getServeToken(token string, tokenFile string) (string, error) {
+	if tokenFile == "" {
+		return token, nil
+	}
+	if token != "" {
+		return "", fmt.Errorf("only one of -token and -token-file can be given")
+	}
+	content, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", err
+	}
+	token = strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("the token file %s is empty", tokenFile)
+	}
+	return token, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetServeToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token.txt")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tt := []struct {
+		name             string
+		token            string
+		tokenFile        string
+		expectedToken    string
+		expectedErrorSet bool
+	}{
+		{name: "no token"},
+		{name: "token", token: "flag", expectedToken: "flag"},
+		{name: "token file", tokenFile: tokenFile, expectedToken: "secret"},
+		{name: "token and token file", token: "flag", tokenFile: tokenFile, expectedErrorSet: true},
+		{name: "empty token file", tokenFile: emptyFile, expectedErrorSet: true},
+		{name: "missing token file", tokenFile: filepath.Join(dir, "missing.txt"), expectedErrorSet: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := getServeToken(tc.token, tc.tokenFile)
+			if tc.expectedErrorSet {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err.Error())
+			}
+			if token != tc.expectedToken {
+				t.Errorf("expected the token %q, got %q", tc.expectedToken, token)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// templateFuncs are the functions available to the templates of -template on top of the text/template ones
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+}
+
+// renderTemplate executes the text/template of the given file on the parsed code. The template is given the
+// ClassParser, so it reads the model through its exported methods (e.g. {{range $name, $st := .Structs}} or
+// {{range .Relations}}).
+func renderTemplate(result *goplantuml.ClassParser, path string) (string, error) {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return "", err
+	}
+	buffer := &bytes.Buffer{}
+	if err := tmpl.Execute(buffer, result); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	goplantuml "github.com/jfeliu007/goplantuml/parser"
+)
+
+// getTrendRevisions returns the revisions to analyze, oldest first, starting with since. step is either tag, to
+// analyze every tag reachable from HEAD created after since, or commit, to analyze every first parent commit.
+func getTrendRevisions(root string, since string, step string) ([]string, error) {
+	switch step {
+	case "tag":
+		tags, err := runGit(root, "tag", "--merged", "HEAD", "--sort=creatordate")
+		if err != nil {
+			return nil, err
+		}
+		split := strings.Fields(tags)
+		for i, tag := range split {
+			if tag == since {
+				return split[i:], nil
+			}
+		}
+		return nil, fmt.Errorf("tag %s is not reachable from HEAD", since)
+	case "commit":
+		commits, err := runGit(root, "rev-list", "--reverse", "--first-parent", since+"..HEAD")
+		if err != nil {
+			return nil, err
+		}
+		return append([]string{since}, strings.Fields(commits)...), nil
+	}
+	return nil, fmt.Errorf("unknown trend step %s. One of tag or commit", step)
+}
+
+// getTrendReport parses every revision since the given one with the given options and returns their metrics as CSV
+func getTrendReport(since string, step string, options *goplantuml.ClassDiagramOptions) (string, error) {
+	if len(options.Directories) == 0 {
+		return "", fmt.Errorf("no directories to analyze")
+	}
+	root, err := runGit(options.Directories[0], "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	revisions, err := getTrendRevisions(strings.TrimSpace(root), since, step)
+	if err != nil {
+		return "", err
+	}
+	report := &goplantuml.LineStringBuilder{}
+	report.WriteLineWithDepth(0, "revision,packages,structs,interfaces,methods,relationships")
+	for _, revision := range revisions {
+		metrics, err := getRevisionMetrics(revision, *options)
+		if err != nil {
+			return "", err
+		}
+		report.WriteLineWithDepth(0, fmt.Sprintf("%s,%d,%d,%d,%d,%d", revision, metrics.Packages, metrics.Structs, metrics.Interfaces, metrics.Methods, metrics.Relationships))
+	}
+	return report.String(), nil
+}
+
+func getRevisionMetrics(revision string, options goplantuml.ClassDiagramOptions) (*goplantuml.Metrics, error) {
+	revisionDir, dirs, ignoredDirectories, err := exportRevision(revision, options.Directories, options.IgnoredDirectories)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(revisionDir)
+	options.Directories = dirs
+	options.IgnoredDirectories = ignoredDirectories
+	result, err := goplantuml.NewClassDiagramWithOptions(&options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Metrics(), nil
+}
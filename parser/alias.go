@@ -7,6 +7,9 @@ type Alias struct {
 	Name        string
 	PackageName string
 	AliasOf     string
+	// Assign is true for the true aliases declared with type A = B, and false for the defined types declared with
+	// type A B
+	Assign bool
 }
 
 func getNewAlias(name, packageName, aliasOf string) *Alias {
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -46,6 +47,64 @@ func TestAliasSlice(t *testing.T) {
 	}
 	aliasSlice.Swap(0, 1)
 	if aliasSlice[0].AliasOf != "A" {
-		t.Errorf("TestAliasSlice: Expected aliasSlice[0].AliasOf to be 'A' got %s", aliasSlice[0])
+		t.Errorf("TestAliasSlice: Expected aliasSlice[0].AliasOf to be 'A' got %s", aliasSlice[0].AliasOf)
+	}
+}
+
+func TestTypeAliasAssign(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/typealiases"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if !parser.allAliases["typealiases.Settings"].Assign {
+		t.Error("TestTypeAliasAssign: expected typealiases.Settings to be a true alias")
+	}
+	if parser.allAliases["typealiases.Options"].Assign {
+		t.Error("TestTypeAliasAssign: expected typealiases.Options not to be a true alias")
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderAliases: true})
+	rendered := parser.Render()
+	for _, expected := range []string{
+		"class typealiases.Settings << (T, #FF7700) alias >>  {",
+		"class typealiases.Options << (T, #FF7700) >>  {",
+		`"typealiases.Settings" ..> "typealiases.Config"`,
+		`"typealiases.Config" #.. "typealiases.Options"`,
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestTypeAliasAssign: expected the render to contain %s, got\n%s", expected, rendered)
+		}
+	}
+}
+
+func TestDefinedCollectionTypeAggregations(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"sessions/sessions.go": `package sessions
+
+type UserID string
+
+type Session struct{}
+
+type Sessions map[UserID]*Session
+
+type Names []string
+`}, &ClassDiagramOptions{RenderingOptions: map[RenderingOption]interface{}{RenderAggregations: true}})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	expected := map[string]struct{}{"sessions.UserID": {}, "sessions.Session": {}}
+	if result := parser.structure["sessions"]["sessions.Sessions"].Aggregations; !reflect.DeepEqual(result, expected) {
+		t.Errorf("TestDefinedCollectionTypeAggregations: expected the aggregations %v, got %v", expected, result)
+	}
+	if result := parser.structure["sessions"]["sessions.UserID"].Aggregations; len(result) != 0 {
+		t.Errorf("TestDefinedCollectionTypeAggregations: expected no aggregations of UserID, got %v", result)
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{
+		`"sessions.Sessions" o-- "sessions.Session"`,
+		`"sessions.Sessions" o-- "sessions.UserID"`,
+		`class "<font color=blue>map</font>[UserID]*Session" as`,
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestDefinedCollectionTypeAggregations: expected the render to contain %s, got\n%s", expected, rendered)
+		}
 	}
 }
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// expandAnonymousStruct replaces the anonymous struct type of the given field, also through pointers and slices, by a
+// structure named after the one declaring the field and the field (e.g. Outer_field) that composes it. The
+// anonymous structs of its own fields are expanded too.
+func (p *ClassParser) expandAnonymousStruct(st *Struct, typeName string, field *Field, fieldType ast.Expr) {
+	structType, prefix := getAnonymousStruct(fieldType)
+	if structType == nil || len(structType.Fields.List) == 0 {
+		return
+	}
+	name := fmt.Sprintf("%s_%s", typeName, field.Name)
+	handleGenDecStructType(p, name, structType)
+	anonymous := p.getOrCreateStruct(name)
+	anonymous.Type = "class"
+	anonymous.Test = p.parsingTestFile
+	anonymous.FileName = p.parsingFileName
+	anonymous.Line = p.getLine(structType.Pos())
+	p.allStructs[fmt.Sprintf("%s.%s", p.currentPackageName, name)] = struct{}{}
+	field.Type = prefix + name
+	st.AddToComposition(name)
+}
+
+// getAnonymousStruct returns the anonymous struct type of the given field type, if it is one or a pointer or slice of
+// one, and the prefix of the field type (e.g. []* for []*struct{...})
+func getAnonymousStruct(fieldType ast.Expr) (*ast.StructType, string) {
+	switch v := fieldType.(type) {
+	case *ast.StructType:
+		return v, ""
+	case *ast.StarExpr:
+		structType, prefix := getAnonymousStruct(v.X)
+		return structType, "*" + prefix
+	case *ast.ArrayType:
+		structType, prefix := getAnonymousStruct(v.Elt)
+		return structType, "[]" + prefix
+	}
+	return nil, ""
+}
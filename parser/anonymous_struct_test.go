@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const anonymousStructsSource = `package config
+
+type Config struct {
+	Name     string
+	Database struct {
+		Host string
+		Pool *struct {
+			Size int
+		}
+	}
+	Routes []struct {
+		Path string
+	}
+	done struct{}
+}
+`
+
+func TestExpandAnonymousStructs(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"config/config.go": anonymousStructsSource}, &ClassDiagramOptions{
+		ExpandAnonymousStructs: true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	tt := []struct {
+		Name   string
+		Fields []string
+	}{
+		{Name: "config.Config", Fields: []string{"Name string", "Database Config_Database", "Routes []Config_Routes", "done <font color=blue>struct</font>{}"}},
+		{Name: "config.Config_Database", Fields: []string{"Host string", "Pool *Config_Database_Pool"}},
+		{Name: "config.Config_Database_Pool", Fields: []string{"Size int"}},
+		{Name: "config.Config_Routes", Fields: []string{"Path string"}},
+	}
+	for _, tc := range tt {
+		st, ok := structs[tc.Name]
+		if !ok {
+			t.Errorf("TestExpandAnonymousStructs: expected the structure %s", tc.Name)
+			continue
+		}
+		fields := []string{}
+		for _, field := range st.Fields {
+			fields = append(fields, field.Name+" "+field.Type)
+		}
+		if strings.Join(fields, "; ") != strings.Join(tc.Fields, "; ") {
+			t.Errorf("TestExpandAnonymousStructs: expected the fields %v of %s, got %v", tc.Fields, tc.Name, fields)
+		}
+	}
+	if structs["config.Config_Database"].Line != 5 {
+		t.Errorf("TestExpandAnonymousStructs: expected Config_Database at line 5, got %d", structs["config.Config_Database"].Line)
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{
+		`class Config_Database << (S,Aquamarine) >> {`,
+		`"config.Config_Database" *-- "config.Config"`,
+		`"config.Config_Database_Pool" *-- "config.Config_Database"`,
+		`"config.Config_Routes" *-- "config.Config"`,
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestExpandAnonymousStructs: expected %s in %s", expected, rendered)
+		}
+	}
+}
+
+func TestAnonymousStructsNotExpanded(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"config/config.go": anonymousStructsSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if _, ok := parser.Structs()["config.Config_Database"]; ok {
+		t.Errorf("TestAnonymousStructsNotExpanded: expected the anonymous structs to be inlined")
+	}
+}
@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ArchitectureVersion is the version of the architecture files ReadArchitecture reads
+const ArchitectureVersion = 1
+
+// Layer is a group of packages of an Architecture
+type Layer struct {
+	// Name identifies the layer in the DependsOn of the other ones (e.g. usecase)
+	Name string `json:"name"`
+	// Packages are the patterns, with the path.Match syntax, of the names of the packages of the layer (e.g.
+	// *repository). A package belongs to the first layer matching it
+	Packages []string `json:"packages"`
+	// DependsOn are the names of the layers the packages of this one may depend on
+	DependsOn []string `json:"dependsOn"`
+}
+
+// Architecture declares the dependencies allowed between layers of packages (e.g. controller depends on usecase
+// which depends on repository). The packages matching no layer are not checked.
+type Architecture struct {
+	// Version is the version of the format of the file. 0 (omitted) stands for ArchitectureVersion
+	Version int      `json:"version"`
+	Layers  []*Layer `json:"layers"`
+}
+
+// LayerViolation is a dependency between packages that the Architecture does not allow
+type LayerViolation struct {
+	Package         string
+	Layer           string
+	Dependency      string
+	DependencyLayer string
+}
+
+// String returns the violation as controller/handlers (controller) depends on repository/db (repository)
+func (v *LayerViolation) String() string {
+	return fmt.Sprintf("%s (%s) depends on %s (%s)", v.Package, v.Layer, v.Dependency, v.DependencyLayer)
+}
+
+// ReadArchitecture decodes an architecture written as JSON, e.g.
+// {"layers": [{"name": "controller", "packages": ["*controller"], "dependsOn": ["usecase"]}, ...]}
+// and checks its version, that its patterns are valid and its layers declared. The unknown keys (e.g. a misspelled
+// dependOn) are rejected, listing the allowed ones, rather than silently leaving the layer without dependencies.
+func ReadArchitecture(r io.Reader) (*Architecture, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid architecture: %s", err.Error())
+	}
+	architecture := &Architecture{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(architecture); err != nil {
+		if unknownKeyErr := getUnknownArchitectureKey(content); unknownKeyErr != nil {
+			return nil, unknownKeyErr
+		}
+		return nil, fmt.Errorf("invalid architecture: %s", err.Error())
+	}
+	if architecture.Version != 0 && architecture.Version != ArchitectureVersion {
+		return nil, fmt.Errorf("invalid architecture: unsupported version %d, the supported version is %d", architecture.Version, ArchitectureVersion)
+	}
+	layers := map[string]struct{}{}
+	for _, layer := range architecture.Layers {
+		if _, ok := layers[layer.Name]; ok || layer.Name == "" {
+			return nil, fmt.Errorf("invalid architecture: duplicated or empty layer name %q", layer.Name)
+		}
+		layers[layer.Name] = struct{}{}
+		for _, pattern := range layer.Packages {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid architecture: layer %s: invalid package pattern %q", layer.Name, pattern)
+			}
+		}
+	}
+	for _, layer := range architecture.Layers {
+		for _, dependency := range layer.DependsOn {
+			if _, ok := layers[dependency]; !ok {
+				return nil, fmt.Errorf("invalid architecture: layer %s depends on the undeclared layer %s", layer.Name, dependency)
+			}
+		}
+	}
+	return architecture, nil
+}
+
+// getUnknownArchitectureKey returns an error naming the first unknown key of the architecture and the keys allowed
+// where it was found, nil if there is none or the content is not made of the expected objects
+func getUnknownArchitectureKey(content []byte) error {
+	var architecture map[string]json.RawMessage
+	if json.Unmarshal(content, &architecture) != nil {
+		return nil
+	}
+	if key := getUnknownKey(architecture, Architecture{}); key != "" {
+		return fmt.Errorf("invalid architecture: unknown key %q, the allowed keys are %s", key, strings.Join(getJSONKeys(Architecture{}), ", "))
+	}
+	var layers []map[string]json.RawMessage
+	if json.Unmarshal(architecture["layers"], &layers) != nil {
+		return nil
+	}
+	for i, layer := range layers {
+		if key := getUnknownKey(layer, Layer{}); key != "" {
+			var name string
+			json.Unmarshal(layer["name"], &name)
+			return fmt.Errorf("invalid architecture: layer %d (%s): unknown key %q, the allowed keys are %s", i+1, name, key, strings.Join(getJSONKeys(Layer{}), ", "))
+		}
+	}
+	return nil
+}
+
+// getUnknownKey returns the first key of the object, in alphabetical order, that is not a field of the given struct,
+// an empty string if there is none
+func getUnknownKey(object map[string]json.RawMessage, v interface{}) string {
+	allowed := map[string]struct{}{}
+	for _, key := range getJSONKeys(v) {
+		allowed[strings.ToLower(key)] = struct{}{}
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// encoding/json matches the keys case insensitively
+		if _, ok := allowed[strings.ToLower(key)]; !ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// getJSONKeys returns the json keys of the fields of the given struct, in declaration order
+func getJSONKeys(v interface{}) []string {
+	structType := reflect.TypeOf(v)
+	keys := make([]string, 0, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		keys = append(keys, strings.Split(structType.Field(i).Tag.Get("json"), ",")[0])
+	}
+	return keys
+}
+
+// getLayer returns the first layer with a pattern matching the given package, nil if there is none
+func (a *Architecture) getLayer(pack string) *Layer {
+	for _, layer := range a.Layers {
+		for _, pattern := range layer.Packages {
+			if matched, _ := path.Match(pattern, pack); matched {
+				return layer
+			}
+		}
+	}
+	return nil
+}
+
+// allows returns true if the packages of the layer from may depend on the ones of the layer to
+func (a *Architecture) allows(from *Layer, to *Layer) bool {
+	if from == to {
+		return true
+	}
+	for _, dependency := range from.DependsOn {
+		if dependency == to.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// LayerViolations returns the dependencies of PackageDependencies between packages of layers of the given
+// architecture that it does not allow, sorted by package and dependency
+func (p *ClassParser) LayerViolations(architecture *Architecture) []*LayerViolation {
+	violations := []*LayerViolation{}
+	dependencies := p.PackageDependencies()
+	packages := make([]string, 0, len(dependencies))
+	for pack := range dependencies {
+		packages = append(packages, pack)
+	}
+	sort.Strings(packages)
+	for _, pack := range packages {
+		layer := architecture.getLayer(pack)
+		if layer == nil {
+			continue
+		}
+		for _, dependency := range dependencies[pack] {
+			dependencyLayer := architecture.getLayer(dependency)
+			if dependencyLayer == nil || architecture.allows(layer, dependencyLayer) {
+				continue
+			}
+			violations = append(violations, &LayerViolation{
+				Package:         pack,
+				Layer:           layer.Name,
+				Dependency:      dependency,
+				DependencyLayer: dependencyLayer.Name,
+			})
+		}
+	}
+	return violations
+}
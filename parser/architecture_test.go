@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadArchitecture(t *testing.T) {
+	tt := []struct {
+		name          string
+		input         string
+		expectedError string
+	}{
+		{
+			name:  "valid",
+			input: `{"layers": [{"name": "controller", "packages": ["*controller"], "dependsOn": ["usecase"]}, {"name": "usecase", "packages": ["*usecase"]}]}`,
+		},
+		{
+			name:          "invalid json",
+			input:         `{"layers": [`,
+			expectedError: "invalid architecture: unexpected EOF",
+		},
+		{
+			name:          "unknown key",
+			input:         `{"layers": [{"name": "controller", "dependOn": ["usecase"]}]}`,
+			expectedError: `invalid architecture: layer 1 (controller): unknown key "dependOn", the allowed keys are name, packages, dependsOn`,
+		},
+		{
+			name:          "unknown top level key",
+			input:         `{"layer": []}`,
+			expectedError: `invalid architecture: unknown key "layer", the allowed keys are version, layers`,
+		},
+		{
+			name:  "supported version",
+			input: `{"version": 1, "layers": [{"name": "usecase"}]}`,
+		},
+		{
+			name:          "unsupported version",
+			input:         `{"version": 2, "layers": [{"name": "usecase"}]}`,
+			expectedError: "invalid architecture: unsupported version 2, the supported version is 1",
+		},
+		{
+			name:          "undeclared layer",
+			input:         `{"layers": [{"name": "controller", "dependsOn": ["usecase"]}]}`,
+			expectedError: "invalid architecture: layer controller depends on the undeclared layer usecase",
+		},
+		{
+			name:          "duplicated layer",
+			input:         `{"layers": [{"name": "usecase"}, {"name": "usecase"}]}`,
+			expectedError: `invalid architecture: duplicated or empty layer name "usecase"`,
+		},
+		{
+			name:          "invalid pattern",
+			input:         `{"layers": [{"name": "usecase", "packages": ["[usecase"]}]}`,
+			expectedError: `invalid architecture: layer usecase: invalid package pattern "[usecase"`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadArchitecture(strings.NewReader(tc.input))
+			if tc.expectedError == "" && err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			if tc.expectedError != "" && (err == nil || err.Error() != tc.expectedError) {
+				t.Errorf("expected error %s, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLayerViolations(t *testing.T) {
+	parser := getEmptyParser("webcontroller")
+	parser.structure["webcontroller"]["Handler"] = &Struct{
+		PackageName: "webcontroller",
+		Type:        "class",
+		Composition: map[string]struct{}{"userusecase.Service": {}, "sqlrepository.Store": {}, "log.Logger": {}},
+	}
+	parser.structure["userusecase"] = map[string]*Struct{
+		"Service": {PackageName: "userusecase", Type: "class", Composition: map[string]struct{}{"sqlrepository.Store": {}}},
+	}
+	parser.structure["sqlrepository"] = map[string]*Struct{
+		"Store": {PackageName: "sqlrepository", Type: "class", Composition: map[string]struct{}{"userusecase.Service": {}}},
+	}
+	architecture := &Architecture{
+		Layers: []*Layer{
+			{Name: "controller", Packages: []string{"*controller"}, DependsOn: []string{"usecase"}},
+			{Name: "usecase", Packages: []string{"*usecase"}, DependsOn: []string{"repository"}},
+			{Name: "repository", Packages: []string{"*repository"}},
+		},
+	}
+	expected := []*LayerViolation{
+		{Package: "sqlrepository", Layer: "repository", Dependency: "userusecase", DependencyLayer: "usecase"},
+		{Package: "webcontroller", Layer: "controller", Dependency: "sqlrepository", DependencyLayer: "repository"},
+	}
+	violations := parser.LayerViolations(architecture)
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("TestLayerViolations: expected %v, got %v", expected, violations)
+	}
+	if s := violations[0].String(); s != "sqlrepository (repository) depends on userusecase (usecase)" {
+		t.Errorf("TestLayerViolations: unexpected violation %s", s)
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"go/ast"
+)
+
+// setLastMethodAsync records whether the method added last to the given structure, if it was added by AddMethod,
+// launches goroutines in the given body
+func setLastMethodAsync(st *Struct, methods int, body *ast.BlockStmt) {
+	if len(st.Functions) > methods && body != nil {
+		st.Functions[len(st.Functions)-1].Async = launchesGoroutines(body)
+	}
+}
+
+// launchesGoroutines returns true if the given body has a go statement, including in its function literals
+func launchesGoroutines(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		if _, ok := node.(*ast.GoStmt); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// getAsyncStereotype returns the stereotype rendered next to the given method when it launches goroutines
+func (p *ClassParser) getAsyncStereotype(method *Function) string {
+	if !p.renderingOptions.Async || !method.Async {
+		return ""
+	}
+	return "<<async>>"
+}
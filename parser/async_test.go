@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const asyncSource = `package server
+
+type Server struct{}
+
+func (s *Server) Start() {
+	go s.serve()
+}
+
+func (s *Server) Stop() {
+	cancel := func() {
+		go s.drain()
+	}
+	cancel()
+}
+
+func (s *Server) serve() {}
+
+func (s *Server) drain() {}
+`
+
+func TestFindGoroutines(t *testing.T) {
+	tt := []struct {
+		Name           string
+		FindGoroutines bool
+		Expected       map[string]bool
+	}{
+		{
+			Name:     "not inspected",
+			Expected: map[string]bool{"Start": false, "Stop": false, "serve": false},
+		},
+		{
+			Name:           "inspected",
+			FindGoroutines: true,
+			Expected:       map[string]bool{"Start": true, "Stop": true, "serve": false},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramFromSources(map[string]string{"server/server.go": asyncSource}, &ClassDiagramOptions{
+				FindGoroutines: tc.FindGoroutines,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			for _, method := range parser.Structs()["server.Server"].Functions {
+				if expected, ok := tc.Expected[method.Name]; ok && method.Async != expected {
+					t.Errorf("expected %s to be async %t, got %t", method.Name, expected, method.Async)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderAsync(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"server/server.go": asyncSource}, &ClassDiagramOptions{
+		FindGoroutines:   true,
+		RenderingOptions: map[RenderingOption]interface{}{RenderAsync: true, RenderPrivateMembers: true},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{"+ Start() <<async>>\n", "+ Stop() <<async>>\n", "- serve()\n"} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestRenderAsync: expected %q in %s", expected, rendered)
+		}
+	}
+}
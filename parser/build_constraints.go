@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// getBuildConstraintsFilter returns the filter of the files of the given directory that match the build constraints
+// of the parser, or nil to parse all of them. The files whose constraints cannot be evaluated are excluded and
+// reported in the diagnostics. The files are read from the file system of the parser, unless the build context has
+// its own OpenFile.
+func (p *ClassParser) getBuildConstraintsFilter(directoryPath string) func(os.FileInfo) bool {
+	if p.buildContext == nil {
+		return nil
+	}
+	buildContext := *p.buildContext
+	if buildContext.OpenFile == nil {
+		buildContext.OpenFile = func(path string) (io.ReadCloser, error) {
+			return p.fileSystem.Open(path)
+		}
+	}
+	return func(info os.FileInfo) bool {
+		match, err := buildContext.MatchFile(directoryPath, info.Name())
+		if err != nil {
+			p.addDiagnostic(filepath.Join(directoryPath, info.Name()), "excluded, %s", err.Error())
+		}
+		return err == nil && match
+	}
+}
+
+// addExcludedDirectoryDiagnostic reports the given directory if it has go files to parse but all of them were
+// excluded by the build constraints, in which case it is skipped
+func (p *ClassParser) addExcludedDirectoryDiagnostic(directoryPath string, parsedFiles int) {
+	if p.buildContext == nil || parsedFiles > 0 {
+		return
+	}
+	goFiles, _ := afero.Glob(p.fileSystem, filepath.Join(directoryPath, "*.go"))
+	for _, goFile := range goFiles {
+		if p.includeTests || !strings.HasSuffix(goFile, "_test.go") {
+			p.addDiagnostic(directoryPath, "skipped, the build constraints exclude all its go files")
+			return
+		}
+	}
+}
+
+func (p *ClassParser) addDiagnostic(path string, format string, a ...interface{}) {
+	p.diagnostics = append(p.diagnostics, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, a...)))
+}
+
+// Diagnostics returns the problems found while parsing that did not prevent the diagram from being generated (e.g. a
+// directory skipped because the build constraints exclude all its go files), sorted by path
+func (p *ClassParser) Diagnostics() []string {
+	diagnostics := append([]string{}, p.diagnostics...)
+	sort.Strings(diagnostics)
+	return diagnostics
+}
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"go/build"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestBuildConstraints(t *testing.T) {
+	getContext := func(goos string, tags ...string) *build.Context {
+		context := build.Default
+		context.GOOS = goos
+		context.GOARCH = "amd64"
+		context.BuildTags = tags
+		return &context
+	}
+	tt := []struct {
+		Name         string
+		BuildContext *build.Context
+		Methods      int
+		ReturnValue  string
+		Debugger     bool
+	}{
+		{
+			Name:     "every file",
+			Methods:  2,
+			Debugger: true,
+		},
+		{
+			Name:         "linux",
+			BuildContext: getContext("linux"),
+			Methods:      1,
+			ReturnValue:  "string",
+			Debugger:     false,
+		},
+		{
+			Name:         "windows with tags",
+			BuildContext: getContext("windows", "debug"),
+			Methods:      1,
+			ReturnValue:  "[]string",
+			Debugger:     true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:         afero.NewOsFs(),
+				Directories:        []string{"../testingsupport/buildconstraints"},
+				IgnoredDirectories: []string{},
+				RenderingOptions:   map[RenderingOption]interface{}{},
+				BuildContext:       tc.BuildContext,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			structs := parser.Structs()
+			store, ok := structs["buildconstraints.Store"]
+			if !ok {
+				t.Fatalf("expected buildconstraints.Store to be parsed, got %v", structs)
+			}
+			if len(store.Functions) != tc.Methods {
+				t.Fatalf("expected %d Path methods, got %d", tc.Methods, len(store.Functions))
+			}
+			if tc.ReturnValue != "" && store.Functions[0].FullNameReturnValues[0] != tc.ReturnValue {
+				t.Errorf("expected Path to return %s, got %s", tc.ReturnValue, store.Functions[0].FullNameReturnValues[0])
+			}
+			if _, ok := structs["buildconstraints.Debugger"]; ok != tc.Debugger {
+				t.Errorf("expected buildconstraints.Debugger to be parsed to be %t, got %t", tc.Debugger, ok)
+			}
+		})
+	}
+}
+
+func TestBuildConstraintsDiagnostics(t *testing.T) {
+	context := build.Default
+	context.GOOS = "linux"
+	context.GOARCH = "amd64"
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/buildconstraints"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		Recursive:          true,
+		BuildContext:       &context,
+	})
+	if err != nil {
+		t.Fatalf("TestBuildConstraintsDiagnostics: expected no error but got %s", err.Error())
+	}
+	if _, ok := parser.structure["windows"]; ok {
+		t.Errorf("TestBuildConstraintsDiagnostics: expected the windows package to be skipped")
+	}
+	if result := parser.Render(); strings.Contains(result, "namespace windows") {
+		t.Errorf("TestBuildConstraintsDiagnostics: expected no windows namespace, got %s", result)
+	}
+	expected := []string{filepath.Join("../testingsupport/buildconstraints", "windows") + ": skipped, the build constraints exclude all its go files"}
+	if diagnostics := parser.Diagnostics(); !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("TestBuildConstraintsDiagnostics: expected %v, got %v", expected, diagnostics)
+	}
+}
+
+func TestInvalidBuildConstraints(t *testing.T) {
+	directory := t.TempDir()
+	files := map[string]string{
+		"valid.go":   "package invalid\n\ntype Valid struct{}\n",
+		"invalid.go": "//go:build linux &&\n\npackage invalid\n\ntype Invalid struct{}\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatalf("TestInvalidBuildConstraints: expected no error writing %s but got %s", name, err.Error())
+		}
+	}
+	context := build.Default
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{directory},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		BuildContext:       &context,
+	})
+	if err != nil {
+		t.Fatalf("TestInvalidBuildConstraints: expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	if _, ok := structs["invalid.Valid"]; !ok {
+		t.Errorf("TestInvalidBuildConstraints: expected invalid.Valid to be parsed, got %v", structs)
+	}
+	if _, ok := structs["invalid.Invalid"]; ok {
+		t.Errorf("TestInvalidBuildConstraints: expected invalid.Invalid to be excluded")
+	}
+	diagnostics := parser.Diagnostics()
+	if len(diagnostics) != 1 || !strings.HasPrefix(diagnostics[0], filepath.Join(directory, "invalid.go")+": excluded, ") {
+		t.Errorf("TestInvalidBuildConstraints: expected invalid.go to be reported, got %v", diagnostics)
+	}
+}
+
+func TestBuildConstraintsFileSystem(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/project/debug.go", []byte("//go:build debug\n\npackage project\n\ntype Debugger struct{}\n"), 0644)
+	afero.WriteFile(fs, "/project/release.go", []byte("//go:build !debug\n\npackage project\n\ntype Release struct{}\n"), 0644)
+	context := build.Default
+	context.BuildTags = []string{"debug"}
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:       fs,
+		Directories:      []string{"/project"},
+		RenderingOptions: map[RenderingOption]interface{}{},
+		BuildContext:     &context,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	if _, ok := structs["project.Debugger"]; !ok {
+		t.Errorf("TestBuildConstraintsFileSystem: expected project.Debugger to be parsed, got %v", structs)
+	}
+	if _, ok := structs["project.Release"]; ok {
+		t.Errorf("TestBuildConstraintsFileSystem: expected project.Release to be excluded, got %v", structs)
+	}
+	if diagnostics := parser.Diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("TestBuildConstraintsFileSystem: expected no diagnostics, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,55 @@
+package parser
+
+import "fmt"
+
+// RenderC4 returns a C4-PlantUML component diagram of the parsed packages. Every package with structures is a
+// component and the relationships of its structures with the types of other packages (see PackageDependencies) are
+// relationships between the components, only the relationships of the kinds of PackageRelationKinds if it is set. The
+// packages that were not parsed are rendered as external components.
+func (p *ClassParser) RenderC4() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	str := &LineStringBuilder{}
+	str.WriteLineWithDepth(0, "@startuml")
+	str.WriteLineWithDepth(0, "!include <C4/C4_Component>")
+	if p.renderingOptions.Title != "" {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`title %s`, p.renderingOptions.Title))
+	}
+	packages := p.Packages()
+	parsed := map[string]struct{}{}
+	for _, pack := range packages {
+		parsed[pack] = struct{}{}
+		description := fmt.Sprintf("%d types", len(p.structure[pack]))
+		if len(p.structure[pack]) == 1 {
+			description = "1 type"
+		}
+		str.WriteLineWithDepth(0, fmt.Sprintf(`Component(%s, "%s", "Go package", "%s")`, getPlantUMLAlias(pack), pack, description))
+	}
+	kinds := p.renderingOptions.PackageRelationKinds
+	if len(kinds) == 0 {
+		kinds = defaultPackageRelationKinds
+	}
+	dependencies := p.getPackageDependencyCounts(kinds)
+	external := map[string]struct{}{}
+	for _, pack := range packages {
+		for dependency := range dependencies[pack] {
+			if _, ok := parsed[dependency]; !ok {
+				external[dependency] = struct{}{}
+			}
+		}
+	}
+	for _, pack := range getSortedKeys(external) {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`Component_Ext(%s, "%s", "Go package")`, getPlantUMLAlias(pack), pack))
+	}
+	for _, pack := range packages {
+		for _, dependency := range getSortedDependencies(dependencies[pack]) {
+			label := "uses"
+			if p.renderingOptions.PackageWeights {
+				label = fmt.Sprintf("uses (%d)", dependencies[pack][dependency])
+			}
+			str.WriteLineWithDepth(0, fmt.Sprintf(`Rel(%s, %s, "%s")`, getPlantUMLAlias(pack), getPlantUMLAlias(dependency), label))
+		}
+	}
+	str.WriteLineWithDepth(0, "@enduml")
+	return str.String()
+}
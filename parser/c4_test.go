@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderC4(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder", "../testingsupport/subfolder2", "../testingsupport/subfolder3"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderTitle: "Packages"})
+	expected := `@startuml
+!include <C4/C4_Component>
+title Packages
+Component(subfolder, "subfolder", "Go package", "2 types")
+Component(subfolder2, "subfolder2", "Go package", "1 type")
+Component(subfolder3, "subfolder3", "Go package", "1 type")
+Rel(subfolder2, subfolder3, "uses")
+@enduml
+`
+	if rendered := parser.RenderC4(); rendered != expected {
+		t.Errorf("TestRenderC4: expected\n%s\ngot\n%s", expected, rendered)
+	}
+}
+
+func TestRenderC4ExternalPackages(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/externaltypes"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	expected := `@startuml
+!include <C4/C4_Component>
+Component(externaltypes, "externaltypes", "Go package", "1 type")
+Component_Ext(io, "io", "Go package")
+Component_Ext(time, "time", "Go package")
+Rel(externaltypes, io, "uses")
+Rel(externaltypes, time, "uses")
+@enduml
+`
+	if rendered := parser.RenderC4(); rendered != expected {
+		t.Errorf("TestRenderC4ExternalPackages: expected\n%s\ngot\n%s", expected, rendered)
+	}
+}
+
+func TestRenderC4PackageRelations(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{
+		"store/store.go": "package store\n\ntype Reader interface{}\n\ntype Item struct{}\n\ntype Base struct{}\n",
+		"app/app.go": `package app
+
+import "store"
+
+type Service struct {
+	store.Base
+	Item  store.Item
+	items []store.Item
+}
+
+func (s *Service) Read() {}
+`,
+	}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	tt := []struct {
+		name     string
+		options  map[RenderingOption]interface{}
+		expected string
+	}{
+		{
+			name:     "weights",
+			options:  map[RenderingOption]interface{}{RenderPackageWeights: true},
+			expected: `Rel(app, store, "uses (3)")`,
+		},
+		{
+			name:     "kinds",
+			options:  map[RenderingOption]interface{}{PackageRelationKinds: []RelationKind{RelationComposition, RelationAggregation}},
+			expected: `Rel(app, store, "uses (2)")`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := parser.SetRenderingOptions(tc.options); err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			if rendered := parser.RenderC4(); !strings.Contains(rendered, tc.expected) {
+				t.Errorf("expected %s in\n%s", tc.expected, rendered)
+			}
+		})
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{PackageRelationKinds: []RelationKind{RelationExtends}}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if rendered := parser.RenderC4(); strings.Contains(rendered, "Rel(") {
+		t.Errorf("expected no relationship between the packages in\n%s", rendered)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{PackageRelationKinds: []RelationKind{"uses"}}); err == nil || err.Error() != "Invalid relation kind uses" {
+		t.Errorf("expected an invalid relation kind error, got %v", err)
+	}
+}
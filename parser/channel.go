@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"unicode"
+)
+
+const (
+	// channelSend is the direction of the channels a structure sends values to (e.g. chan<- Job)
+	channelSend = "send"
+	// channelReceive is the direction of the channels a structure receives values from (e.g. <-chan Job)
+	channelReceive = "receive"
+)
+
+// defaultChannelArrow is the arrow of the channel relationships when the ChannelArrow rendering option is empty
+const defaultChannelArrow = "-->"
+
+// addChannels records the directions of a channel field (e.g. receive for <-chan Job) for the types of its values.
+// The bidirectional channels are used in both directions.
+func (st *Struct) addChannels(field *Field, fieldType ast.Expr, aliases map[string]string) {
+	chanType, ok := fieldType.(*ast.ChanType)
+	if !ok {
+		return
+	}
+	directions := []string{}
+	if chanType.Dir&ast.SEND != 0 {
+		directions = append(directions, channelSend)
+	}
+	if chanType.Dir&ast.RECV != 0 {
+		directions = append(directions, channelReceive)
+	}
+	channels := &st.Channels
+	if !unicode.IsUpper(rune(field.Name[0])) {
+		channels = &st.PrivateChannels
+	}
+	if *channels == nil {
+		*channels = map[string]map[string]struct{}{}
+	}
+	_, valueTypes := getFieldType(chanType.Value, aliases)
+	for _, t := range valueTypes {
+		t = replacePackageConstant(t, st.PackageName)
+		if _, ok := (*channels)[t]; !ok {
+			(*channels)[t] = map[string]struct{}{}
+		}
+		for _, direction := range directions {
+			(*channels)[t][direction] = struct{}{}
+		}
+	}
+}
+
+// getChannelLabel returns the label of the channel relationship rendered instead of the aggregation of target (e.g.
+// sends/receives) or an empty string if target is not carried by a channel field or channels are not rendered
+func (p *ClassParser) getChannelLabel(structure *Struct, target string) string {
+	if !p.renderingOptions.Channels {
+		return ""
+	}
+	directions := structure.Channels[target]
+	if p.renderingOptions.AggregatePrivateMembers {
+		directions = mergeSets(directions, structure.PrivateChannels[target])
+	}
+	_, sends := directions[channelSend]
+	_, receives := directions[channelReceive]
+	switch {
+	case sends && receives:
+		return "sends/receives"
+	case sends:
+		return "sends"
+	case receives:
+		return "receives"
+	}
+	return ""
+}
+
+// renderChannel renders the channel relationship of the given structure with target, labeled with its directions
+func (p *ClassParser) renderChannel(fullName string, target string, label string, aggregations *LineStringBuilder) {
+	arrow := p.renderingOptions.ChannelArrow
+	if arrow == "" {
+		arrow = defaultChannelArrow
+	}
+	aggregations.WriteLineWithDepth(0, fmt.Sprintf(`"%s" %s "%s" : %s`, fullName, p.getCycleArrow(arrow, fullName, target), target, label))
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const channelsSource = `package pipeline
+
+type Job struct{}
+
+type Result struct{}
+
+type Event struct{}
+
+type Worker struct {
+	Jobs    <-chan Job
+	Results chan<- *Result
+	events  chan Event
+	Last    *Result
+}
+`
+
+func TestAddChannels(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"pipeline/pipeline.go": channelsSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	worker := parser.Structs()["pipeline.Worker"]
+	expected := map[string]map[string]struct{}{
+		"pipeline.Job":    {channelReceive: {}},
+		"pipeline.Result": {channelSend: {}},
+	}
+	if !reflect.DeepEqual(worker.Channels, expected) {
+		t.Errorf("TestAddChannels: expected the channels %v, got %v", expected, worker.Channels)
+	}
+	expected = map[string]map[string]struct{}{
+		"pipeline.Event": {channelSend: {}, channelReceive: {}},
+	}
+	if !reflect.DeepEqual(worker.PrivateChannels, expected) {
+		t.Errorf("TestAddChannels: expected the private channels %v, got %v", expected, worker.PrivateChannels)
+	}
+}
+
+func TestRenderChannels(t *testing.T) {
+	tt := []struct {
+		Name       string
+		Options    map[RenderingOption]interface{}
+		Expected   []string
+		Unexpected []string
+	}{
+		{
+			Name:       "aggregations",
+			Options:    map[RenderingOption]interface{}{RenderAggregations: true},
+			Expected:   []string{`"pipeline.Worker" o-- "pipeline.Job"`},
+			Unexpected: []string{"receives"},
+		},
+		{
+			Name:    "channels",
+			Options: map[RenderingOption]interface{}{RenderAggregations: true, RenderChannels: true},
+			Expected: []string{
+				`"pipeline.Worker" --> "pipeline.Job" : receives`,
+				`"pipeline.Worker" --> "pipeline.Result" : sends`,
+			},
+			Unexpected: []string{`o-- "pipeline.Job"`, "pipeline.Event"},
+		},
+		{
+			Name:     "private channels",
+			Options:  map[RenderingOption]interface{}{RenderAggregations: true, AggregatePrivateMembers: true, RenderChannels: true},
+			Expected: []string{`"pipeline.Worker" --> "pipeline.Event" : sends/receives`},
+		},
+		{
+			Name:     "arrow",
+			Options:  map[RenderingOption]interface{}{RenderAggregations: true, RenderChannels: true, ChannelArrow: "..>"},
+			Expected: []string{`"pipeline.Worker" ..> "pipeline.Job" : receives`},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramFromSources(map[string]string{"pipeline/pipeline.go": channelsSource}, &ClassDiagramOptions{
+				RenderingOptions: tc.Options,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			rendered := parser.Render()
+			for _, expected := range tc.Expected {
+				if !strings.Contains(rendered, expected) {
+					t.Errorf("expected %s in %s", expected, rendered)
+				}
+			}
+			for _, unexpected := range tc.Unexpected {
+				if strings.Contains(rendered, unexpected) {
+					t.Errorf("expected no %s in %s", unexpected, rendered)
+				}
+			}
+		})
+	}
+}
@@ -14,15 +14,22 @@ the console.
 package parser
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
+	"go/build"
+	"go/importer"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 
 	"github.com/spf13/afero"
@@ -48,28 +55,138 @@ func (lsb *LineStringBuilder) WriteLineWithDepth(depth int, str string) {
 	lsb.WriteString("\n")
 }
 
+// flushTo writes what was built so far to the given writer and empties the builder
+func (lsb *LineStringBuilder) flushTo(w io.Writer) error {
+	_, err := io.WriteString(w, lsb.String())
+	lsb.Reset()
+	return err
+}
+
 // ClassDiagramOptions will provide a way for callers of the NewClassDiagramFs() function to pass all the necessary arguments.
 type ClassDiagramOptions struct {
+	// FileSystem is the file system the directories are walked and the go files read from (e.g. afero.NewMemMapFs()),
+	// the OS one if nil. The packages imported by the parsed ones are type checked from the OS (see TypeCheckImports).
 	FileSystem         afero.Fs
 	Directories        []string
 	IgnoredDirectories []string
-	RenderingOptions   map[RenderingOption]interface{}
-	Recursive          bool
+	// IgnoredPatterns are glob patterns of the directories skipped when walking the directories recursively. Patterns
+	// without a slash (e.g. mocks or *_gen) match the name of a directory at any depth, the other ones
+	// (e.g. internal/*/testdata) its path relative to the walked directory, ** matching any number of directories.
+	// The patterns of the .plantumlignore file of every walked directory, written with the .gitignore syntax, are
+	// added to them. The testdata and node_modules directories, and the vendor and hidden ones unless IncludeVendor and
+	// IncludeHidden are set, are ignored by default. Patterns starting with ! (e.g. !testdata) include them back.
+	IgnoredPatterns []string
+	// IncludeVendor walks the vendor directories too when walking the directories recursively
+	IncludeVendor bool
+	// IncludeHidden walks the directories whose name starts with a dot too when walking the directories recursively
+	IncludeHidden bool
+	// FollowSymlinks follows the symbolic links to directories when walking the directories recursively on the OS file
+	// system. The directories reached again through a link are walked once.
+	FollowSymlinks   bool
+	RenderingOptions map[RenderingOption]interface{}
+	Recursive        bool
+	IncludeExternal  bool
+	IncludeTypes     *regexp.Regexp
+	ExcludeTypes     *regexp.Regexp
+	// MatchUnderlyingTypes makes a method satisfy an interface method when their parameters and return values have
+	// the same underlying types (e.g. a method taking a MyString declared as type MyString string implements one
+	// taking a string). By default the compiler rules apply and only aliases (type MyString = string) do.
+	MatchUnderlyingTypes bool
+	// TypeCheckImports type checks the packages imported by the parsed ones from their source, so their methods and
+	// the interfaces they implement are known too (e.g. a structure embedding sync.Mutex implements sync.Locker). It
+	// makes the parsing much slower. It is implied by IncludeExternal, FindDependencies, FindCalls and FindProviders.
+	// By default the imported packages are stubbed with the types the parsed ones use, which is enough to check their
+	// signatures.
+	TypeCheckImports bool
+	// NormalizeName, when set, renames every package and type before rendering (e.g. to strip internal prefixes). It
+	// is called with package names (e.g. v2) and with package qualified type names (e.g. v2.Client), for which it
+	// must return a package qualified name too.
+	NormalizeName func(string) string
+	// BuildContext, when set, restricts the parsed files to the ones matching its build constraints (GOOS, GOARCH and
+	// build tags) like the go command does. By default every go file is parsed.
+	BuildContext *build.Context
+	// FindDependencies inspects the bodies of the methods to find the types whose methods they call or that they
+	// construct, which are rendered as dependencies with RenderDependencies. It makes the parsing slower.
+	FindDependencies bool
+	// FindCalls inspects the bodies of the functions and methods to record the calls they make, which are rendered
+	// with RenderSequence. It makes the parsing slower.
+	FindCalls bool
+	// FindGoroutines inspects the bodies of the methods to find the ones launching goroutines, which are rendered
+	// with RenderAsync.
+	FindGoroutines bool
+	// FindProviders records the constructors registered with google/wire or uber/fx, which are validated with
+	// MissingProviders and rendered with RenderProviders.
+	FindProviders bool
+	// Logger receives the progress of the parsing and rendering when set
+	Logger Logger
+	// Verbose reports every parsed file to the Logger too
+	Verbose bool
+	// Strict makes NewClassDiagramWithOptions fail, returning the ParseReport as error, if any file cannot be parsed
+	// completely. By default these files are skipped and reported by ClassParser.Errors.
+	Strict bool
+	// IncludeTests parses the _test.go files too. The types they declare are rendered with the test stereotype and the
+	// external test packages (e.g. parser_test) in their own namespace.
+	IncludeTests bool
+	// ExpandAnonymousStructs renders the anonymous struct types of the fields (e.g. Config struct{...}) as classes
+	// named after their structure and field (e.g. Outer_Config) composing it, instead of inlining them in the field
+	// type.
+	ExpandAnonymousStructs bool
 }
 
 // RenderingOptions will allow the class parser to optionally enebale or disable the things to render.
 type RenderingOptions struct {
-	Title                   string
-	Notes                   string
-	Aggregations            bool
-	Fields                  bool
-	Methods                 bool
-	Compositions            bool
-	Implementations         bool
-	Aliases                 bool
-	ConnectionLabels        bool
-	AggregatePrivateMembers bool
-	PrivateMembers          bool
+	Title                    string
+	Notes                    string
+	Aggregations             bool
+	Fields                   bool
+	Methods                  bool
+	Compositions             bool
+	Implementations          bool
+	Aliases                  bool
+	ConnectionLabels         bool
+	AggregatePrivateMembers  bool
+	PrivateMembers           bool
+	CleanSignatures          bool
+	Singletons               bool
+	FieldTags                bool
+	BuiltinNotes             bool
+	DocComments              bool
+	DocCommentsMaxLength     int
+	Conversions              bool
+	PackageFunctions         bool
+	FlattenInterfaces        bool
+	PublicMemberSymbol       string
+	PrivateMemberSymbol      string
+	VisibilityLegend         bool
+	EmbeddedAssets           bool
+	ContextWarnings          bool
+	QualifiedAssociations    bool
+	Dependencies             bool
+	Theme                    string
+	SkinParams               string
+	Direction                string
+	HideEmptyMembers         bool
+	HiddenNamespaceEdges     bool
+	ColorPackages            bool
+	PackageColors            map[string]string
+	PackageKeyword           bool
+	Multiplicity             bool
+	MetricsLegend            bool
+	Cycles                   bool
+	CustomRelations          []*CustomRelation
+	IgnoredAggregations      []string
+	CollapsePackageThreshold int
+	FileGroups               bool
+	SourceLinks              bool
+	LinkTemplate             string
+	LinkRoot                 string
+	Channels                 bool
+	ChannelArrow             string
+	Receivers                bool
+	Async                    bool
+	Deprecations             bool
+	PackageRelationKinds     []RelationKind
+	PackageWeights           bool
 }
 
 const aliasComplexNameComment = "'This class was created so that we can correctly have an alias pointing to this name. Since it contains dots that can break namespaces"
@@ -107,6 +224,129 @@ const (
 
 	// RenderPrivateMembers is used if private members (fields, methods) should be rendered
 	RenderPrivateMembers
+
+	// CleanSignatures is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the trailing error return value will be omitted from the rendered methods
+	CleanSignatures
+
+	// RenderSingletons is to be used in the SetRenderingOptions argument as the key to the map, when value is true, package level variables holding a struct will be rendered as singleton objects
+	RenderSingletons
+
+	// RenderFieldTags is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the struct field tags will be rendered next to the fields
+	RenderFieldTags
+
+	// RenderBuiltinNotes is to be used in the SetRenderingOptions argument as the key to the map, when value is true, relationships to builtin types (which are never drawn as edges) will be rendered as notes
+	RenderBuiltinNotes
+
+	// RenderDocComments is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the doc comments of structs, interfaces and methods will be rendered as notes
+	RenderDocComments
+
+	// DocCommentsMaxLength is the maximum length of the rendered doc comments. Longer comments are truncated. A value of 0 or less disables the truncation
+	DocCommentsMaxLength
+
+	// RenderConversions is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the explicit conversions between the parsed types will be rendered as "converts to" connections
+	RenderConversions
+
+	// RenderPackageFunctions is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the functions without receiver and the exported variables of every package will be rendered in a synthetic <<functions>> class
+	RenderPackageFunctions
+
+	// FlattenInterfaces is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the methods of embedded interfaces will be inlined in the embedding interface instead of rendering a connection to them
+	FlattenInterfaces
+
+	// PublicMemberSymbol is the symbol rendered before the exported fields and methods ("+" by default). An empty value renders no symbol
+	PublicMemberSymbol
+
+	// PrivateMemberSymbol is the symbol rendered before the unexported fields and methods ("-" by default). An empty value renders no symbol
+	PrivateMemberSymbol
+
+	// RenderVisibilityLegend is to be used in the SetRenderingOptions argument as the key to the map, when value is true, a legend explaining the member symbols will be rendered
+	RenderVisibilityLegend
+
+	// RenderEmbeddedAssets is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the variables initialized with a //go:embed directive will be rendered as artifacts linked to the structures using them
+	RenderEmbeddedAssets
+
+	// RenderContextWarnings is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the exported methods without a context.Context first parameter in packages where most exported methods have one will be annotated
+	RenderContextWarnings
+
+	// RenderQualifiedAssociations is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the aggregations of the values of map fields will be labeled with the key type of the map (e.g. per UserID for map[UserID]*Session)
+	RenderQualifiedAssociations
+
+	// RenderDependencies is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the types whose methods are called or that are constructed in the methods of a structure will be connected to it, unless they are already connected. They are only found when ClassDiagramOptions.FindDependencies is set
+	RenderDependencies
+
+	// RenderTheme is to be used in the SetRenderingOptions argument as the key to the map, the value is the name of the PlantUML theme (e.g. cerulean) included with !theme at the top of the diagram. Empty for none
+	RenderTheme
+
+	// SkinParams is to be used in the SetRenderingOptions argument as the key to the map, the value holds one skinparam per line (e.g. "linetype ortho\nArrowColor #333333") rendered at the top of the diagram, after the theme
+	SkinParams
+
+	// RenderDirection is to be used in the SetRenderingOptions argument as the key to the map, the value is the direction of the diagram, one of DirectionTopToBottom or DirectionLeftToRight. Empty for the PlantUML default
+	RenderDirection
+
+	// HideEmptyMembers is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the empty fields and methods compartments of the classes will be hidden
+	HideEmptyMembers
+
+	// RenderHiddenNamespaceEdges is to be used in the SetRenderingOptions argument as the key to the map, when value is true, hidden edges will chain the namespaces in alphabetical order so PlantUML lays them out one after another in the direction of the diagram
+	RenderHiddenNamespaceEdges
+
+	// RenderPackageColors is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the classes of every package will be given a background color of a palette, so the classes of a package can be told apart even when they are connected across namespaces
+	RenderPackageColors
+
+	// PackageColors is to be used in the SetRenderingOptions argument as the key to the map, the value is a map[string]string of package name patterns (e.g. *repository, with the path.Match syntax) to the background color of the classes of the matching packages (e.g. #D5E8D4). It takes precedence over RenderPackageColors, so layers can be colored consistently
+	PackageColors
+
+	// RenderPackageKeyword is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the packages will be rendered as quoted package "x" {} blocks instead of namespace x {} blocks, for the package names (e.g. with dots or reserved words) that break namespaces
+	RenderPackageKeyword
+
+	// RenderMultiplicity is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the aggregations of the types held by slice, map and array fields will be annotated with their multiplicity (e.g. "1" o-- "0..*" for []*Seat or "1" o-- "4" for [4]Wheel)
+	RenderMultiplicity
+
+	// RenderMetricsLegend is to be used in the SetRenderingOptions argument as the key to the map, when value is true, a table of the size and coupling figures of every package (see PackageMetrics) will be rendered in the legend
+	RenderMetricsLegend
+
+	// RenderCycles is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the edges between the packages of a dependency cycle (see PackageCycles) will be rendered in red
+	RenderCycles
+
+	// CustomRelations is to be used in the SetRenderingOptions argument as the key to the map, the value is a []*CustomRelation (see ParseCustomRelations) of relationships that cannot be found in the code, rendered with the parsed ones
+	CustomRelations
+
+	// IgnoredAggregations is to be used in the SetRenderingOptions argument as the key to the map, the value is a []string of patterns, with the path.Match syntax, of the package qualified names of the types whose aggregations are not rendered (e.g. time.Time or sync.*). See DefaultIgnoredAggregations
+	IgnoredAggregations
+
+	// CollapsePackageThreshold is to be used in the SetRenderingOptions argument as the key to the map, the value is an int. The packages with more types are rendered as a single node with the number of their types, the relationships with their types being drawn to and from that node. 0 for no limit
+	CollapsePackageThreshold
+
+	// RenderFileGroups is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the types of every package will be nested in a package block per file declaring them
+	RenderFileGroups
+
+	// RenderSourceLinks is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the classes will link ([[file:line]]) to their declaration
+	RenderSourceLinks
+
+	// LinkTemplate is to be used in the SetRenderingOptions argument as the key to the map, the value is a string url (e.g. https://github.com/org/repo/blob/main/{path}#L{line}) every class links to, {path} and {line} being replaced by the position of its declaration. It takes precedence over RenderSourceLinks
+	LinkTemplate
+
+	// LinkRoot is to be used in the SetRenderingOptions argument as the key to the map, the value is the string directory (e.g. the root of the repository) the {path} of the LinkTemplate is relative to
+	LinkRoot
+
+	// RenderChannels is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the aggregations of the types carried by channel fields will be rendered as edges labeled with the directions of the channels (sends, receives or sends/receives). The types also held by other fields are only connected by these edges
+	RenderChannels
+
+	// ChannelArrow is to be used in the SetRenderingOptions argument as the key to the map, the value is the string PlantUML arrow (e.g. ..> or -[#blue]->) of the edges rendered by RenderChannels, --> by default
+	ChannelArrow
+
+	// RenderReceivers is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the methods declared on a pointer receiver will be annotated with {ptr}
+	RenderReceivers
+
+	// RenderAsync is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the methods launching goroutines will be rendered with the <<async>> stereotype. They are only found when ClassDiagramOptions.FindGoroutines is set
+	RenderAsync
+
+	// RenderDeprecations is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the types whose doc comment has a paragraph starting with Deprecated: will be rendered with the deprecated stereotype, and the methods with such a doc comment struck through
+	RenderDeprecations
+
+	// PackageRelationKinds is to be used in the SetRenderingOptions argument as the key to the map, the value is a []RelationKind of the kinds of the relationships between types that make the relationships between packages of RenderC4 (e.g. only RelationComposition and RelationExtends). Empty for the ones of PackageDependencies
+	PackageRelationKinds
+
+	// RenderPackageWeights is to be used in the SetRenderingOptions argument as the key to the map, when value is true, the relationships between packages of RenderC4 will be labeled with the number of relationships between their types they stand for
+	RenderPackageWeights
 )
 
 // RenderingOption is an alias for an it so it is easier to use it as options in a map (see SetRenderingOptions(map[RenderingOption]bool) error)
@@ -114,85 +354,264 @@ type RenderingOption int
 
 // ClassParser contains the structure of the parsed files. The structure is a map of package_names that contains
 // a map of structure_names -> Structs
+//
+// The parsed structure is only changed by Merge once NewClassDiagramWithOptions returns, so a ClassParser can be
+// rendered and queried from multiple goroutines. SetRenderingOptions and Merge are synchronized with the renders, not
+// with the other queries. The returned structures (e.g. by Structs) are shared and must not be modified.
 type ClassParser struct {
-	renderingOptions   *RenderingOptions
-	structure          map[string]map[string]*Struct
-	currentPackageName string
-	allInterfaces      map[string]struct{}
-	allStructs         map[string]struct{}
-	allImports         map[string]string
-	allAliases         map[string]*Alias
-	allRenamedStructs  map[string]map[string]string
+	// mutex protects the rendering options and the structure merged by Merge, the only state that changes after parsing
+	mutex                sync.RWMutex
+	renderingOptions     *RenderingOptions
+	structure            map[string]map[string]*Struct
+	currentPackageName   string
+	allInterfaces        map[string]struct{}
+	allStructs           map[string]struct{}
+	allImports           map[string]string
+	allAliases           map[string]*Alias
+	allRenamedStructs    map[string]map[string]string
+	typedImplements      map[string]map[string]bool
+	fileSet              *token.FileSet
+	typesImporter        types.Importer
+	importedPackages     map[string]*types.Package
+	allExternals         map[string]*Struct
+	allGlobals           map[string][]*GlobalVariable
+	allConversions       map[string]map[string]struct{}
+	allFunctions         map[string][]*Function
+	allEmbeds            map[string][]*EmbeddedAssets
+	contextlessMethods   map[*Function]struct{}
+	matchUnderlyingTypes bool
+	buildContext         *build.Context
+	includeTests         bool
+	parsingTestFile      bool
+	parsingFileName      string
+	diagnostics          []string
+	parseErrors          []*ParseError
+	logger               Logger
+	verbose              bool
+	parsedDirectories    int
+	parsedFiles          int
+	cyclicPackages       map[string]int
+	findDependencies     bool
+	allDependencies      map[string]map[string]struct{}
+	findCalls            bool
+	allCalls             map[string]*sequenceFunction
+	findProviders        bool
+	findGoroutines       bool
+	ignoredTypes         map[string]struct{}
+	allProviders         []*Provider
+	fileSystem           afero.Fs
+	anonymousStructs     bool
 }
 
 // NewClassDiagramWithOptions returns a new classParser with which can Render the class diagram of
 // files in the given directory passed in the ClassDiargamOptions. This will also alow for different types of FileSystems
 // Passed since it is part of the ClassDiagramOptions as well.
 func NewClassDiagramWithOptions(options *ClassDiagramOptions) (*ClassParser, error) {
+	return NewClassDiagramWithContext(context.Background(), options)
+}
+
+// NewClassDiagramWithContext is NewClassDiagramWithOptions stopping the parsing, and returning the error of the given
+// context, as soon as it is done (e.g. canceled or timed out).
+func NewClassDiagramWithContext(ctx context.Context, options *ClassDiagramOptions) (*ClassParser, error) {
+	classParser := newClassParser(options)
+	start := time.Now()
+	if err := classParser.parseDirectories(ctx, options); err != nil {
+		return nil, err
+	}
+	return classParser.resolve(options, start)
+}
+
+// NewClassDiagramFromReader returns the class diagram of the go source read from src, named fileName in the parse
+// errors and source positions (e.g. a file given on the standard input). The Directories and FileSystem of the
+// options are not used.
+func NewClassDiagramFromReader(fileName string, src io.Reader, options *ClassDiagramOptions) (*ClassParser, error) {
+	classParser := newClassParser(options)
+	start := time.Now()
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(classParser.fileSet, fileName, content, parser.ParseComments)
+	if err != nil {
+		classParser.addParseError(fileName, err)
+	} else {
+		pack := &ast.Package{Name: file.Name.Name, Files: map[string]*ast.File{fileName: file}}
+		files := classParser.parsePackage(pack)
+		if files > 0 {
+			classParser.typeCheckPackage(filepath.Dir(fileName), pack)
+		}
+		classParser.parsedFiles += files
+	}
+	return classParser.resolve(options, start)
+}
+
+// newClassParser returns a parser with nothing parsed yet
+func newClassParser(options *ClassDiagramOptions) *ClassParser {
 	classParser := &ClassParser{
 		renderingOptions: &RenderingOptions{
-			Aggregations:     false,
-			Fields:           true,
-			Methods:          true,
-			Compositions:     true,
-			Implementations:  true,
-			Aliases:          true,
-			ConnectionLabels: false,
-			Title:            "",
-			Notes:            "",
+			Aggregations:         false,
+			Fields:               true,
+			Methods:              true,
+			Compositions:         true,
+			Implementations:      true,
+			Aliases:              true,
+			ConnectionLabels:     false,
+			Title:                "",
+			Notes:                "",
+			DocCommentsMaxLength: 80,
+			PublicMemberSymbol:   "+",
+			PrivateMemberSymbol:  "-",
 		},
-		structure:         make(map[string]map[string]*Struct),
-		allInterfaces:     make(map[string]struct{}),
-		allStructs:        make(map[string]struct{}),
-		allImports:        make(map[string]string),
-		allAliases:        make(map[string]*Alias),
-		allRenamedStructs: make(map[string]map[string]string),
+		structure:            make(map[string]map[string]*Struct),
+		allInterfaces:        make(map[string]struct{}),
+		allStructs:           make(map[string]struct{}),
+		allImports:           make(map[string]string),
+		allAliases:           make(map[string]*Alias),
+		allRenamedStructs:    make(map[string]map[string]string),
+		typedImplements:      make(map[string]map[string]bool),
+		fileSet:              token.NewFileSet(),
+		importedPackages:     make(map[string]*types.Package),
+		allExternals:         make(map[string]*Struct),
+		allGlobals:           make(map[string][]*GlobalVariable),
+		allConversions:       make(map[string]map[string]struct{}),
+		allFunctions:         make(map[string][]*Function),
+		allEmbeds:            make(map[string][]*EmbeddedAssets),
+		contextlessMethods:   make(map[*Function]struct{}),
+		matchUnderlyingTypes: options.MatchUnderlyingTypes,
+		buildContext:         options.BuildContext,
+		includeTests:         options.IncludeTests,
+		findDependencies:     options.FindDependencies,
+		allDependencies:      make(map[string]map[string]struct{}),
+		findCalls:            options.FindCalls,
+		allCalls:             make(map[string]*sequenceFunction),
+		findProviders:        options.FindProviders,
+		findGoroutines:       options.FindGoroutines,
+		ignoredTypes:         make(map[string]struct{}),
+		logger:               options.Logger,
+		verbose:              options.Verbose,
+		fileSystem:           options.FileSystem,
+		anonymousStructs:     options.ExpandAnonymousStructs,
+	}
+	classParser.typesImporter = newStubImporter()
+	if options.TypeCheckImports || options.IncludeExternal || options.FindDependencies || options.FindCalls || options.FindProviders {
+		classParser.typesImporter = importer.ForCompiler(classParser.fileSet, "source", nil)
+	}
+	if classParser.fileSystem == nil {
+		classParser.fileSystem = afero.NewOsFs()
 	}
+	return classParser
+}
+
+// parseDirectories parses the directories of the given options, walking them when they are recursive
+func (p *ClassParser) parseDirectories(ctx context.Context, options *ClassDiagramOptions) error {
 	ignoreDirectoryMap := map[string]struct{}{}
 	for _, dir := range options.IgnoredDirectories {
 		ignoreDirectoryMap[dir] = struct{}{}
 	}
 	for _, directoryPath := range options.Directories {
 		if options.Recursive {
-			err := afero.Walk(options.FileSystem, directoryPath, func(path string, info os.FileInfo, err error) error {
+			filePatterns, err := getIgnoreFilePatterns(p.fileSystem, directoryPath)
+			if err != nil {
+				return err
+			}
+			// the default patterns come first and the given ones last so they win over the ones of the file
+			ignoredPatterns := append(getDefaultIgnoredPatterns(options), filePatterns...)
+			ignoredPatterns = append(ignoredPatterns, options.IgnoredPatterns...)
+			err = walk(p.fileSystem, directoryPath, options.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 				if err != nil {
 					return err
 				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
 				if info.IsDir() {
-					if strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" {
+					if _, ok := ignoreDirectoryMap[path]; ok {
 						return filepath.SkipDir
 					}
-					if _, ok := ignoreDirectoryMap[path]; ok {
+					if isIgnoredDirectory(directoryPath, path, ignoredPatterns) {
 						return filepath.SkipDir
 					}
-					classParser.parseDirectory(path)
+					p.parseDirectory(ctx, path)
 				}
 				return nil
 			})
 			if err != nil {
-				return nil, err
+				return err
 			}
 		} else {
-			err := classParser.parseDirectory(directoryPath)
+			err := p.parseDirectory(ctx, directoryPath)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
+	return ctx.Err()
+}
+
+// resolve resolves the relationships between the parsed structures, parsed since the given time, and sets the
+// rendering options
+func (p *ClassParser) resolve(options *ClassDiagramOptions, start time.Time) (*ClassParser, error) {
+	p.logf("parsed %d files in %d directories, found %d types in %s", p.parsedFiles, p.parsedDirectories, p.countTypes(), getElapsed(start))
+	if report := p.Errors(); options.Strict && report.HasErrors() {
+		return nil, report
+	}
+	start = time.Now()
+	p.resolveEnums()
+	p.resolveImplementations()
+	p.resolveConversions()
+	p.resolveDependencies()
+	p.resolveCalls()
+	p.resolveContextlessMethods()
+	p.removeIgnoredTypes()
+	p.filterTypes(options.IncludeTypes, options.ExcludeTypes)
+	if options.IncludeExternal {
+		p.addExternalTypes()
+	}
+	if options.NormalizeName != nil {
+		p.normalizeNames(options.NormalizeName)
+	}
+	p.logf("resolved the relationships in %s", getElapsed(start))
+	if err := p.SetRenderingOptions(options.RenderingOptions); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
 
-	for s := range classParser.allStructs {
-		st := classParser.getStruct(s)
+// resolveImplementations adds the extends relationship to every struct that implements one of the parsed interfaces
+func (p *ClassParser) resolveImplementations() {
+	var underlying func(string) string
+	if p.matchUnderlyingTypes {
+		underlying = p.getUnderlyingTypeName
+	}
+	for s := range p.allStructs {
+		st := p.getStruct(s)
 		if st != nil {
-			for i := range classParser.allInterfaces {
-				inter := classParser.getStruct(i)
-				if st.ImplementsInterface(inter) {
+			for i := range p.allInterfaces {
+				inter := p.getStruct(i)
+				if p.resolveImplementation(s, st, i, inter, underlying) {
 					st.AddToExtends(i)
 				}
 			}
 		}
 	}
-	classParser.SetRenderingOptions(options.RenderingOptions)
-	return classParser, nil
+}
+
+// resolveImplementation returns true if the structure st, named s, implements the interface inter, named i. The
+// decisions of the type information that differ from the signatures alone are recorded in typedImplements, keyed by
+// structure and interface, so they are kept when the implementations are resolved again without it (e.g. by Merge on
+// loaded models).
+func (p *ClassParser) resolveImplementation(s string, st *Struct, i string, inter *Struct, underlying func(string) string) bool {
+	if override, ok := p.typedImplements[s][i]; ok {
+		return override
+	}
+	implements := st.implementsInterface(inter, underlying)
+	if len(inter.Functions) > 0 && implements != st.implementsInterfaceBySignatures(inter, underlying) {
+		if _, ok := p.typedImplements[s]; !ok {
+			p.typedImplements[s] = map[string]bool{}
+		}
+		p.typedImplements[s][i] = implements
+	}
+	return implements
 }
 
 // NewClassDiagram returns a new classParser with which can Render the class diagram of
@@ -208,30 +627,47 @@ func NewClassDiagram(directoryPaths []string, ignoreDirectories []string, recurs
 	return NewClassDiagramWithOptions(options)
 }
 
-// parse the given ast.Package into the ClassParser structure
-func (p *ClassParser) parsePackage(node ast.Node) {
+// parse the given ast.Package into the ClassParser structure and return the number of files parsed. Packages without
+// files to parse (e.g. external test packages when the tests are not included) are not added.
+func (p *ClassParser) parsePackage(node ast.Node) int {
 	pack := node.(*ast.Package)
+	var sortedFiles []string
+	for fileName := range pack.Files {
+		if p.includeTests || !strings.HasSuffix(fileName, "_test.go") {
+			sortedFiles = append(sortedFiles, fileName)
+		}
+	}
+	if len(sortedFiles) == 0 {
+		return 0
+	}
 	p.currentPackageName = pack.Name
 	_, ok := p.structure[p.currentPackageName]
 	if !ok {
 		p.structure[p.currentPackageName] = make(map[string]*Struct)
 	}
-	var sortedFiles []string
-	for fileName := range pack.Files {
-		sortedFiles = append(sortedFiles, fileName)
-	}
 	sort.Strings(sortedFiles)
+	files := []*ast.File{}
 	for _, fileName := range sortedFiles {
+		p.parsingTestFile = strings.HasSuffix(fileName, "_test.go")
+		p.parsingFileName = fileName
+		f := pack.Files[fileName]
+		p.parseFile(fileName, f)
+		files = append(files, f)
+	}
+	p.addEmbeddedAssetsUsers(files)
+	return len(files)
+}
 
-		if !strings.HasSuffix(fileName, "_test.go") {
-			f := pack.Files[fileName]
-			for _, d := range f.Imports {
-				p.parseImports(d)
-			}
-			for _, d := range f.Decls {
-				p.parseFileDeclarations(d)
-			}
-		}
+// parseFile parses the imports and declarations of the given file. A panic on unexpected source is reported in the
+// parse errors, keeping the declarations parsed until then.
+func (p *ClassParser) parseFile(fileName string, f *ast.File) {
+	defer p.recoverParseError(fileName)
+	p.verbosef("parsing %s", fileName)
+	for _, d := range f.Imports {
+		p.parseImports(d)
+	}
+	for _, d := range f.Decls {
+		p.parseFileDeclarations(d)
 	}
 }
 
@@ -243,15 +679,34 @@ func (p *ClassParser) parseImports(impt *ast.ImportSpec) {
 	}
 }
 
-func (p *ClassParser) parseDirectory(directoryPath string) error {
-	fs := token.NewFileSet()
-	result, err := parser.ParseDir(fs, directoryPath, nil, 0)
+func (p *ClassParser) parseDirectory(ctx context.Context, directoryPath string) error {
+	start := time.Now()
+	types := p.countTypes()
+	result, err := p.parseDirectoryFiles(ctx, directoryPath)
 	if err != nil {
 		return err
 	}
-	for _, v := range result {
-		p.parsePackage(v)
+	// packages are parsed in a stable order since the imports found are shared between them
+	names := make([]string, 0, len(result))
+	for name := range result {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parsedFiles := 0
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		files := p.parsePackage(result[name])
+		if files > 0 {
+			p.typeCheckPackage(directoryPath, result[name])
+		}
+		parsedFiles += files
 	}
+	p.addExcludedDirectoryDiagnostic(directoryPath, parsedFiles)
+	p.parsedDirectories++
+	p.parsedFiles += parsedFiles
+	p.logf("scanned %s: %d files, %d types in %s", directoryPath, parsedFiles, p.countTypes()-types, getElapsed(start))
 	return nil
 }
 
@@ -272,11 +727,12 @@ func (p *ClassParser) handleFuncDecl(decl *ast.FuncDecl) {
 			return
 		}
 
-		// Only get in when the function is defined for a structure. Global functions are not needed for class diagram
-		theType, _ := getFieldType(decl.Recv.List[0].Type, p.allImports)
+		// Only get in when the function is defined for a structure. Global functions are rendered apart
+		receiver, _ := getReceiverType(decl.Recv.List[0].Type)
+		theType, _ := getFieldType(receiver, p.allImports)
 		theType = replacePackageConstant(theType, "")
-		if theType[0] == "*"[0] {
-			theType = theType[1:]
+		if theType == "" {
+			return
 		}
 		structure := p.getOrCreateStruct(theType)
 		if structure.Type == "" {
@@ -285,6 +741,7 @@ func (p *ClassParser) handleFuncDecl(decl *ast.FuncDecl) {
 
 		fullName := fmt.Sprintf("%s.%s", p.currentPackageName, theType)
 		p.allStructs[fullName] = struct{}{}
+		methods := len(structure.Functions)
 		structure.AddMethod(&ast.Field{
 			Names:   []*ast.Ident{decl.Name},
 			Doc:     decl.Doc,
@@ -292,12 +749,25 @@ func (p *ClassParser) handleFuncDecl(decl *ast.FuncDecl) {
 			Tag:     nil,
 			Comment: nil,
 		}, p.allImports)
+		p.setLastMethodPosition(structure, methods, decl.Name.Pos())
+		setLastMethodReceiver(structure, methods, decl.Recv.List[0].Type)
+		if p.findGoroutines {
+			setLastMethodAsync(structure, methods, decl.Body)
+		}
+	} else {
+		p.addPackageFunction(decl)
 	}
 }
 
 func handleGenDecStructType(p *ClassParser, typeName string, c *ast.StructType) {
 	for _, f := range c.Fields.List {
-		p.getOrCreateStruct(typeName).AddField(f, p.allImports)
+		st := p.getOrCreateStruct(typeName)
+		fields := len(st.Fields)
+		st.AddField(f, p.allImports)
+		p.setLastFieldLine(st, fields, f.Pos())
+		if p.anonymousStructs && len(st.Fields) > fields {
+			p.expandAnonymousStruct(st, typeName, st.Fields[len(st.Fields)-1], f.Type)
+		}
 	}
 }
 
@@ -305,13 +775,17 @@ func handleGenDecInterfaceType(p *ClassParser, typeName string, c *ast.Interface
 	for _, f := range c.Methods.List {
 		switch t := f.Type.(type) {
 		case *ast.FuncType:
-			p.getOrCreateStruct(typeName).AddMethod(f, p.allImports)
+			st := p.getOrCreateStruct(typeName)
+			methods := len(st.Functions)
+			st.AddMethod(f, p.allImports)
+			p.setLastMethodPosition(st, methods, f.Pos())
 			break
 		case *ast.Ident:
 			f, _ := getFieldType(t, p.allImports)
 			st := p.getOrCreateStruct(typeName)
 			f = replacePackageConstant(f, st.PackageName)
 			st.AddToComposition(f)
+			st.EmbeddedInterfaces = append(st.EmbeddedInterfaces, f)
 			break
 		}
 	}
@@ -322,7 +796,25 @@ func (p *ClassParser) handleGenDecl(decl *ast.GenDecl) {
 		// This might be a type of General Declaration we do not know how to handle.
 		return
 	}
+	var constType ast.Expr
 	for _, spec := range decl.Specs {
+		if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+			switch decl.Tok {
+			case token.VAR:
+				p.addGlobalVariables(valueSpec)
+				if decl.Lparen.IsValid() {
+					p.addEmbeddedAssets(valueSpec, nil)
+				} else {
+					p.addEmbeddedAssets(valueSpec, decl.Doc)
+				}
+			case token.CONST:
+				constType = p.addEnumValues(valueSpec, constType)
+			}
+		}
+		if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Doc == nil && len(decl.Specs) == 1 {
+			// The doc comment of a non parenthesized type declaration is attached to the GenDecl
+			typeSpec.Doc = decl.Doc
+		}
 		p.processSpec(spec)
 	}
 }
@@ -330,10 +822,17 @@ func (p *ClassParser) handleGenDecl(decl *ast.GenDecl) {
 func (p *ClassParser) processSpec(spec ast.Spec) {
 	var typeName string
 	var alias *Alias
+	var doc string
+	var comments *ast.CommentGroup
+	var line int
+	var aggregations []string
 	declarationType := "alias"
 	switch v := spec.(type) {
 	case *ast.TypeSpec:
 		typeName = v.Name.Name
+		line = p.getLine(v.Name.Pos())
+		doc = strings.TrimSpace(v.Doc.Text())
+		comments = v.Doc
 		switch c := v.Type.(type) {
 		case *ast.StructType:
 			declarationType = "class"
@@ -344,8 +843,13 @@ func (p *ClassParser) processSpec(spec ast.Spec) {
 		default:
 			basicType, _ := getFieldType(getBasicType(c), p.allImports)
 
-			aliasType, _ := getFieldType(c, p.allImports)
+			aliasType, fundamentalTypes := getFieldType(c, p.allImports)
 			aliasType = replacePackageConstant(aliasType, "")
+			if getBasicType(c) != c {
+				// the defined collection types (e.g. type Sessions map[UserID]*Session) aggregate the types of their
+				// keys and elements like the fields of these types do
+				aggregations = fundamentalTypes
+			}
 			if !isPrimitiveString(typeName) {
 				typeName = fmt.Sprintf("%s.%s", p.currentPackageName, typeName)
 			}
@@ -354,13 +858,25 @@ func (p *ClassParser) processSpec(spec ast.Spec) {
 				packageName = builtinPackageName
 			}
 			alias = getNewAlias(fmt.Sprintf("%s.%s", packageName, aliasType), p.currentPackageName, typeName)
+			alias.Assign = v.Assign.IsValid()
 
 		}
 	default:
 		// Not needed for class diagrams (Imports, global variables, regular functions, etc)
 		return
 	}
-	p.getOrCreateStruct(typeName).Type = declarationType
+	st := p.getOrCreateStruct(typeName)
+	st.Type = declarationType
+	for _, t := range aggregations {
+		st.AddToAggregation(replacePackageConstant(t, p.currentPackageName))
+	}
+	// the type parameters are types of the declaration only, not of the package
+	st.removeTypeParameters(getTypeSpecParameters(spec.(*ast.TypeSpec)))
+	st.Doc = doc
+	st.Test = p.parsingTestFile
+	st.FileName = p.parsingFileName
+	st.Line = line
+	p.addDirectives(st, getStructFullName(st, p.currentPackageName, typeName), comments)
 	fullName := fmt.Sprintf("%s.%s", p.currentPackageName, typeName)
 	switch declarationType {
 	case "interface":
@@ -369,13 +885,13 @@ func (p *ClassParser) processSpec(spec ast.Spec) {
 		p.allStructs[fullName] = struct{}{}
 	case "alias":
 		p.allAliases[typeName] = alias
-		if strings.Count(alias.Name, ".") > 1 {
+		if needsRenaming(alias.Name) {
 			pack := strings.SplitN(alias.Name, ".", 2)
 			if _, ok := p.allRenamedStructs[pack[0]]; !ok {
 				p.allRenamedStructs[pack[0]] = map[string]string{}
 			}
-			renamedClass := generateRenamedStructName(pack[1])
-			p.allRenamedStructs[pack[0]][renamedClass] = pack[1]
+			// the classes are named when rendered, once every type of the package is known
+			p.allRenamedStructs[pack[0]][pack[1]] = pack[1]
 		}
 	}
 	return
@@ -401,46 +917,89 @@ func getBasicType(theType ast.Expr) ast.Expr {
 
 // Render returns a string of the class diagram that this parser has generated.
 func (p *ClassParser) Render() string {
+	str := &strings.Builder{}
+	// writing to a strings.Builder never fails
+	p.RenderTo(str)
+	return str.String()
+}
+
+// RenderTo writes the class diagram returned by Render to the given writer. The diagram is written package by package
+// so it is never held in memory as a whole, which matters for the diagrams of large repositories.
+func (p *ClassParser) RenderTo(w io.Writer) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	defer p.logRenderTime(time.Now())
 	str := &LineStringBuilder{}
-	str.WriteLineWithDepth(0, "@startuml")
-	if p.renderingOptions.Title != "" {
-		str.WriteLineWithDepth(0, fmt.Sprintf(`title %s`, p.renderingOptions.Title))
-	}
-	if note := strings.TrimSpace(p.renderingOptions.Notes); note != "" {
-		str.WriteLineWithDepth(0, "legend")
-		str.WriteLineWithDepth(0, note)
-		str.WriteLineWithDepth(0, "end legend")
+	p.renderHeader(str)
+	if err := str.flushTo(w); err != nil {
+		return err
 	}
-
 	var packages []string
 	for pack := range p.structure {
 		packages = append(packages, pack)
 	}
 	sort.Strings(packages)
+	namespaces := []string{}
 	for _, pack := range packages {
 		structures := p.structure[pack]
-		p.renderStructures(pack, structures, str)
+		if p.isCollapsedPackage(pack) {
+			p.renderCollapsedPackage(pack, structures, str)
+		} else if p.renderStructures(pack, structures, str) {
+			namespaces = append(namespaces, pack)
+		}
+		if err := str.flushTo(w); err != nil {
+			return err
+		}
+	}
+	p.renderFooter(namespaces, str)
+	return str.flushTo(w)
+}
 
+// renderHeader renders the start of the class diagram, before the packages
+func (p *ClassParser) renderHeader(str *LineStringBuilder) {
+	str.WriteLineWithDepth(0, "@startuml")
+	p.renderTheme(str)
+	if p.hasEmbeddedAssets() {
+		// artifacts are not part of class diagrams
+		str.WriteLineWithDepth(0, "allowmixing")
 	}
+	if p.renderingOptions.Title != "" {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`title %s`, p.renderingOptions.Title))
+	}
+	p.renderDirection(str)
+	p.renderLegend(str)
+}
+
+// renderFooter renders the end of the class diagram, after the packages whose namespaces were rendered
+func (p *ClassParser) renderFooter(namespaces []string, str *LineStringBuilder) {
+	p.renderExternals(str)
 	if p.renderingOptions.Aliases {
 		p.renderAliases(str)
 	}
+	p.renderCustomRelations(str)
+	p.renderHiddenNamespaceEdges(namespaces, str)
 	if !p.renderingOptions.Fields {
 		str.WriteLineWithDepth(0, "hide fields")
 	}
 	if !p.renderingOptions.Methods {
 		str.WriteLineWithDepth(0, "hide methods")
 	}
+	if p.renderingOptions.HideEmptyMembers {
+		str.WriteLineWithDepth(0, "hide empty members")
+	}
 	str.WriteLineWithDepth(0, "@enduml")
-	return str.String()
 }
 
-func (p *ClassParser) renderStructures(pack string, structures map[string]*Struct, str *LineStringBuilder) {
-	if len(structures) > 0 {
+// renderStructures renders the namespace of the given package and the relationships of its structures. It returns
+// false when the namespace is not rendered because the package has nothing to render.
+func (p *ClassParser) renderStructures(pack string, structures map[string]*Struct, str *LineStringBuilder) bool {
+	if len(structures) > 0 || p.getPackageFunctions(pack) != nil || (p.renderingOptions.EmbeddedAssets && len(p.allEmbeds[pack]) > 0) {
 		composition := &LineStringBuilder{}
 		extends := &LineStringBuilder{}
 		aggregations := &LineStringBuilder{}
-		str.WriteLineWithDepth(0, fmt.Sprintf(`namespace %s {`, pack))
+		conversions := &LineStringBuilder{}
+		dependencies := &LineStringBuilder{}
+		str.WriteLineWithDepth(0, p.getNamespaceStart(pack))
 
 		names := []string{}
 		for name := range structures {
@@ -449,18 +1008,37 @@ func (p *ClassParser) renderStructures(pack string, structures map[string]*Struc
 
 		sort.Strings(names)
 
-		for _, name := range names {
-			structure := structures[name]
-			p.renderStructure(structure, pack, name, str, composition, extends, aggregations)
+		for _, group := range p.getFileGroups(names, structures) {
+			groupStr := str
+			if group.fileName != "" {
+				groupStr = &LineStringBuilder{}
+			}
+			for _, name := range group.names {
+				structure := structures[name]
+				p.renderStructure(structure, pack, name, groupStr, composition, extends, aggregations)
+				if p.renderingOptions.Conversions {
+					p.renderConversions(structure, getStructFullName(structure, pack, name), conversions)
+				}
+				p.renderDependencies(structure, getStructFullName(structure, pack, name), dependencies)
+			}
+			renderFileGroup(pack, group.fileName, groupStr, str)
 		}
+		p.renderPackageFunctions(pack, str)
+		singletons := &LineStringBuilder{}
+		p.renderSingletons(pack, str, singletons)
+		embeds := &LineStringBuilder{}
+		p.renderEmbeddedAssets(pack, str, embeds)
+		docNotes := &LineStringBuilder{}
+		p.renderDocComments(pack, names, structures, docNotes)
+		renamedStructs := map[string]string{}
 		var orderedRenamedStructs []string
-		for tempName := range p.allRenamedStructs[pack] {
+		for name, tempName := range p.getRenamedStructNames(pack) {
+			renamedStructs[tempName] = name
 			orderedRenamedStructs = append(orderedRenamedStructs, tempName)
 		}
 		sort.Strings(orderedRenamedStructs)
 		for _, tempName := range orderedRenamedStructs {
-			name := p.allRenamedStructs[pack][tempName]
-			str.WriteLineWithDepth(1, fmt.Sprintf(`class "%s" as %s {`, name, tempName))
+			str.WriteLineWithDepth(1, fmt.Sprintf(`class %s as %s {`, quoteName(renamedStructs[tempName]), tempName))
 			str.WriteLineWithDepth(2, aliasComplexNameComment)
 			str.WriteLineWithDepth(1, "}")
 		}
@@ -474,7 +1052,14 @@ func (p *ClassParser) renderStructures(pack string, structures map[string]*Struc
 		if p.renderingOptions.Aggregations {
 			str.WriteLineWithDepth(0, aggregations.String())
 		}
+		for _, edges := range []*LineStringBuilder{conversions, dependencies, singletons, embeds, docNotes} {
+			if edges.Len() > 0 {
+				str.WriteLineWithDepth(0, edges.String())
+			}
+		}
+		return true
 	}
+	return false
 }
 
 func (p *ClassParser) renderAliases(str *LineStringBuilder) {
@@ -489,22 +1074,37 @@ func (p *ClassParser) renderAliases(str *LineStringBuilder) {
 	}
 	sort.Sort(orderedAliases)
 	for _, alias := range orderedAliases {
+		if isBuiltinName(alias.Name) {
+			if p.renderingOptions.BuiltinNotes {
+				str.WriteLineWithDepth(0, fmt.Sprintf(`note right of %s : alias of %s`, alias.AliasOf, strings.TrimPrefix(alias.Name, builtinPackageName+".")))
+			}
+			continue
+		}
 		aliasName := alias.Name
-		if strings.Count(alias.Name, ".") > 1 {
+		if needsRenaming(alias.Name) {
 			split := strings.SplitN(alias.Name, ".", 2)
-			if aliasRename, ok := p.allRenamedStructs[split[0]]; ok {
-				renamed := generateRenamedStructName(split[1])
-				if _, ok := aliasRename[renamed]; ok {
-					aliasName = fmt.Sprintf("%s.%s", split[0], renamed)
-				}
+			if renamed, ok := p.getRenamedStructNames(split[0])[split[1]]; ok {
+				aliasName = fmt.Sprintf("%s.%s", split[0], renamed)
 			}
 		}
-		str.WriteLineWithDepth(0, fmt.Sprintf(`"%s" #.. %s"%s"`, aliasName, aliasString, alias.AliasOf))
+		aliasOf := p.getCollapsedName(alias.AliasOf)
+		aliasName = p.getCollapsedName(aliasName)
+		if aliasName == aliasOf {
+			// both in the same collapsed package
+			continue
+		}
+		if alias.Assign {
+			// a true alias is the same type as the aliased one
+			str.WriteLineWithDepth(0, fmt.Sprintf(`"%s" %s..> "%s"`, aliasOf, aliasString, aliasName))
+			continue
+		}
+		str.WriteLineWithDepth(0, fmt.Sprintf(`"%s" #.. %s"%s"`, aliasName, aliasString, aliasOf))
 	}
 }
 
 func (p *ClassParser) renderStructure(structure *Struct, pack string, name string, str *LineStringBuilder, composition *LineStringBuilder, extends *LineStringBuilder, aggregations *LineStringBuilder) {
 
+	structure = p.getFlattenedInterface(structure)
 	privateFields := &LineStringBuilder{}
 	publicFields := &LineStringBuilder{}
 	privateMethods := &LineStringBuilder{}
@@ -516,8 +1116,25 @@ func (p *ClassParser) renderStructure(structure *Struct, pack string, name strin
 		sType = "<< (S,Aquamarine) >>"
 	case "alias":
 		sType = "<< (T, #FF7700) >> "
+		if alias, ok := p.allAliases[name]; ok && alias.Assign {
+			sType = "<< (T, #FF7700) alias >> "
+		}
 		renderStructureType = "class"
-
+	case "enum":
+		p.renderEnumValues(structure, privateFields, publicFields)
+	}
+	if structure.Test {
+		sType = getTestStereotype(sType)
+	}
+	sType = p.getDeprecatedStereotype(structure, sType)
+	for _, stereotype := range structure.Stereotypes {
+		sType = addStereotype(sType, stereotype)
+	}
+	if color := p.getPackageColor(pack); color != "" {
+		sType = strings.TrimSpace(fmt.Sprintf("%s %s", strings.TrimSpace(sType), color))
+	}
+	if link := p.getSourceLink(structure); link != "" {
+		sType = strings.TrimSpace(fmt.Sprintf("%s %s", strings.TrimSpace(sType), link))
 	}
 	str.WriteLineWithDepth(1, fmt.Sprintf(`%s %s %s {`, renderStructureType, name, sType))
 	p.renderStructFields(structure, privateFields, publicFields)
@@ -543,39 +1160,49 @@ func (p *ClassParser) renderStructure(structure *Struct, pack string, name strin
 func (p *ClassParser) renderCompositions(structure *Struct, name string, composition *LineStringBuilder) {
 	orderedCompositions := []string{}
 
+	builtinCompositions := []string{}
 	for c := range structure.Composition {
 		if !strings.Contains(c, ".") {
 			c = fmt.Sprintf("%s.%s", p.getPackageName(c, structure), c)
 		}
+		if isBuiltinName(c) {
+			builtinCompositions = append(builtinCompositions, strings.TrimPrefix(c, builtinPackageName+"."))
+			continue
+		}
+		c = p.getExternalName(p.getCollapsedName(c))
 		composedString := ""
 		if p.renderingOptions.ConnectionLabels {
 			composedString = extends
 		}
-		c = fmt.Sprintf(`"%s" *-- %s"%s.%s"`, c, composedString, structure.PackageName, name)
+		c = fmt.Sprintf(`"%s" %s %s"%s.%s"`, c, p.getCycleArrow("*--", structure.PackageName+"."+name, c), composedString, structure.PackageName, name)
 		orderedCompositions = append(orderedCompositions, c)
 	}
 	sort.Strings(orderedCompositions)
 	for _, c := range orderedCompositions {
 		composition.WriteLineWithDepth(0, c)
 	}
+	if p.renderingOptions.BuiltinNotes && len(builtinCompositions) > 0 {
+		sort.Strings(builtinCompositions)
+		composition.WriteLineWithDepth(0, fmt.Sprintf(`note right of %s.%s : embeds %s`, structure.PackageName, name, strings.Join(builtinCompositions, ", ")))
+	}
+}
+
+// isBuiltinName returns true if the given package qualified name belongs to the builtin namespace. No edges are
+// drawn to builtin types, they can only be shown as notes with the BuiltinNotes option.
+func isBuiltinName(name string) bool {
+	return strings.HasPrefix(name, builtinPackageName+".")
 }
 
 func (p *ClassParser) renderAggregations(structure *Struct, name string, aggregations *LineStringBuilder) {
 
 	aggregationMap := structure.Aggregations
 	if p.renderingOptions.AggregatePrivateMembers {
-		p.updatePrivateAggregations(structure, aggregationMap)
+		// merged into a new set so rendering never changes the parsed structure
+		aggregationMap = mergeSets(structure.Aggregations, structure.PrivateAggregations)
 	}
 	p.renderAggregationMap(aggregationMap, structure, aggregations, name)
 }
 
-func (p *ClassParser) updatePrivateAggregations(structure *Struct, aggregationsMap map[string]struct{}) {
-
-	for agg := range structure.PrivateAggregations {
-		aggregationsMap[agg] = struct{}{}
-	}
-}
-
 func (p *ClassParser) renderAggregationMap(aggregationMap map[string]struct{}, structure *Struct, aggregations *LineStringBuilder, name string) {
 	var orderedAggregations []string
 	for a := range aggregationMap {
@@ -584,17 +1211,32 @@ func (p *ClassParser) renderAggregationMap(aggregationMap map[string]struct{}, s
 
 	sort.Strings(orderedAggregations)
 
+	fullName := getStructFullName(structure, structure.PackageName, name)
 	for _, a := range orderedAggregations {
+		channel := p.getChannelLabel(structure, a)
+		multiplicity := p.getMultiplicityLabel(structure, a)
+		qualifier := ""
+		if label := strings.TrimSpace(fmt.Sprintf("%s %s", multiplicity, p.getQualifierLabel(structure, a))); label != "" {
+			qualifier = fmt.Sprintf(` "%s"`, label)
+		}
 		if !strings.Contains(a, ".") {
 			a = fmt.Sprintf("%s.%s", p.getPackageName(a, structure), a)
 		}
 		aggregationString := ""
 		if p.renderingOptions.ConnectionLabels {
 			aggregationString = aggregates
+		} else if multiplicity != "" {
+			// the label of the connection takes the place of the multiplicity of the aggregating end
+			aggregationString = ` "1"`
+		}
+		if p.getPackageName(a, structure) == builtinPackageName || p.isIgnoredAggregation(a) {
+			continue
 		}
-		if p.getPackageName(a, structure) != builtinPackageName {
-			aggregations.WriteLineWithDepth(0, fmt.Sprintf(`"%s.%s"%s o-- "%s"`, structure.PackageName, name, aggregationString, a))
+		if channel != "" {
+			p.renderChannel(fullName, p.getExternalName(p.getCollapsedName(a)), channel, aggregations)
+			continue
 		}
+		aggregations.WriteLineWithDepth(0, fmt.Sprintf(`"%s"%s %s%s "%s"`, fullName, aggregationString, p.getCycleArrow("o--", fullName, a), qualifier, p.getExternalName(p.getCollapsedName(a))))
 	}
 }
 
@@ -613,11 +1255,12 @@ func (p *ClassParser) renderExtends(structure *Struct, name string, extends *Lin
 		if !strings.Contains(c, ".") {
 			c = fmt.Sprintf("%s.%s", structure.PackageName, c)
 		}
+		c = p.getExternalName(p.getCollapsedName(c))
 		implementString := ""
 		if p.renderingOptions.ConnectionLabels {
 			implementString = implements
 		}
-		c = fmt.Sprintf(`"%s" <|-- %s"%s.%s"`, c, implementString, structure.PackageName, name)
+		c = fmt.Sprintf(`"%s" %s %s"%s.%s"`, c, p.getCycleArrow("<|--", structure.PackageName+"."+name, c), implementString, structure.PackageName, name)
 		orderedExtends = append(orderedExtends, c)
 	}
 	sort.Strings(orderedExtends)
@@ -629,48 +1272,67 @@ func (p *ClassParser) renderExtends(structure *Struct, name string, extends *Lin
 func (p *ClassParser) renderStructMethods(structure *Struct, privateMethods *LineStringBuilder, publicMethods *LineStringBuilder) {
 
 	for _, method := range structure.Functions {
-		accessModifier := "+"
-		if unicode.IsLower(rune(method.Name[0])) {
-			if !p.renderingOptions.PrivateMembers {
-				continue
-			}
-
-			accessModifier = "-"
+		accessModifier, ok := p.getAccessModifier(method.Name)
+		if !ok {
+			continue
 		}
 		parameterList := make([]string, 0)
 		for _, p := range method.Parameters {
 			parameterList = append(parameterList, fmt.Sprintf("%s %s", p.Name, p.Type))
 		}
 		returnValues := ""
-		if len(method.ReturnValues) > 0 {
-			if len(method.ReturnValues) == 1 {
-				returnValues = method.ReturnValues[0]
+		renderedReturnValues := p.getRenderedReturnValues(method)
+		if len(renderedReturnValues) > 0 {
+			if len(renderedReturnValues) == 1 {
+				returnValues = renderedReturnValues[0]
 			} else {
-				returnValues = fmt.Sprintf("(%s)", strings.Join(method.ReturnValues, ", "))
+				returnValues = fmt.Sprintf("(%s)", strings.Join(renderedReturnValues, ", "))
 			}
 		}
-		if accessModifier == "-" {
-			privateMethods.WriteLineWithDepth(2, fmt.Sprintf(`%s %s(%s) %s`, accessModifier, method.Name, strings.Join(parameterList, ", "), returnValues))
+		signature := fmt.Sprintf(`%s(%s)`, method.Name, strings.Join(parameterList, ", "))
+		if returnValues != "" {
+			signature = fmt.Sprintf(`%s %s`, signature, returnValues)
+		}
+		// the annotations are separated by single spaces, the ones that are not rendered being empty
+		parts := []string{p.getDeprecatedSignature(method, signature)}
+		for _, annotation := range []string{p.getReceiverAnnotation(method), p.getAsyncStereotype(method), p.getContextWarning(method)} {
+			if annotation != "" {
+				parts = append(parts, annotation)
+			}
+		}
+		renderedMethod := withAccessModifier(accessModifier, strings.Join(parts, " "))
+		if unicode.IsLower(rune(method.Name[0])) {
+			privateMethods.WriteLineWithDepth(2, renderedMethod)
 		} else {
-			publicMethods.WriteLineWithDepth(2, fmt.Sprintf(`%s %s(%s) %s`, accessModifier, method.Name, strings.Join(parameterList, ", "), returnValues))
+			publicMethods.WriteLineWithDepth(2, renderedMethod)
 		}
 	}
 }
 
+// getRenderedReturnValues returns the return values of the method that should be rendered. When CleanSignatures
+// is enabled the trailing error return value is left out since almost every method has one.
+func (p *ClassParser) getRenderedReturnValues(method *Function) []string {
+	returnValues := method.ReturnValues
+	if p.renderingOptions.CleanSignatures && len(returnValues) > 0 && returnValues[len(returnValues)-1] == "error" {
+		returnValues = returnValues[:len(returnValues)-1]
+	}
+	return returnValues
+}
+
 func (p *ClassParser) renderStructFields(structure *Struct, privateFields *LineStringBuilder, publicFields *LineStringBuilder) {
 	for _, field := range structure.Fields {
-		accessModifier := "+"
-		if unicode.IsLower(rune(field.Name[0])) {
-			if !p.renderingOptions.PrivateMembers {
-				continue
-			}
-
-			accessModifier = "-"
+		accessModifier, ok := p.getAccessModifier(field.Name)
+		if !ok {
+			continue
+		}
+		renderedField := withAccessModifier(accessModifier, fmt.Sprintf(`%s %s`, field.Name, field.Type))
+		if p.renderingOptions.FieldTags && field.Tag != "" {
+			renderedField = fmt.Sprintf(`%s <font color=gray>%s</font>`, renderedField, field.Tag)
 		}
-		if accessModifier == "-" {
-			privateFields.WriteLineWithDepth(2, fmt.Sprintf(`%s %s %s`, accessModifier, field.Name, field.Type))
+		if unicode.IsLower(rune(field.Name[0])) {
+			privateFields.WriteLineWithDepth(2, renderedField)
 		} else {
-			publicFields.WriteLineWithDepth(2, fmt.Sprintf(`%s %s %s`, accessModifier, field.Name, field.Type))
+			publicFields.WriteLineWithDepth(2, renderedField)
 		}
 	}
 }
@@ -688,6 +1350,7 @@ func (p *ClassParser) getOrCreateStruct(name string) *Struct {
 			Extends:             make(map[string]struct{}, 0),
 			Aggregations:        make(map[string]struct{}, 0),
 			PrivateAggregations: make(map[string]struct{}, 0),
+			Conversions:         make(map[string]struct{}, 0),
 		}
 		p.structure[p.currentPackageName][name] = result
 	}
@@ -704,40 +1367,154 @@ func (p *ClassParser) getStruct(structName string) *Struct {
 	return pack[split[1]]
 }
 
+// Structs returns the parsed structures keyed by their package qualified name (e.g. parser.Struct). The methods of
+// the defined types that are not structs (e.g. type Slice []Item) are merged into a copy of their alias or enum.
+func (p *ClassParser) Structs() map[string]*Struct {
+	result := map[string]*Struct{}
+	for pack, structures := range p.structure {
+		for name, st := range structures {
+			fullName := getStructFullName(st, pack, name)
+			if existing, ok := result[fullName]; ok {
+				st = mergeMethodHolder(existing, st)
+			}
+			result[fullName] = st
+		}
+	}
+	return result
+}
+
+// mergeMethodHolder returns a copy of the alias or enum declaration of a defined type with the methods and
+// relationships of the structure holding its methods, whichever order they are given in
+func mergeMethodHolder(st *Struct, other *Struct) *Struct {
+	declaration, holder := st, other
+	if declaration.Type != "alias" && declaration.Type != "enum" {
+		declaration, holder = other, st
+	}
+	merged := *declaration
+	merged.Functions = append(append([]*Function{}, declaration.Functions...), holder.Functions...)
+	merged.Composition = getUnion(declaration.Composition, holder.Composition)
+	merged.Extends = getUnion(declaration.Extends, holder.Extends)
+	merged.Aggregations = getUnion(declaration.Aggregations, holder.Aggregations)
+	merged.PrivateAggregations = getUnion(declaration.PrivateAggregations, holder.PrivateAggregations)
+	merged.Conversions = getUnion(declaration.Conversions, holder.Conversions)
+	merged.Dependencies = getUnion(declaration.Dependencies, holder.Dependencies)
+	return &merged
+}
+
+// getUnion returns a new set with the elements of both sets
+func getUnion(set map[string]struct{}, other map[string]struct{}) map[string]struct{} {
+	result := make(map[string]struct{}, len(set)+len(other))
+	mergeSet(result, set)
+	mergeSet(result, other)
+	return result
+}
+
 // SetRenderingOptions Sets the rendering options for the Render() Function
 func (p *ClassParser) SetRenderingOptions(ro map[RenderingOption]interface{}) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 	for option, val := range ro {
+		if stringOption, ok := p.getStringRenderingOption(option); ok {
+			*stringOption = val.(string)
+			continue
+		}
 		switch option {
-		case RenderAggregations:
-			p.renderingOptions.Aggregations = val.(bool)
-		case RenderAliases:
-			p.renderingOptions.Aliases = val.(bool)
-		case RenderCompositions:
-			p.renderingOptions.Compositions = val.(bool)
-		case RenderFields:
-			p.renderingOptions.Fields = val.(bool)
-		case RenderImplementations:
-			p.renderingOptions.Implementations = val.(bool)
-		case RenderMethods:
-			p.renderingOptions.Methods = val.(bool)
-		case RenderConnectionLabels:
-			p.renderingOptions.ConnectionLabels = val.(bool)
-		case RenderTitle:
-			p.renderingOptions.Title = val.(string)
-		case RenderNotes:
-			p.renderingOptions.Notes = val.(string)
-		case AggregatePrivateMembers:
-			p.renderingOptions.AggregatePrivateMembers = val.(bool)
-		case RenderPrivateMembers:
-			p.renderingOptions.PrivateMembers = val.(bool)
+		case DocCommentsMaxLength:
+			p.renderingOptions.DocCommentsMaxLength = val.(int)
+		case CollapsePackageThreshold:
+			p.renderingOptions.CollapsePackageThreshold = val.(int)
+		case PackageColors:
+			p.renderingOptions.PackageColors = val.(map[string]string)
+		case CustomRelations:
+			p.renderingOptions.CustomRelations = val.([]*CustomRelation)
+		case IgnoredAggregations:
+			if err := p.setIgnoredAggregations(val.([]string)); err != nil {
+				return err
+			}
+		case PackageRelationKinds:
+			if err := p.setPackageRelationKinds(val.([]RelationKind)); err != nil {
+				return err
+			}
+		case RenderDirection:
+			direction := val.(string)
+			if direction != "" && direction != DirectionTopToBottom && direction != DirectionLeftToRight {
+				return fmt.Errorf("Invalid direction %s", direction)
+			}
+			p.renderingOptions.Direction = direction
 		default:
-			return fmt.Errorf("Invalid Rendering option %v", option)
+			boolOption, ok := p.getBoolRenderingOption(option)
+			if !ok {
+				return fmt.Errorf("Invalid Rendering option %v", option)
+			}
+			*boolOption = val.(bool)
 		}
 
 	}
+	if p.renderingOptions.Cycles {
+		p.cyclicPackages = p.getCyclicPackages()
+	}
 	return nil
 }
-func generateRenamedStructName(currentName string) string {
-	reg, _ := regexp.Compile("[^a-zA-Z0-9]+")
-	return reg.ReplaceAllString(currentName, "")
+
+// getStringRenderingOption returns a pointer to the string field of the rendering options identified by option.
+// The second return value is false if option is not a string rendering option that can be set to any value.
+func (p *ClassParser) getStringRenderingOption(option RenderingOption) (*string, bool) {
+	stringOptions := map[RenderingOption]*string{
+		RenderTitle:         &p.renderingOptions.Title,
+		RenderNotes:         &p.renderingOptions.Notes,
+		PublicMemberSymbol:  &p.renderingOptions.PublicMemberSymbol,
+		PrivateMemberSymbol: &p.renderingOptions.PrivateMemberSymbol,
+		RenderTheme:         &p.renderingOptions.Theme,
+		SkinParams:          &p.renderingOptions.SkinParams,
+		LinkTemplate:        &p.renderingOptions.LinkTemplate,
+		LinkRoot:            &p.renderingOptions.LinkRoot,
+		ChannelArrow:        &p.renderingOptions.ChannelArrow,
+	}
+	result, ok := stringOptions[option]
+	return result, ok
+}
+
+// getBoolRenderingOption returns a pointer to the boolean field of the rendering options identified by option.
+// The second return value is false if option is not a boolean rendering option.
+func (p *ClassParser) getBoolRenderingOption(option RenderingOption) (*bool, bool) {
+	boolOptions := map[RenderingOption]*bool{
+		RenderAggregations:          &p.renderingOptions.Aggregations,
+		RenderAliases:               &p.renderingOptions.Aliases,
+		RenderCompositions:          &p.renderingOptions.Compositions,
+		RenderFields:                &p.renderingOptions.Fields,
+		RenderImplementations:       &p.renderingOptions.Implementations,
+		RenderMethods:               &p.renderingOptions.Methods,
+		RenderConnectionLabels:      &p.renderingOptions.ConnectionLabels,
+		AggregatePrivateMembers:     &p.renderingOptions.AggregatePrivateMembers,
+		RenderPrivateMembers:        &p.renderingOptions.PrivateMembers,
+		CleanSignatures:             &p.renderingOptions.CleanSignatures,
+		RenderSingletons:            &p.renderingOptions.Singletons,
+		RenderFieldTags:             &p.renderingOptions.FieldTags,
+		RenderBuiltinNotes:          &p.renderingOptions.BuiltinNotes,
+		RenderDocComments:           &p.renderingOptions.DocComments,
+		RenderConversions:           &p.renderingOptions.Conversions,
+		RenderPackageFunctions:      &p.renderingOptions.PackageFunctions,
+		FlattenInterfaces:           &p.renderingOptions.FlattenInterfaces,
+		RenderVisibilityLegend:      &p.renderingOptions.VisibilityLegend,
+		RenderEmbeddedAssets:        &p.renderingOptions.EmbeddedAssets,
+		RenderContextWarnings:       &p.renderingOptions.ContextWarnings,
+		RenderQualifiedAssociations: &p.renderingOptions.QualifiedAssociations,
+		RenderDependencies:          &p.renderingOptions.Dependencies,
+		HideEmptyMembers:            &p.renderingOptions.HideEmptyMembers,
+		RenderHiddenNamespaceEdges:  &p.renderingOptions.HiddenNamespaceEdges,
+		RenderPackageColors:         &p.renderingOptions.ColorPackages,
+		RenderPackageKeyword:        &p.renderingOptions.PackageKeyword,
+		RenderMultiplicity:          &p.renderingOptions.Multiplicity,
+		RenderMetricsLegend:         &p.renderingOptions.MetricsLegend,
+		RenderCycles:                &p.renderingOptions.Cycles,
+		RenderFileGroups:            &p.renderingOptions.FileGroups,
+		RenderSourceLinks:           &p.renderingOptions.SourceLinks,
+		RenderChannels:              &p.renderingOptions.Channels,
+		RenderReceivers:             &p.renderingOptions.Receivers,
+		RenderAsync:                 &p.renderingOptions.Async,
+		RenderDeprecations:          &p.renderingOptions.Deprecations,
+		RenderPackageWeights:        &p.renderingOptions.PackageWeights,
+	}
+	result, ok := boolOptions[option]
+	return result, ok
 }
@@ -1,10 +1,17 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"go/ast"
 	"io/ioutil"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/spf13/afero"
 )
 
 func TestLineBuilder(t *testing.T) {
@@ -98,6 +105,7 @@ func TestGetOrCreateStruct(t *testing.T) {
 					Extends:             make(map[string]struct{}, 0),
 					Aggregations:        make(map[string]struct{}, 0),
 					PrivateAggregations: make(map[string]struct{}, 0),
+					Conversions:         make(map[string]struct{}, 0),
 				}) {
 					t.Errorf("Expected resulting structure to be equal to %v, got %v", tc.structure, st)
 				}
@@ -180,6 +188,34 @@ func TestRenderStructFields(t *testing.T) {
 	}
 }
 
+func TestRenderStructFieldTags(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/fieldtags"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderStructFieldTags: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderFieldTags: true,
+	})
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace fieldtags {
+    class User << (S,Aquamarine) >> {
+        + ID int <font color=gray>json:"id" gorm:"primaryKey"</font>
+        + Email string <font color=gray>json:"email" validate:"required,email"</font>
+        + Name string
+
+    }
+}
+
+
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderStructFieldTags: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
 func TestRenderStructures(t *testing.T) {
 
 	structMap := map[string]*Struct{
@@ -338,7 +374,15 @@ func TestRenderCompositions(t *testing.T) {
 	}
 	extendsBuilder = &LineStringBuilder{}
 	parser.renderCompositions(st, "TestClass", extendsBuilder)
-	expectedResult = "\"" + builtinPackageName + ".int\" *-- \"main.TestClass\"\n"
+	expectedResult = ""
+	if extendsBuilder.String() != expectedResult {
+		t.Errorf("TestRenderCompositions: Expected %s got %s", expectedResult, extendsBuilder.String())
+	}
+
+	parser.renderingOptions.BuiltinNotes = true
+	extendsBuilder = &LineStringBuilder{}
+	parser.renderCompositions(st, "TestClass", extendsBuilder)
+	expectedResult = "note right of main.TestClass : embeds int\n"
 	if extendsBuilder.String() != expectedResult {
 		t.Errorf("TestRenderCompositions: Expected %s got %s", expectedResult, extendsBuilder.String())
 	}
@@ -414,16 +458,84 @@ func TestRenderStructMethods(t *testing.T) {
 	}
 }
 
+func TestRenderStructMethodsCleanSignatures(t *testing.T) {
+	parser := getEmptyParser("main")
+	parser.renderingOptions.CleanSignatures = true
+	st := &Struct{
+		Functions: []*Function{
+			{
+				Name:         "foo",
+				ReturnValues: []string{"int", "error"},
+			},
+			{
+				Name:         "Bar",
+				ReturnValues: []string{"error"},
+			},
+			{
+				Name:         "Baz",
+				ReturnValues: []string{"error", "int"},
+			},
+		},
+	}
+	privateFunctions := &LineStringBuilder{}
+	publicFunctions := &LineStringBuilder{}
+	parser.renderStructMethods(st, privateFunctions, publicFunctions)
+	if privateFunctions.String() != "        - foo() int\n" {
+		t.Errorf("TestRenderStructMethodsCleanSignatures: expected privateFields to be [        - foo() int\\n] got [%v]", privateFunctions.String())
+	}
+	expectedPublic := "        + Bar()\n        + Baz() (error, int)\n"
+	if publicFunctions.String() != expectedPublic {
+		t.Errorf("TestRenderStructMethodsCleanSignatures: expected publicFields to be [%s] got [%v]", expectedPublic, publicFunctions.String())
+	}
+}
+
+func TestRenderStructMethodsAnnotations(t *testing.T) {
+	parser := getEmptyParser("main")
+	parser.renderingOptions.Receivers = true
+	parser.renderingOptions.Async = true
+	parser.renderingOptions.Deprecations = true
+	st := &Struct{
+		Functions: []*Function{
+			{
+				Name: "m",
+			},
+			{
+				Name:            "Add",
+				Parameters:      []*Field{{Name: "t", Type: "T"}},
+				PointerReceiver: true,
+				Async:           true,
+			},
+			{
+				Name:            "Old",
+				Doc:             "Deprecated: use Add",
+				PointerReceiver: true,
+			},
+		},
+	}
+	privateFunctions := &LineStringBuilder{}
+	publicFunctions := &LineStringBuilder{}
+	parser.renderStructMethods(st, privateFunctions, publicFunctions)
+	if privateFunctions.String() != "        - m()\n" {
+		t.Errorf("TestRenderStructMethodsAnnotations: expected privateFields to be [        - m()\\n] got [%v]", privateFunctions.String())
+	}
+	expectedPublic := "        + Add(t T) {ptr} <<async>>\n        + <s>Old()</s> {ptr}\n"
+	if publicFunctions.String() != expectedPublic {
+		t.Errorf("TestRenderStructMethodsAnnotations: expected publicFields to be [%s] got [%v]", expectedPublic, publicFunctions.String())
+	}
+}
+
 func getEmptyParser(packageName string) *ClassParser {
 	result := &ClassParser{
 		renderingOptions: &RenderingOptions{
-			Aggregations:    false,
-			Fields:          true,
-			Methods:         true,
-			Compositions:    true,
-			Implementations: true,
-			Aliases:         true,
-			PrivateMembers:  true,
+			Aggregations:        false,
+			Fields:              true,
+			Methods:             true,
+			Compositions:        true,
+			Implementations:     true,
+			Aliases:             true,
+			PrivateMembers:      true,
+			PublicMemberSymbol:  "+",
+			PrivateMemberSymbol: "-",
 		},
 		currentPackageName: packageName,
 		structure:          make(map[string]map[string]*Struct),
@@ -549,6 +661,36 @@ func TestNewClassDiagram(t *testing.T) {
 	}
 }
 
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestRenderTo(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder", "../testingsupport/subfolder2"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestRenderTo: expected no error but got %s", err.Error())
+	}
+	buffer := &bytes.Buffer{}
+	if err := parser.RenderTo(buffer); err != nil {
+		t.Fatalf("TestRenderTo: expected no error but got %s", err.Error())
+	}
+	if rendered := parser.Render(); buffer.String() != rendered {
+		t.Errorf("TestRenderTo: expected the written diagram to be the rendered one\n%s\ngot\n%s", rendered, buffer.String())
+	}
+	writer := &failingWriter{}
+	if err := parser.RenderTo(writer); err == nil || err.Error() != "disk full" {
+		t.Errorf("TestRenderTo: expected the error of the writer, got %v", err)
+	}
+	if writer.writes != 1 {
+		t.Errorf("TestRenderTo: expected the rendering to stop at the first failed write, got %d writes", writer.writes)
+	}
+}
+
 func TestRender(t *testing.T) {
 
 	parser, err := NewClassDiagram([]string{"../testingsupport"}, []string{}, false)
@@ -583,6 +725,68 @@ func TestGetPackageName(t *testing.T) {
 	}
 }
 
+func TestNewClassDiagramWithContext(t *testing.T) {
+	for _, recursive := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := NewClassDiagramWithContext(ctx, &ClassDiagramOptions{
+			FileSystem:         afero.NewOsFs(),
+			Directories:        []string{"../testingsupport"},
+			IgnoredDirectories: []string{},
+			RenderingOptions:   map[RenderingOption]interface{}{},
+			Recursive:          recursive,
+		})
+		if err != context.Canceled {
+			t.Errorf("TestNewClassDiagramWithContext: expected the parsing to be canceled when recursive is %t, got %v", recursive, err)
+		}
+	}
+	parser, err := NewClassDiagramWithContext(context.Background(), &ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/subfolder"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("TestNewClassDiagramWithContext: expected no error but got %s", err.Error())
+	}
+	if _, ok := parser.Structs()["subfolder.test2"]; !ok {
+		t.Errorf("TestNewClassDiagramWithContext: expected subfolder.test2 to be parsed, got %v", parser.Structs())
+	}
+}
+
+func TestNewClassDiagramFromReader(t *testing.T) {
+	src := `package snippet
+
+type Reader interface {
+	Read() string
+}
+
+type File struct {
+	Name string
+}
+
+func (f File) Read() string {
+	return f.Name
+}
+`
+	parser, err := NewClassDiagramFromReader("snippet.go", strings.NewReader(src), &ClassDiagramOptions{
+		RenderingOptions: map[RenderingOption]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("TestNewClassDiagramFromReader: expected no error but got %s", err.Error())
+	}
+	if st, ok := parser.Structs()["snippet.File"]; !ok || st.FileName != "snippet.go" || st.Line != 7 {
+		t.Errorf("TestNewClassDiagramFromReader: expected snippet.File declared at snippet.go:7, got %v", parser.Structs())
+	}
+	if rendered := parser.Render(); !strings.Contains(rendered, `"snippet.Reader" <|-- "snippet.File"`) {
+		t.Errorf("TestNewClassDiagramFromReader: expected File to implement Reader, got %s", rendered)
+	}
+	_, err = NewClassDiagramFromReader("broken.go", strings.NewReader("package broken\ntype"), &ClassDiagramOptions{Strict: true})
+	if report, ok := err.(*ParseReport); !ok || len(report.Errors) != 1 || report.Errors[0].File != "broken.go" {
+		t.Errorf("TestNewClassDiagramFromReader: expected a parse error for broken.go, got %v", err)
+	}
+}
+
 func TestMultipleFolders(t *testing.T) {
 	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder3", "../testingsupport/subfolder2"}, []string{}, false)
 
@@ -664,26 +868,30 @@ func TestRenderAggregations(t *testing.T) {
 func TestSetRenderingOptions(t *testing.T) {
 	parser := getEmptyParser("main")
 	emptyRenderingOptions := &RenderingOptions{
-		Aggregations:    false,
-		Fields:          true,
-		Methods:         true,
-		Compositions:    true,
-		Implementations: true,
-		Aliases:         true,
-		PrivateMembers:  true,
+		Aggregations:        false,
+		Fields:              true,
+		Methods:             true,
+		Compositions:        true,
+		Implementations:     true,
+		Aliases:             true,
+		PrivateMembers:      true,
+		PublicMemberSymbol:  "+",
+		PrivateMemberSymbol: "-",
 	}
 	if !reflect.DeepEqual(parser.renderingOptions, emptyRenderingOptions) {
 		t.Errorf("TestRenderingOptions: expected renderingOptions to be %v got %v", emptyRenderingOptions, parser.renderingOptions)
 	}
 	newRenderingOptions := &RenderingOptions{
-		Aggregations:     true,
-		Implementations:  false,
-		Compositions:     true,
-		Methods:          false,
-		Fields:           true,
-		Aliases:          false,
-		ConnectionLabels: true,
-		PrivateMembers:   false,
+		Aggregations:        true,
+		Implementations:     false,
+		Compositions:        true,
+		Methods:             false,
+		Fields:              true,
+		Aliases:             false,
+		ConnectionLabels:    true,
+		PrivateMembers:      false,
+		PublicMemberSymbol:  "+",
+		PrivateMemberSymbol: "",
 	}
 	parser.SetRenderingOptions(map[RenderingOption]interface{}{
 		RenderAggregations:     true,
@@ -694,6 +902,7 @@ func TestSetRenderingOptions(t *testing.T) {
 		RenderAliases:          false,
 		RenderConnectionLabels: true,
 		RenderPrivateMembers:   false,
+		PrivateMemberSymbol:    "",
 	})
 	if !reflect.DeepEqual(parser.renderingOptions, newRenderingOptions) {
 		t.Errorf("TestRenderingOptions: expected renderingOptions to be %v got %v", newRenderingOptions, parser.renderingOptions)
@@ -836,7 +1045,7 @@ namespace renderingoptions {
     class Test << (S,Aquamarine) >> {
         - integer int
 
-        - function() 
+        - function()
 
     }
 }
@@ -856,7 +1065,7 @@ namespace renderingoptions {
     class Test << (S,Aquamarine) >> {
         - integer int
 
-        - function() 
+        - function()
 
     }
 }
@@ -877,7 +1086,7 @@ namespace renderingoptions {
     class Test << (S,Aquamarine) >> {
         - integer int
 
-        - function() 
+        - function()
 
     }
 }
@@ -897,7 +1106,7 @@ namespace renderingoptions {
     class Test << (S,Aquamarine) >> {
         - integer int
 
-        - function() 
+        - function()
 
     }
 }
@@ -983,7 +1192,6 @@ namespace connectionlabels {
 
 "connectionlabels.ImplementsAbstractInterface""uses" o-- "connectionlabels.AbstractInterface"
 
-"__builtin__.int" #.. "alias of""connectionlabels.AliasOfInt"
 @enduml
 `
 	if result != expectedResult {
@@ -992,6 +1200,43 @@ namespace connectionlabels {
 
 }
 
+func TestBuiltinNotesRendering(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/connectionlabels"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestBuiltinNotesRendering: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderBuiltinNotes: true,
+		RenderFields:       false,
+		RenderMethods:      false,
+	})
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace connectionlabels {
+    interface AbstractInterface  {
+    }
+    class ImplementsAbstractInterface << (S,Aquamarine) >> {
+        + PublicUse AbstractInterface
+
+    }
+    class connectionlabels.AliasOfInt << (T, #FF7700) >>  {
+    }
+}
+"connectionlabels.AliasOfInt" *-- "connectionlabels.ImplementsAbstractInterface"
+
+"connectionlabels.AbstractInterface" <|-- "connectionlabels.ImplementsAbstractInterface"
+
+note right of connectionlabels.AliasOfInt : alias of int
+hide fields
+hide methods
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestBuiltinNotesRendering: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
 func TestNewClassDiagramWithOptions(t *testing.T) {
 	options := &ClassDiagramOptions{
 		RenderingOptions: map[RenderingOption]interface{}{
@@ -1007,10 +1252,24 @@ func TestNewClassDiagramWithOptions(t *testing.T) {
 	}
 }
 
+func TestNewClassDiagramWithoutFileSystem(t *testing.T) {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		Directories:      []string{"../testingsupport/subfolder"},
+		Recursive:        true,
+		RenderingOptions: map[RenderingOption]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("TestNewClassDiagramWithoutFileSystem: expected no error but got %s", err.Error())
+	}
+	if len(parser.Packages()) == 0 {
+		t.Errorf("TestNewClassDiagramWithoutFileSystem: expected the directory to be parsed from the OS")
+	}
+}
+
 func TestGenerateRenamedStructName(t *testing.T) {
 	generatedName := generateRenamedStructName(`a#b%c.d`)
-	if generatedName != "abcd" {
-		t.Errorf("TestGenerateRenamedStructName: Expected result to be abcd, got %s", generatedName)
+	if generatedName != "__abcd" {
+		t.Errorf("TestGenerateRenamedStructName: Expected result to be __abcd, got %s", generatedName)
 	}
 }
 
@@ -1037,11 +1296,11 @@ func TestParametrizedTypeDeclarations(t *testing.T) {
 	expectedResult := `@startuml
 namespace parenthesizedtypedeclarations {
     interface Bar  {
-        + Bar() 
+        + Bar()
 
     }
     interface Foo  {
-        + Foo() 
+        + Foo()
 
     }
 }
@@ -1078,3 +1337,94 @@ namespace namedimports {
 	}
 
 }
+
+func TestRenderDocComments(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/doccomments"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderDocComments: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderDocComments:    true,
+		DocCommentsMaxLength: 40,
+	})
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace doccomments {
+    class Documented << (S,Aquamarine) >> {
+        + Field int
+
+        + Public() int
+
+    }
+    interface Grouped  {
+        + Do()
+
+    }
+    class Undocumented << (S,Aquamarine) >> {
+        + Do()
+
+    }
+}
+
+"doccomments.Grouped" <|-- "doccomments.Undocumented"
+
+note top of doccomments.Documented : Documented is a struct with a doc commen...
+note right of doccomments.Documented::Public : Public returns the value of the field.
+note top of doccomments.Grouped : Grouped is declared inside a parenthesiz...
+note right of doccomments.Grouped::Do : Do does something
+note right of doccomments.Undocumented::Do : Do implements Grouped
+
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderDocComments: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
+func TestStructs(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/underlyingtypes"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestStructs: expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	for _, name := range []string{"underlyingtypes.Writer", "underlyingtypes.StringWriter", "underlyingtypes.NameWriter", "underlyingtypes.MyString", "underlyingtypes.Name"} {
+		if structs[name] == nil {
+			t.Errorf("TestStructs: expected %s to be returned", name)
+		}
+	}
+	if len(structs) != 5 {
+		t.Errorf("TestStructs: expected 5 structures, got %d", len(structs))
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport"}, []string{}, true)
+	if err != nil {
+		t.Fatalf("TestConcurrentUse: expected no error but got %s", err.Error())
+	}
+	parser.SetRenderingOptions(snapshotRenderingOptions)
+	expected := parser.Render()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%4 == 0 {
+				// setting the same options must not be seen as a change by the concurrent renders
+				parser.SetRenderingOptions(snapshotRenderingOptions)
+			}
+			if result := parser.Render(); result != expected {
+				t.Errorf("TestConcurrentUse: expected concurrent renders to be equal")
+			}
+			parser.RenderDot()
+			parser.Structs()
+			parser.Metrics()
+			parser.PackageDependencies()
+			parser.ContextlessMethods()
+			parser.GlobalVariables()
+			parser.FindReferences("testingsupport.MyStruct")
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isCollapsedPackage returns true if the given package has more types than the CollapsePackageThreshold rendering
+// option, so it is rendered as a single node
+func (p *ClassParser) isCollapsedPackage(pack string) bool {
+	threshold := p.renderingOptions.CollapsePackageThreshold
+	return threshold > 0 && len(p.structure[pack]) > threshold
+}
+
+// getCollapsedName returns the name of the node of the collapsed package of the given package qualified name, or
+// the name itself if its package is not collapsed
+func (p *ClassParser) getCollapsedName(name string) string {
+	if pack := strings.SplitN(name, ".", 2)[0]; pack != name && p.isCollapsedPackage(pack) {
+		return pack
+	}
+	return name
+}
+
+// isRenderedRelation returns true if the relationships of the given kind are rendered with the current options
+func (p *ClassParser) isRenderedRelation(kind RelationKind) bool {
+	switch kind {
+	case RelationComposition:
+		return p.renderingOptions.Compositions
+	case RelationExtends:
+		return p.renderingOptions.Implementations
+	case RelationAggregation:
+		return p.renderingOptions.Aggregations
+	case RelationPrivateAggregation:
+		return p.renderingOptions.Aggregations && p.renderingOptions.AggregatePrivateMembers
+	case RelationConversion:
+		return p.renderingOptions.Conversions
+	}
+	return true
+}
+
+// renderCollapsedPackage renders the given package as a single node with the number of its types, and a dependency
+// to every type or collapsed package its structures have a rendered relationship with
+func (p *ClassParser) renderCollapsedPackage(pack string, structures map[string]*Struct, str *LineStringBuilder) {
+	str.WriteLineWithDepth(0, fmt.Sprintf(`class %s << (P,#DDDDDD) package >> {`, pack))
+	str.WriteLineWithDepth(1, fmt.Sprintf("%d types", len(structures)))
+	str.WriteLineWithDepth(0, "}")
+	targets := map[string]struct{}{}
+	for name, st := range structures {
+		for _, relation := range getStructRelations(st, getStructFullName(st, pack, name)) {
+			target := p.getCollapsedName(relation.Target)
+			if target == pack || isBuiltinName(target) || !p.isRenderedRelation(relation.Kind) {
+				continue
+			}
+			if (relation.Kind == RelationAggregation || relation.Kind == RelationPrivateAggregation) && p.isIgnoredAggregation(target) {
+				continue
+			}
+			targets[p.getExternalName(target)] = struct{}{}
+		}
+	}
+	for _, target := range getSortedKeys(targets) {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`"%s" ..> "%s"`, pack, target))
+	}
+}
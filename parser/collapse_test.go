@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollapsePackageThreshold(t *testing.T) {
+	parser := getEmptyParser("big")
+	parser.structure["big"]["A"] = &Struct{PackageName: "big", Type: "class", Composition: map[string]struct{}{"B": {}, "small.C": {}}}
+	parser.structure["big"]["B"] = &Struct{PackageName: "big", Type: "class", Extends: map[string]struct{}{"io.Reader": {}}}
+	parser.structure["small"] = map[string]*Struct{
+		"C": {PackageName: "small", Type: "class", Composition: map[string]struct{}{"big.A": {}}},
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{CollapsePackageThreshold: 1}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if parser.isCollapsedPackage("small") {
+		t.Errorf("TestCollapsePackageThreshold: expected small not to be collapsed")
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{
+		"class big << (P,#DDDDDD) package >> {\n    2 types\n}\n",
+		`"big" ..> "small.C"`,
+		`"big" ..> "io.Reader"`,
+		`"big" *-- "small.C"`,
+		"namespace small {",
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestCollapsePackageThreshold: expected %s in %s", expected, rendered)
+		}
+	}
+	for _, unexpected := range []string{"namespace big {", `"big.A"`, `"big" ..> "big"`} {
+		if strings.Contains(rendered, unexpected) {
+			t.Errorf("TestCollapsePackageThreshold: unexpected %s in %s", unexpected, rendered)
+		}
+	}
+}
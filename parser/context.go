@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"sort"
+	"unicode"
+)
+
+const contextType = "context.Context"
+
+// ContextlessMethod is an exported method that does not take a context.Context as first parameter while most of the
+// exported methods of its package do
+type ContextlessMethod struct {
+	PackageName string
+	StructName  string
+	Method      string
+}
+
+// resolveContextlessMethods finds, in every package where most exported methods take a context.Context as first
+// parameter, the exported methods that do not
+func (p *ClassParser) resolveContextlessMethods() {
+	for _, structures := range p.structure {
+		withContext := 0
+		withoutContext := []*Function{}
+		for _, st := range structures {
+			for _, method := range st.Functions {
+				if !unicode.IsUpper(rune(method.Name[0])) {
+					continue
+				}
+				if len(method.Parameters) > 0 && method.Parameters[0].FullType == contextType {
+					withContext++
+				} else {
+					withoutContext = append(withoutContext, method)
+				}
+			}
+		}
+		if withContext <= len(withoutContext) {
+			continue
+		}
+		for _, method := range withoutContext {
+			p.contextlessMethods[method] = struct{}{}
+		}
+	}
+}
+
+// ContextlessMethods returns the exported methods that do not take a context.Context as first parameter in the
+// packages where most exported methods do, sorted by package, structure and method name
+func (p *ClassParser) ContextlessMethods() []*ContextlessMethod {
+	result := []*ContextlessMethod{}
+	for pack, structures := range p.structure {
+		for name, st := range structures {
+			for _, method := range st.Functions {
+				if _, ok := p.contextlessMethods[method]; ok {
+					result = append(result, &ContextlessMethod{
+						PackageName: pack,
+						StructName:  name,
+						Method:      method.Name,
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PackageName != result[j].PackageName {
+			return result[i].PackageName < result[j].PackageName
+		}
+		if result[i].StructName != result[j].StructName {
+			return result[i].StructName < result[j].StructName
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// getContextWarning returns the annotation rendered next to the given method when it lacks a context.Context
+func (p *ClassParser) getContextWarning(method *Function) string {
+	if !p.renderingOptions.ContextWarnings {
+		return ""
+	}
+	if _, ok := p.contextlessMethods[method]; !ok {
+		return ""
+	}
+	return "<color:red>(no context)</color>"
+}
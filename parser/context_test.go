@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextlessMethods(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/contexts"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestContextlessMethods: expected no error but got %s", err.Error())
+	}
+	expected := []*ContextlessMethod{
+		{PackageName: "contexts", StructName: "Cache", Method: "Clear"},
+		{PackageName: "contexts", StructName: "Store", Method: "Delete"},
+	}
+	if result := parser.ContextlessMethods(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("TestContextlessMethods: expected %v, got %v", expected, result)
+	}
+}
+
+func TestContextlessMethodsWithoutMajority(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/implementations"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestContextlessMethodsWithoutMajority: expected no error but got %s", err.Error())
+	}
+	if result := parser.ContextlessMethods(); len(result) != 0 {
+		t.Errorf("TestContextlessMethodsWithoutMajority: expected no methods, got %v", result)
+	}
+}
+
+func TestRenderContextWarnings(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/contexts"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestRenderContextWarnings: expected no error but got %s", err.Error())
+	}
+	st := parser.getStruct("contexts.Store")
+	private := &LineStringBuilder{}
+	public := &LineStringBuilder{}
+	parser.renderStructMethods(st, private, public)
+	expected := "        + Get(ctx context.Context, id string) (string, error)\n        + Put(ctx context.Context, id string, value string) error\n        + List(ctx context.Context) ([]string, error)\n        + Delete(id string) error\n"
+	if public.String() != expected {
+		t.Errorf("TestRenderContextWarnings: expected %s, got %s", expected, public.String())
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderContextWarnings: true})
+	public = &LineStringBuilder{}
+	parser.renderStructMethods(st, private, public)
+	expected = "        + Get(ctx context.Context, id string) (string, error)\n        + Put(ctx context.Context, id string, value string) error\n        + List(ctx context.Context) ([]string, error)\n        + Delete(id string) error <color:red>(no context)</color>\n"
+	if public.String() != expected {
+		t.Errorf("TestRenderContextWarnings: expected %s, got %s", expected, public.String())
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+const convertsTo = `"converts to"`
+
+// addConversions records the explicit conversions between named types (e.g. UserDTO(user)) found in the function
+// bodies of the given files. Both types are recorded with their package qualified name and only kept if they end
+// up being parsed (see resolveConversions).
+func (p *ClassParser) addConversions(info *types.Info, files []*ast.File) {
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			conversion, ok := info.Types[call.Fun]
+			if !ok || !conversion.IsType() {
+				return true
+			}
+			target := getNamedTypeName(conversion.Type)
+			source := getNamedTypeName(info.TypeOf(call.Args[0]))
+			if target == "" || source == "" || target == source {
+				return true
+			}
+			if _, ok := p.allConversions[source]; !ok {
+				p.allConversions[source] = map[string]struct{}{}
+			}
+			p.allConversions[source][target] = struct{}{}
+			return true
+		})
+	}
+}
+
+// getNamedTypeName returns the package qualified name of the given named type (or pointer to it). Any other type
+// returns an empty string.
+func getNamedTypeName(t types.Type) string {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", named.Obj().Pkg().Name(), named.Obj().Name())
+}
+
+// resolveConversions adds the recorded conversions to the parsed structures when both sides of the conversion
+// were parsed
+func (p *ClassParser) resolveConversions() {
+	for source, targets := range p.allConversions {
+		st := p.getStructByFullName(source)
+		if st == nil {
+			continue
+		}
+		for target := range targets {
+			if p.getStructByFullName(target) != nil {
+				st.AddToConversions(target)
+			}
+		}
+	}
+}
+
+// getStructByFullName returns the structure with the given package qualified name. Unlike getStruct, it also finds
+// aliases and enums, which are stored with their package name.
+func (p *ClassParser) getStructByFullName(fullName string) *Struct {
+	if st := p.getStruct(fullName); st != nil {
+		return st
+	}
+	split := strings.SplitN(fullName, ".", 2)
+	return p.structure[split[0]][fullName]
+}
+
+func (p *ClassParser) renderConversions(structure *Struct, fullName string, conversions *LineStringBuilder) {
+	convertsToString := ""
+	if p.renderingOptions.ConnectionLabels {
+		convertsToString = convertsTo
+	}
+	for _, target := range getSortedKeys(structure.Conversions) {
+		conversions.WriteLineWithDepth(0, fmt.Sprintf(`"%s" ..> %s"%s"`, fullName, convertsToString, p.getCollapsedName(target)))
+	}
+}
+
+func (p *ClassParser) renderDotConversions(structure *Struct, id string, edges *LineStringBuilder) {
+	label := p.getDotEdgeLabel(convertsTo)
+	for _, target := range getSortedKeys(structure.Conversions) {
+		edges.WriteLineWithDepth(1, fmt.Sprintf(`"%s" -> "%s" [style=dashed, arrowhead=open%s];`, id, target, label))
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderConversions(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/conversions"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderConversions: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderConversions:      true,
+		RenderConnectionLabels: true,
+		RenderFields:           false,
+		RenderMethods:          false,
+	})
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace conversions {
+    class User << (S,Aquamarine) >> {
+        + Name string
+
+        + ToDTO() *UserDTO
+
+    }
+    class UserDTO << (S,Aquamarine) >> {
+        + Name string
+
+    }
+    class conversions.Status << (T, #FF7700) >>  {
+    }
+}
+
+
+"conversions.User" ..> "converts to""conversions.UserDTO"
+"conversions.UserDTO" ..> "converts to""conversions.User"
+
+hide fields
+hide methods
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderConversions: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
+func TestConversionsNotRenderedByDefault(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/conversions"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestConversionsNotRenderedByDefault: expected no error but got %s", err.Error())
+		return
+	}
+	st := parser.getStruct("conversions.User")
+	if _, ok := st.Conversions["conversions.UserDTO"]; !ok {
+		t.Errorf("TestConversionsNotRenderedByDefault: expected conversions.User to be converted to conversions.UserDTO, got %v", st.Conversions)
+	}
+	result := parser.RenderDot()
+	if strings.Contains(result, "style=dashed, arrowhead=open") {
+		t.Errorf("TestConversionsNotRenderedByDefault: expected no conversion edges, got \n%s\n", result)
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// customRelationArrows are the arrows accepted between the types of a custom relationship and the PlantUML arrows
+// they are rendered with
+var customRelationArrows = map[string]string{
+	"->":   "-->",
+	"-->":  "-->",
+	"..>":  "..>",
+	"<|--": "<|--",
+	"*--":  "*--",
+	"o--":  "o--",
+}
+
+// CustomRelation is a relationship that cannot be found in the code (e.g. a service publishing to a queue client)
+// rendered with the parsed ones when given with the CustomRelations rendering option
+type CustomRelation struct {
+	// Source and Target are package qualified names (e.g. orders.Service). The package may be given by its import
+	// path (e.g. github.com/shop/orders.Service)
+	Source string
+	Target string
+	// Arrow is one of ->, -->, ..>, <|--, *-- and o--
+	Arrow string
+	Label string
+}
+
+// ParseCustomRelations parses the relationships written one per line as source arrow target, optionally followed by
+// a colon and a label (e.g. orders.Service -> queue.Client : publishes). Empty lines and lines starting with # are
+// skipped.
+func ParseCustomRelations(content string) ([]*CustomRelation, error) {
+	relations := []*CustomRelation{}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		label := ""
+		if split := strings.SplitN(line, ":", 2); len(split) == 2 {
+			line = strings.TrimSpace(split[0])
+			label = strings.TrimSpace(split[1])
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid relationship on line %d: expected source arrow target", i+1)
+		}
+		if _, ok := customRelationArrows[fields[1]]; !ok {
+			return nil, fmt.Errorf("invalid relationship on line %d: unknown arrow %s", i+1, fields[1])
+		}
+		relations = append(relations, &CustomRelation{Source: fields[0], Arrow: fields[1], Target: fields[2], Label: label})
+	}
+	return relations, nil
+}
+
+// getCustomRelationName returns the name the type is rendered with, dropping the import path of its package
+func (p *ClassParser) getCustomRelationName(name string) string {
+	if index := strings.LastIndex(name, "/"); index >= 0 {
+		name = name[index+1:]
+	}
+	return p.getExternalName(p.getCollapsedName(name))
+}
+
+// renderCustomRelations renders the relationships given with the CustomRelations rendering option, sorted so the
+// diagram does not depend on their order
+func (p *ClassParser) renderCustomRelations(str *LineStringBuilder) {
+	lines := []string{}
+	for _, relation := range p.renderingOptions.CustomRelations {
+		line := fmt.Sprintf(`"%s" %s "%s"`, p.getCustomRelationName(relation.Source), customRelationArrows[relation.Arrow], p.getCustomRelationName(relation.Target))
+		if relation.Label != "" {
+			line = fmt.Sprintf("%s : %s", line, relation.Label)
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		str.WriteLineWithDepth(0, line)
+	}
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCustomRelations(t *testing.T) {
+	content := `# domain knowledge
+github.com/shop/orders.Service -> queue.Client : publishes
+
+billing.Invoice ..> orders.Order
+`
+	expected := []*CustomRelation{
+		{Source: "github.com/shop/orders.Service", Arrow: "->", Target: "queue.Client", Label: "publishes"},
+		{Source: "billing.Invoice", Arrow: "..>", Target: "orders.Order"},
+	}
+	relations, err := ParseCustomRelations(content)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if !reflect.DeepEqual(relations, expected) {
+		t.Errorf("TestParseCustomRelations: expected %v, got %v", expected, relations)
+	}
+	for _, invalid := range []string{"orders.Service queue.Client", "orders.Service => queue.Client"} {
+		if _, err := ParseCustomRelations(invalid); err == nil {
+			t.Errorf("TestParseCustomRelations: expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestRenderCustomRelations(t *testing.T) {
+	parser := getEmptyParser("orders")
+	parser.allExternals = map[string]*Struct{"queue.Client": {PackageName: "queue"}}
+	relations, err := ParseCustomRelations("github.com/shop/orders.Service -> queue.Client : publishes\nbilling.Invoice ..> orders.Order")
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{CustomRelations: relations}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	str := &LineStringBuilder{}
+	parser.renderCustomRelations(str)
+	expected := `"billing.Invoice" ..> "orders.Order"
+"orders.Service" --> "external.queue.Client" : publishes
+`
+	if str.String() != expected {
+		t.Errorf("TestRenderCustomRelations: expected %s, got %s", expected, str.String())
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// PackageCycles returns the groups of packages depending on each other, directly or not, through the dependencies
+// of PackageDependencies. Every group is sorted and so are the groups. There are none when the packages can be
+// layered.
+func (p *ClassParser) PackageCycles() [][]string {
+	return getCycles(p.PackageDependencies())
+}
+
+// getCycles returns the strongly connected components of more than one node of the given graph, found with the
+// algorithm of Tarjan
+func getCycles(graph map[string][]string) [][]string {
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	cycles := [][]string{}
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, next := range graph[node] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowLink[node] = minInt(lowLink[node], lowLink[next])
+			} else if onStack[next] {
+				lowLink[node] = minInt(lowLink[node], index[next])
+			}
+		}
+		if lowLink[node] != index[node] {
+			return
+		}
+		component := []string{}
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	nodes := make([]string, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		if _, visited := index[node]; !visited {
+			visit(node)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// getCyclicPackages returns the packages in a cycle mapped to the index of their cycle in PackageCycles
+func (p *ClassParser) getCyclicPackages() map[string]int {
+	cyclic := map[string]int{}
+	for i, cycle := range p.PackageCycles() {
+		for _, pack := range cycle {
+			cyclic[pack] = i
+		}
+	}
+	return cyclic
+}
+
+// getCycleArrow returns the given arrow (e.g. *--) colored in red when RenderCycles is set and the packages of both
+// ends of the edge, given by their package qualified names, are in the same cycle
+func (p *ClassParser) getCycleArrow(arrow string, from string, to string) string {
+	if !p.renderingOptions.Cycles {
+		return arrow
+	}
+	fromCycle, ok := p.cyclicPackages[strings.SplitN(from, ".", 2)[0]]
+	if !ok {
+		return arrow
+	}
+	toPackage := strings.SplitN(to, ".", 2)[0]
+	if toCycle, ok := p.cyclicPackages[toPackage]; !ok || toCycle != fromCycle || toPackage == strings.SplitN(from, ".", 2)[0] {
+		return arrow
+	}
+	return strings.Replace(arrow, "-", "-[#red]", 1)
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetCycles(t *testing.T) {
+	graph := map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a", "d"},
+		"d": {},
+		"e": {"f"},
+		"f": {"e"},
+		"g": {"g"},
+	}
+	expected := [][]string{{"a", "b", "c"}, {"e", "f"}}
+	if cycles := getCycles(graph); !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("TestGetCycles: expected %v, got %v", expected, cycles)
+	}
+	if cycles := getCycles(map[string][]string{"a": {"b"}, "b": {}}); len(cycles) != 0 {
+		t.Errorf("TestGetCycles: expected no cycles, got %v", cycles)
+	}
+}
+
+func TestPackageCycles(t *testing.T) {
+	parser := getEmptyParser("a")
+	parser.structure["a"]["A"] = &Struct{PackageName: "a", Type: "class", Composition: map[string]struct{}{"b.B": {}}}
+	parser.structure["b"] = map[string]*Struct{
+		"B": {PackageName: "b", Type: "class", Composition: map[string]struct{}{"a.A": {}}},
+	}
+	parser.structure["c"] = map[string]*Struct{
+		"C": {PackageName: "c", Type: "class", Composition: map[string]struct{}{"a.A": {}}},
+	}
+	expected := [][]string{{"a", "b"}}
+	if cycles := parser.PackageCycles(); !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("TestPackageCycles: expected %v, got %v", expected, cycles)
+	}
+	if arrow := parser.getCycleArrow("*--", "a.A", "b.B"); arrow != "*--" {
+		t.Errorf("TestPackageCycles: expected *-- without RenderCycles, got %s", arrow)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderCycles: true}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	tt := []struct {
+		from     string
+		to       string
+		expected string
+	}{
+		{from: "a.A", to: "b.B", expected: "*-[#red]-"},
+		{from: "b.B", to: "a.A", expected: "*-[#red]-"},
+		{from: "c.C", to: "a.A", expected: "*--"},
+		{from: "a.A", to: "a.A", expected: "*--"},
+	}
+	for _, tc := range tt {
+		if arrow := parser.getCycleArrow("*--", tc.from, tc.to); arrow != tc.expected {
+			t.Errorf("TestPackageCycles: expected %s from %s to %s, got %s", tc.expected, tc.from, tc.to, arrow)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+const dependsOn = `"depends on"`
+
+// addDependencies records the named types whose methods are called (e.g. s.repository.Save(user)) or that are
+// constructed (e.g. &Client{}) in the methods of the given files. Both types are recorded with their package qualified
+// name and only kept if they end up being parsed (see resolveDependencies).
+func (p *ClassParser) addDependencies(info *types.Info, files []*ast.File) {
+	for _, file := range files {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Recv == nil || len(decl.Recv.List) == 0 || decl.Body == nil {
+				continue
+			}
+			source := getNamedTypeName(info.TypeOf(decl.Recv.List[0].Type))
+			if source == "" {
+				continue
+			}
+			ast.Inspect(decl.Body, func(node ast.Node) bool {
+				p.addDependency(source, getDependencyTarget(info, node))
+				return true
+			})
+		}
+	}
+}
+
+// getDependencyTarget returns the package qualified name of the type whose method is called or which is constructed
+// by the given node, or an empty string
+func getDependencyTarget(info *types.Info, node ast.Node) string {
+	switch v := node.(type) {
+	case *ast.SelectorExpr:
+		if selection, ok := info.Selections[v]; ok && selection.Kind() == types.MethodVal {
+			return getNamedTypeName(selection.Recv())
+		}
+	case *ast.CompositeLit:
+		return getNamedTypeName(info.TypeOf(v))
+	}
+	return ""
+}
+
+func (p *ClassParser) addDependency(source string, target string) {
+	if target == "" || target == source {
+		return
+	}
+	if _, ok := p.allDependencies[source]; !ok {
+		p.allDependencies[source] = map[string]struct{}{}
+	}
+	p.allDependencies[source][target] = struct{}{}
+}
+
+// resolveDependencies adds the recorded dependencies to the parsed structures when both types were parsed
+func (p *ClassParser) resolveDependencies() {
+	for source, targets := range p.allDependencies {
+		st := p.getStructByFullName(source)
+		if st == nil {
+			continue
+		}
+		for target := range targets {
+			if p.getStructByFullName(target) != nil {
+				st.AddToDependencies(target)
+			}
+		}
+	}
+}
+
+// getRenderedDependencies returns the sorted dependencies of the structure that are not already rendered as a
+// composition, implementation or aggregation
+func (p *ClassParser) getRenderedDependencies(structure *Struct) []string {
+	if !p.renderingOptions.Dependencies {
+		return nil
+	}
+	relationships := []map[string]struct{}{}
+	if p.renderingOptions.Compositions {
+		relationships = append(relationships, structure.Composition)
+	}
+	if p.renderingOptions.Implementations {
+		relationships = append(relationships, structure.Extends)
+	}
+	if p.renderingOptions.Aggregations {
+		relationships = append(relationships, structure.Aggregations)
+		if p.renderingOptions.AggregatePrivateMembers {
+			relationships = append(relationships, structure.PrivateAggregations)
+		}
+	}
+	rendered := map[string]struct{}{}
+	for target := range mergeSets(relationships...) {
+		target = strings.TrimPrefix(target, "*")
+		if !strings.Contains(target, ".") {
+			target = fmt.Sprintf("%s.%s", structure.PackageName, target)
+		}
+		rendered[target] = struct{}{}
+	}
+	dependencies := []string{}
+	for _, target := range getSortedKeys(structure.Dependencies) {
+		if _, ok := rendered[target]; !ok {
+			dependencies = append(dependencies, target)
+		}
+	}
+	return dependencies
+}
+
+func (p *ClassParser) renderDependencies(structure *Struct, fullName string, dependencies *LineStringBuilder) {
+	dependsOnString := ""
+	if p.renderingOptions.ConnectionLabels {
+		dependsOnString = dependsOn
+	}
+	for _, target := range p.getRenderedDependencies(structure) {
+		dependencies.WriteLineWithDepth(0, fmt.Sprintf(`"%s" ..> %s"%s"`, fullName, dependsOnString, p.getCollapsedName(target)))
+	}
+}
+
+func (p *ClassParser) renderDotDependencies(structure *Struct, id string, edges *LineStringBuilder) {
+	label := p.getDotEdgeLabel(dependsOn)
+	for _, target := range p.getRenderedDependencies(structure) {
+		edges.WriteLineWithDepth(1, fmt.Sprintf(`"%s" -> "%s" [style=dashed, arrowhead=open%s];`, id, target, label))
+	}
+}
@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func getDependenciesParser(t *testing.T, findDependencies bool) *ClassParser {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/dependencies"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		FindDependencies:   findDependencies,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	return parser
+}
+
+func TestDependencies(t *testing.T) {
+	parser := getDependenciesParser(t, true)
+	expected := map[string]struct{}{
+		"dependencies.User":       {},
+		"dependencies.Repository": {},
+		"dependencies.Mailer":     {},
+	}
+	if dependencies := parser.getStruct("dependencies.Service").Dependencies; !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("TestDependencies: expected %v, got %v", expected, dependencies)
+	}
+	if dependencies := parser.getStruct("dependencies.Repository").Dependencies; len(dependencies) != 0 {
+		t.Errorf("TestDependencies: expected Repository to have no dependencies, got %v", dependencies)
+	}
+	if dependencies := getDependenciesParser(t, false).getStruct("dependencies.Service").Dependencies; len(dependencies) != 0 {
+		t.Errorf("TestDependencies: expected no dependencies without FindDependencies, got %v", dependencies)
+	}
+}
+
+func TestRenderDependencies(t *testing.T) {
+	tt := []struct {
+		Name        string
+		Options     map[RenderingOption]interface{}
+		Expected    []string
+		NotExpected []string
+	}{
+		{
+			Name:    "disabled",
+			Options: map[RenderingOption]interface{}{},
+			NotExpected: []string{
+				`"dependencies.Service" ..> "dependencies.User"`,
+			},
+		},
+		{
+			Name:    "without aggregations",
+			Options: map[RenderingOption]interface{}{RenderDependencies: true},
+			Expected: []string{
+				`"dependencies.Service" ..> "dependencies.Mailer"`,
+				`"dependencies.Service" ..> "dependencies.Repository"`,
+				`"dependencies.Service" ..> "dependencies.User"`,
+			},
+		},
+		{
+			Name:     "with private aggregations",
+			Options:  map[RenderingOption]interface{}{RenderDependencies: true, RenderAggregations: true, AggregatePrivateMembers: true, RenderConnectionLabels: true},
+			Expected: []string{`"dependencies.Service" ..> "depends on""dependencies.User"`},
+			NotExpected: []string{
+				`"dependencies.Service" ..> "depends on""dependencies.Mailer"`,
+				`"dependencies.Service" ..> "depends on""dependencies.Repository"`,
+			},
+		},
+	}
+	parser := getDependenciesParser(t, true)
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderDependencies: false, RenderAggregations: false, AggregatePrivateMembers: false, RenderConnectionLabels: false})
+			parser.SetRenderingOptions(tc.Options)
+			result := parser.Render()
+			for _, expected := range tc.Expected {
+				if !strings.Contains(result, expected+"\n") {
+					t.Errorf("expected the render to contain %s, got %s", expected, result)
+				}
+			}
+			for _, notExpected := range tc.NotExpected {
+				if strings.Contains(result, notExpected) {
+					t.Errorf("expected the render not to contain %s, got %s", notExpected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderDotDependencies(t *testing.T) {
+	parser := getDependenciesParser(t, true)
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderDependencies: true})
+	expected := `"dependencies.Service" -> "dependencies.User" [style=dashed, arrowhead=open];`
+	if result := parser.RenderDot(); !strings.Contains(result, expected) {
+		t.Errorf("TestRenderDotDependencies: expected the render to contain %s, got %s", expected, result)
+	}
+}
+
+const removedDependenciesSource = `package dep
+
+type Repo struct{}
+
+func (r *Repo) Save() {}
+
+//plantuml:ignore
+type Cache struct{}
+
+func (c *Cache) Get() {}
+
+type Service struct{}
+
+func (s *Service) Run(r *Repo, c *Cache) {
+	r.Save()
+	c.Get()
+}
+`
+
+func TestRemovedTypesDependencies(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"dep/dep.go": removedDependenciesSource}, &ClassDiagramOptions{
+		FindDependencies: true,
+		ExcludeTypes:     regexp.MustCompile(`Repo$`),
+		RenderingOptions: map[RenderingOption]interface{}{RenderDependencies: true},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if dependencies := parser.getStruct("dep.Service").Dependencies; len(dependencies) != 0 {
+		t.Errorf("TestRemovedTypesDependencies: expected the dependencies on the removed types to be pruned, got %v", dependencies)
+	}
+	rendered := parser.Render()
+	for _, unexpected := range []string{`"dep.Service" ..> "dep.Repo"`, `"dep.Service" ..> "dep.Cache"`} {
+		if strings.Contains(rendered, unexpected) {
+			t.Errorf("TestRemovedTypesDependencies: expected no %s in\n%s", unexpected, rendered)
+		}
+	}
+}
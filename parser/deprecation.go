@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// deprecatedPrefix starts the paragraph of the doc comments of the deprecated identifiers, like the go tools expect
+const deprecatedPrefix = "Deprecated:"
+
+// isDeprecated returns true if the given doc comment has a paragraph starting with Deprecated:
+func isDeprecated(doc string) bool {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(strings.TrimSpace(paragraph), deprecatedPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDeprecatedStereotype adds the deprecated stereotype to the given one of a deprecated structure, after its spot
+// and stereotypes if it has some (e.g. << (S,Aquamarine) deprecated >>)
+func (p *ClassParser) getDeprecatedStereotype(structure *Struct, stereotype string) string {
+	if !p.renderingOptions.Deprecations || !isDeprecated(structure.Doc) {
+		return stereotype
+	}
+	return addStereotype(stereotype, "deprecated")
+}
+
+// getDeprecatedSignature returns the given rendered signature of a method struck through if it is deprecated
+func (p *ClassParser) getDeprecatedSignature(method *Function, signature string) string {
+	if !p.renderingOptions.Deprecations || !isDeprecated(method.Doc) {
+		return signature
+	}
+	return fmt.Sprintf("<s>%s</s>", signature)
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const deprecationsSource = `package client
+
+// Client calls the API.
+//
+// Deprecated: use ClientV2 instead.
+type Client struct{}
+
+// Get gets the resource.
+//
+// Deprecated: use Fetch.
+func (c *Client) Get() error {
+	return nil
+}
+
+// Fetch fetches the resource
+func (c *Client) Fetch() error {
+	return nil
+}
+
+// Getter is deprecated, see the note.
+type Getter interface {
+	Get() error
+}
+
+// Deprecated: use Getter.
+type OldGetter interface {
+	Get() error
+}
+`
+
+func TestIsDeprecated(t *testing.T) {
+	tt := []struct {
+		Doc      string
+		Expected bool
+	}{
+		{Doc: "Deprecated: use New", Expected: true},
+		{Doc: "Client calls the API.\n\nDeprecated: use ClientV2.", Expected: true},
+		{Doc: "Client is deprecated.", Expected: false},
+		{Doc: "Client calls the API.\nDeprecated: not a paragraph", Expected: false},
+		{Doc: "", Expected: false},
+	}
+	for _, tc := range tt {
+		if result := isDeprecated(tc.Doc); result != tc.Expected {
+			t.Errorf("TestIsDeprecated: expected %t for %q, got %t", tc.Expected, tc.Doc, result)
+		}
+	}
+}
+
+func TestRenderDeprecations(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"client/client.go": deprecationsSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if rendered := parser.Render(); strings.Contains(rendered, "deprecated") || strings.Contains(rendered, "<s>") {
+		t.Errorf("TestRenderDeprecations: expected no deprecation by default, got %s", rendered)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderDeprecations: true}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{
+		"class Client << (S,Aquamarine) deprecated >> {",
+		"+ <s>Get() error</s>\n",
+		"+ Fetch() error\n",
+		"interface Getter  {",
+		"interface OldGetter <<deprecated>> {",
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestRenderDeprecations: expected %q in %s", expected, rendered)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jfeliu007/goplantuml/parser"
+)
+
+// API is the public surface of an exported type
+type API struct {
+	// Kind is the type of the structure (class, interface, alias or enum)
+	Kind string `json:"kind"`
+	// Members are the exported fields, methods and enum values keyed like the ones of Type (e.g. method Render)
+	Members map[string]string `json:"members"`
+}
+
+// Baseline is the approved architecture of a tree, usually committed as JSON so CI can check that no dependency
+// between packages and no public API is added without updating it
+type Baseline struct {
+	// Dependencies are the sorted names of the packages every package depends on
+	Dependencies map[string][]string `json:"dependencies"`
+	// API are the exported types keyed by their package qualified name
+	API map[string]*API `json:"api"`
+}
+
+// NewBaseline returns the baseline of the structures found by the given parser
+func NewBaseline(p *parser.ClassParser) *Baseline {
+	baseline := &Baseline{
+		Dependencies: p.PackageDependencies(),
+		API:          map[string]*API{},
+	}
+	for name, t := range NewModel(p) {
+		if !isExported(name) {
+			continue
+		}
+		api := &API{Kind: t.Kind, Members: map[string]string{}}
+		for member, definition := range t.Members {
+			if isExported(member) {
+				api.Members[member] = definition
+			}
+		}
+		baseline.API[name] = api
+	}
+	return baseline
+}
+
+// ReadBaseline decodes a baseline written by WriteJSON
+func ReadBaseline(r io.Reader) (*Baseline, error) {
+	baseline := &Baseline{}
+	if err := json.NewDecoder(r).Decode(baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %s", err.Error())
+	}
+	return baseline, nil
+}
+
+// WriteJSON encodes the baseline as indented JSON, with sorted keys so it can be reviewed and diffed
+func (b *Baseline) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(b)
+}
+
+// CheckBaseline returns the dependencies and public API of the current baseline that are not in the approved one.
+// New dependencies are changes to the depending package (e.g. + parser depends on diff). Removed things are not
+// reported since they do not grow the architecture.
+func CheckBaseline(approved *Baseline, current *Baseline) []*Change {
+	changes := []*Change{}
+	packages := make([]string, 0, len(current.Dependencies))
+	for pack := range current.Dependencies {
+		packages = append(packages, pack)
+	}
+	sort.Strings(packages)
+	for _, pack := range packages {
+		allowed := map[string]struct{}{}
+		for _, dependency := range approved.Dependencies[pack] {
+			allowed[dependency] = struct{}{}
+		}
+		for _, dependency := range current.Dependencies[pack] {
+			if _, ok := allowed[dependency]; !ok {
+				changes = append(changes, &Change{Kind: Added, Type: pack, Member: "depends on " + dependency})
+			}
+		}
+	}
+	names := make([]string, 0, len(current.API))
+	for name := range current.API {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		api := current.API[name]
+		approvedAPI, ok := approved.API[name]
+		if !ok {
+			changes = append(changes, &Change{Kind: Added, Type: name, After: api.Kind})
+			approvedAPI = &API{}
+		}
+		for _, change := range compareMembers(name, approvedAPI.Members, api.Members) {
+			if change.Kind != Removed {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+// isExported returns true if the last identifier of the given type name (e.g. parser.Struct) or member key
+// (e.g. method Render) is exported
+func isExported(name string) bool {
+	name = name[strings.LastIndexAny(name, ". ")+1:]
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
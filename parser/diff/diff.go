@@ -0,0 +1,235 @@
+/*
+Package diff compares the structures parsed by github.com/jfeliu007/goplantuml/parser from two trees (e.g. two
+revisions of the same repository) and reports the types, fields, methods and relationships that were added, removed or
+changed, either as text or as a PlantUML diagram where the changes are color coded.
+
+It can also check a tree against an approved Baseline of its package dependencies and public API, to fail CI when the
+architecture grows without the baseline being updated.
+*/
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfeliu007/goplantuml/parser"
+)
+
+// Kind tells if something was added, removed or changed
+type Kind string
+
+const (
+	// Added is the kind of the changes to things that only exist in the second tree
+	Added Kind = "added"
+	// Removed is the kind of the changes to things that only exist in the first tree
+	Removed Kind = "removed"
+	// Changed is the kind of the changes to things that exist in both trees with a different definition
+	Changed Kind = "changed"
+)
+
+// markup matches the PlantUML formatting of the rendered types (e.g. <font color=blue>map</font>)
+var markup = regexp.MustCompile(`<[^>]*>`)
+
+var kindSymbols = map[Kind]string{
+	Added:   "+",
+	Removed: "-",
+	Changed: "~",
+}
+
+// Type is the comparable representation of a parsed structure
+type Type struct {
+	// Kind is the type of the structure (class, interface, alias or enum)
+	Kind string
+	// Members are the rendered fields, methods and enum values keyed by their kind and name (e.g. field Name)
+	Members map[string]string
+	// Relationships are the connections to other types (e.g. extends parser.Renderer)
+	Relationships map[string]struct{}
+}
+
+// Model is the comparable representation of a parsed tree. Types are keyed by their package qualified name.
+type Model map[string]*Type
+
+// Change is a difference between two models
+type Change struct {
+	Kind Kind
+	// Type is the package qualified name of the type the change belongs to
+	Type string
+	// Member is the changed member (e.g. method Render) or relationship (e.g. extends parser.Renderer). It is empty
+	// when the type itself was added, removed or changed.
+	Member string
+	// Before is the definition in the first model, empty if it was added or is a relationship
+	Before string
+	// After is the definition in the second model, empty if it was removed or is a relationship
+	After string
+}
+
+// String returns a single line description of the change (e.g. ~ parser.Struct method Render: Render() -> Render(int))
+func (c *Change) String() string {
+	result := fmt.Sprintf("%s %s", kindSymbols[c.Kind], c.Type)
+	if c.Member != "" {
+		result = fmt.Sprintf("%s %s", result, c.Member)
+	}
+	switch {
+	case c.Kind == Changed:
+		return fmt.Sprintf("%s: %s -> %s", result, c.Before, c.After)
+	case c.Member == "":
+		return fmt.Sprintf("%s (%s%s)", result, c.Before, c.After)
+	case c.Before != "" || c.After != "":
+		return fmt.Sprintf("%s: %s%s", result, c.Before, c.After)
+	}
+	return result
+}
+
+// NewModel returns the comparable representation of the structures found by the given parser
+func NewModel(p *parser.ClassParser) Model {
+	model := Model{}
+	for name, st := range p.Structs() {
+		t := &Type{
+			Kind:          st.Type,
+			Members:       map[string]string{},
+			Relationships: map[string]struct{}{},
+		}
+		for _, field := range st.Fields {
+			t.Members["field "+field.Name] = fmt.Sprintf("%s %s", field.Name, markup.ReplaceAllString(field.Type, ""))
+		}
+		for _, function := range st.Functions {
+			t.Members["method "+function.Name] = getSignature(function)
+		}
+		for _, value := range st.EnumValues {
+			t.Members["value "+value.Name] = value.String()
+		}
+		for kind, relationships := range map[string]map[string]struct{}{
+			"extends":    st.Extends,
+			"composes":   st.Composition,
+			"aggregates": st.Aggregations,
+			"converts":   st.Conversions,
+		} {
+			for target := range relationships {
+				t.Relationships[fmt.Sprintf("%s %s", kind, getFullName(target, st.PackageName))] = struct{}{}
+			}
+		}
+		model[name] = t
+	}
+	return model
+}
+
+func getSignature(function *parser.Function) string {
+	parameters := make([]string, 0, len(function.Parameters))
+	for _, parameter := range function.Parameters {
+		parameters = append(parameters, markup.ReplaceAllString(parameter.Type, ""))
+	}
+	signature := fmt.Sprintf("%s(%s)", function.Name, strings.Join(parameters, ", "))
+	returnValues := markup.ReplaceAllString(strings.Join(function.ReturnValues, ", "), "")
+	switch len(function.ReturnValues) {
+	case 0:
+		return signature
+	case 1:
+		return fmt.Sprintf("%s %s", signature, returnValues)
+	}
+	return fmt.Sprintf("%s (%s)", signature, returnValues)
+}
+
+func getFullName(target string, pack string) string {
+	target = strings.TrimPrefix(target, "*")
+	if !strings.Contains(target, ".") {
+		return fmt.Sprintf("%s.%s", pack, target)
+	}
+	return target
+}
+
+// Compare returns the changes needed to go from the before model to the after one, sorted by type and member
+func Compare(before Model, after Model) []*Change {
+	changes := []*Change{}
+	for _, name := range getSortedTypeNames(before, after) {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+		switch {
+		case !inBefore:
+			changes = append(changes, &Change{Kind: Added, Type: name, After: a.Kind})
+			b = &Type{}
+		case !inAfter:
+			changes = append(changes, &Change{Kind: Removed, Type: name, Before: b.Kind})
+			a = &Type{}
+		default:
+			if a.Kind != b.Kind {
+				changes = append(changes, &Change{Kind: Changed, Type: name, Before: b.Kind, After: a.Kind})
+			}
+			changes = append(changes, compareMembers(name, b.Members, a.Members)...)
+		}
+		changes = append(changes, compareRelationships(name, b.Relationships, a.Relationships)...)
+	}
+	return changes
+}
+
+func compareMembers(name string, before map[string]string, after map[string]string) []*Change {
+	changes := []*Change{}
+	for _, member := range getSortedKeys(before, after) {
+		b, inBefore := before[member]
+		a, inAfter := after[member]
+		switch {
+		case !inBefore:
+			changes = append(changes, &Change{Kind: Added, Type: name, Member: member, After: a})
+		case !inAfter:
+			changes = append(changes, &Change{Kind: Removed, Type: name, Member: member, Before: b})
+		case a != b:
+			changes = append(changes, &Change{Kind: Changed, Type: name, Member: member, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+func compareRelationships(name string, before map[string]struct{}, after map[string]struct{}) []*Change {
+	changes := []*Change{}
+	all := map[string]struct{}{}
+	for relationship := range before {
+		all[relationship] = struct{}{}
+	}
+	for relationship := range after {
+		all[relationship] = struct{}{}
+	}
+	relationships := make([]string, 0, len(all))
+	for relationship := range all {
+		relationships = append(relationships, relationship)
+	}
+	sort.Strings(relationships)
+	for _, relationship := range relationships {
+		_, inBefore := before[relationship]
+		_, inAfter := after[relationship]
+		if !inBefore {
+			changes = append(changes, &Change{Kind: Added, Type: name, Member: relationship})
+		} else if !inAfter {
+			changes = append(changes, &Change{Kind: Removed, Type: name, Member: relationship})
+		}
+	}
+	return changes
+}
+
+func getSortedTypeNames(before Model, after Model) []string {
+	names := []string{}
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getSortedKeys(before map[string]string, after map[string]string) []string {
+	keys := []string{}
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,208 @@
+package diff
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/jfeliu007/goplantuml/parser"
+)
+
+func getTestModels() (Model, Model) {
+	before := Model{
+		"main.Foo": {
+			Kind: "class",
+			Members: map[string]string{
+				"field Age":    "Age int",
+				"method Write": "Write(string) error",
+			},
+			Relationships: map[string]struct{}{"extends main.Writer": {}},
+		},
+		"main.Old": {
+			Kind:          "class",
+			Members:       map[string]string{"field Name": "Name string"},
+			Relationships: map[string]struct{}{},
+		},
+	}
+	after := Model{
+		"main.Foo": {
+			Kind: "class",
+			Members: map[string]string{
+				"field Age":  "Age int64",
+				"method Run": "Run()",
+			},
+			Relationships: map[string]struct{}{},
+		},
+		"main.New": {
+			Kind:          "interface",
+			Members:       map[string]string{},
+			Relationships: map[string]struct{}{"aggregates main.Writer": {}},
+		},
+	}
+	return before, after
+}
+
+func TestNewModel(t *testing.T) {
+	p, err := parser.NewClassDiagram([]string{"../../testingsupport/underlyingtypes"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	model := NewModel(p)
+	expected := &Type{
+		Kind:          "class",
+		Members:       map[string]string{"method Write": "Write(MyString) error"},
+		Relationships: map[string]struct{}{},
+	}
+	if !reflect.DeepEqual(model["underlyingtypes.StringWriter"], expected) {
+		t.Errorf("expected %v, got %v", expected, model["underlyingtypes.StringWriter"])
+	}
+	if _, ok := model["underlyingtypes.NameWriter"].Relationships["extends underlyingtypes.Writer"]; !ok {
+		t.Errorf("expected underlyingtypes.NameWriter to extend underlyingtypes.Writer")
+	}
+	if model["underlyingtypes.MyString"] == nil {
+		t.Errorf("expected the alias underlyingtypes.MyString to be in the model")
+	}
+}
+
+// definedSliceSource declares a slice type with methods, stored both as an alias and as the holder of its methods
+const definedSliceSource = `package items
+
+type Item struct {
+	Name string
+}
+
+type Items []Item
+
+func (s Items) Len() int { return len(s) }
+
+func (s Items) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+func (s Items) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+`
+
+func TestNewModelIsDeterministic(t *testing.T) {
+	var first Model
+	for i := 0; i < 20; i++ {
+		p, err := parser.NewClassDiagramFromSources(map[string]string{"items/items.go": definedSliceSource}, &parser.ClassDiagramOptions{})
+		if err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		model := NewModel(p)
+		if first == nil {
+			first = model
+			continue
+		}
+		if changes := Compare(first, model); len(changes) != 0 {
+			t.Fatalf("expected the models of the same code to be equal, got %s", RenderText(changes))
+		}
+	}
+	items := first["items.Items"]
+	if items == nil || items.Kind != "alias" || items.Members["method Len"] != "Len() int" {
+		t.Errorf("expected items.Items to be an alias with the method Len, got %v", items)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before, after := getTestModels()
+	expected := []string{
+		"~ main.Foo field Age: Age int -> Age int64",
+		"+ main.Foo method Run: Run()",
+		"- main.Foo method Write: Write(string) error",
+		"- main.Foo extends main.Writer",
+		"+ main.New (interface)",
+		"+ main.New aggregates main.Writer",
+		"- main.Old (class)",
+	}
+	changes := Compare(before, after)
+	result := []string{}
+	for _, change := range changes {
+		result = append(result, change.String())
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if len(Compare(after, after)) != 0 {
+		t.Errorf("expected no changes comparing a model with itself")
+	}
+}
+
+func TestRenderPlantUML(t *testing.T) {
+	before, after := getTestModels()
+	expected := `@startuml
+set separator none
+class "main.Foo" #khaki {
+    <color:orange>~ Age int -> Age int64</color>
+    <color:green>+ Run()</color>
+    <color:red>- Write(string) error</color>
+}
+interface "main.New" <<added>> #palegreen {
+}
+class "main.Old" <<removed>> #pink {
+    <color:red>- Name string</color>
+}
+"main.Foo" --> "main.Writer" #red;line.dashed : extends
+"main.New" --> "main.Writer" #green : aggregates
+@enduml
+`
+	result := RenderPlantUML(before, after, Compare(before, after))
+	if result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	changes := []*Change{
+		{Kind: Added, Type: "main.Foo", After: "class"},
+		{Kind: Changed, Type: "main.Bar", Member: "method Run", Before: "Run()", After: "Run(int)"},
+	}
+	expected := "+ main.Foo (class)\n~ main.Bar method Run: Run() -> Run(int)\n"
+	if result := RenderText(changes); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestNewBaseline(t *testing.T) {
+	p, err := parser.NewClassDiagram([]string{"../../testingsupport/embeds"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	baseline := NewBaseline(p)
+	expected := &API{
+		Kind:    "class",
+		Members: map[string]string{"field Name": "Name string", "method Style": "Style() string"},
+	}
+	if !reflect.DeepEqual(baseline.API["embeds.Server"], expected) {
+		t.Errorf("expected %v, got %v", expected, baseline.API["embeds.Server"])
+	}
+	buffer := &bytes.Buffer{}
+	if err := baseline.WriteJSON(buffer); err != nil {
+		t.Fatalf("expected no error writing the baseline but got %s", err.Error())
+	}
+	read, err := ReadBaseline(buffer)
+	if err != nil {
+		t.Fatalf("expected no error reading the baseline but got %s", err.Error())
+	}
+	if changes := CheckBaseline(read, baseline); len(changes) != 0 {
+		t.Errorf("expected no changes against the baseline itself, got %s", RenderText(changes))
+	}
+}
+
+func TestCheckBaseline(t *testing.T) {
+	approved := &Baseline{
+		Dependencies: map[string][]string{"main": {"parser"}},
+		API: map[string]*API{
+			"main.Foo": {Kind: "class", Members: map[string]string{"method Run": "Run()", "field Age": "Age int"}},
+		},
+	}
+	current := &Baseline{
+		Dependencies: map[string][]string{"main": {"diff", "parser"}, "parser": {}},
+		API: map[string]*API{
+			"main.Foo": {Kind: "class", Members: map[string]string{"method Run": "Run(int)", "method Stop": "Stop()"}},
+			"main.Bar": {Kind: "interface", Members: map[string]string{}},
+		},
+	}
+	expected := "+ main depends on diff\n+ main.Bar (interface)\n~ main.Foo method Run: Run() -> Run(int)\n+ main.Foo method Stop: Stop()\n"
+	if result := RenderText(CheckBaseline(approved, current)); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
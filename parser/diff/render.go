@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfeliu007/goplantuml/parser"
+)
+
+var kindColors = map[Kind]string{
+	Added:   "green",
+	Removed: "red",
+	Changed: "orange",
+}
+
+var typeColors = map[Kind]string{
+	Added:   "#palegreen",
+	Removed: "#pink",
+	Changed: "#khaki",
+}
+
+// RenderText returns the changes, one per line
+func RenderText(changes []*Change) string {
+	str := &parser.LineStringBuilder{}
+	for _, change := range changes {
+		str.WriteLineWithDepth(0, change.String())
+	}
+	return str.String()
+}
+
+// RenderPlantUML returns a diagram of the types with changes. Added things are green, removed ones red and changed
+// ones orange. Only the members that changed are rendered, except for added and removed types.
+func RenderPlantUML(before Model, after Model, changes []*Change) string {
+	members := map[string][]*Change{}
+	relationships := []*Change{}
+	typeChanges := map[string]*Change{}
+	names := []string{}
+	for _, change := range changes {
+		if _, ok := members[change.Type]; !ok {
+			members[change.Type] = []*Change{}
+			names = append(names, change.Type)
+		}
+		switch {
+		case change.Member == "":
+			typeChanges[change.Type] = change
+		case change.Before == "" && change.After == "":
+			relationships = append(relationships, change)
+		default:
+			members[change.Type] = append(members[change.Type], change)
+		}
+	}
+	str := &parser.LineStringBuilder{}
+	str.WriteLineWithDepth(0, "@startuml")
+	str.WriteLineWithDepth(0, "set separator none")
+	for _, name := range names {
+		t, ok := after[name]
+		if !ok {
+			t = before[name]
+		}
+		typeChange := typeChanges[name]
+		declaration := fmt.Sprintf(`%s "%s"`, getDeclarationKeyword(t.Kind), name)
+		switch {
+		case typeChange != nil && typeChange.Kind != Changed:
+			declaration = fmt.Sprintf(`%s <<%s>> %s`, declaration, typeChange.Kind, typeColors[typeChange.Kind])
+			members[name] = getTypeMembers(t, typeChange.Kind)
+		case typeChange != nil || len(members[name]) > 0:
+			declaration = fmt.Sprintf(`%s %s`, declaration, typeColors[Changed])
+		}
+		str.WriteLineWithDepth(0, fmt.Sprintf("%s {", declaration))
+		for _, member := range members[name] {
+			str.WriteLineWithDepth(1, renderMember(member))
+		}
+		str.WriteLineWithDepth(0, "}")
+	}
+	for _, relationship := range relationships {
+		split := strings.SplitN(relationship.Member, " ", 2)
+		label, target := split[0], split[1]
+		style := "#green"
+		if relationship.Kind == Removed {
+			style = "#red;line.dashed"
+		}
+		str.WriteLineWithDepth(0, fmt.Sprintf(`"%s" --> "%s" %s : %s`, relationship.Type, target, style, label))
+	}
+	str.WriteLineWithDepth(0, "@enduml")
+	return str.String()
+}
+
+func getDeclarationKeyword(kind string) string {
+	switch kind {
+	case "interface", "enum":
+		return kind
+	}
+	return "class"
+}
+
+// getTypeMembers returns every member of the added or removed type as a change of the same kind
+func getTypeMembers(t *Type, kind Kind) []*Change {
+	names := make([]string, 0, len(t.Members))
+	for name := range t.Members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	changes := make([]*Change, 0, len(names))
+	for _, name := range names {
+		change := &Change{Kind: kind, Member: name}
+		if kind == Added {
+			change.After = t.Members[name]
+		} else {
+			change.Before = t.Members[name]
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func renderMember(change *Change) string {
+	text := change.After
+	switch change.Kind {
+	case Removed:
+		text = change.Before
+	case Changed:
+		text = fmt.Sprintf("%s -> %s", change.Before, change.After)
+	}
+	return fmt.Sprintf("<color:%s>%s %s</color>", kindColors[change.Kind], kindSymbols[change.Kind], text)
+}
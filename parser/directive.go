@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// directivePrefix starts the comment directives of the doc comments of the types (e.g. //plantuml:ignore). Like the
+// other directives (e.g. //go:generate) they are not part of the doc comment.
+const directivePrefix = "//plantuml:"
+
+// addDirectives applies the directives of the doc comment of the given type: //plantuml:stereotype followed by
+// stereotypes adds them to the structure and //plantuml:ignore leaves the type out of the diagram. The unknown ones
+// are reported as diagnostics.
+func (p *ClassParser) addDirectives(st *Struct, fullName string, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		if !strings.HasPrefix(comment.Text, directivePrefix) {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(comment.Text, directivePrefix))
+		switch {
+		case len(fields) > 1 && fields[0] == "stereotype":
+			st.Stereotypes = append(st.Stereotypes, fields[1:]...)
+		case len(fields) == 1 && fields[0] == "ignore":
+			p.ignoredTypes[fullName] = struct{}{}
+		default:
+			p.addDiagnostic(fmt.Sprintf("%s:%d", p.parsingFileName, p.getLine(comment.Pos())), "invalid directive %s", comment.Text)
+		}
+	}
+}
+
+// removeIgnoredTypes removes the types with the //plantuml:ignore directive
+func (p *ClassParser) removeIgnoredTypes() {
+	if len(p.ignoredTypes) == 0 {
+		return
+	}
+	p.removeTypes(func(fullName string) bool {
+		_, ok := p.ignoredTypes[fullName]
+		return ok
+	})
+}
+
+// addStereotype adds the given stereotype to the one of a structure, after its spot and stereotypes if it has some
+// (e.g. << (S,Aquamarine) deprecated >>)
+func addStereotype(stereotype string, name string) string {
+	stereotype = strings.TrimSpace(stereotype)
+	if stereotype == "" {
+		return fmt.Sprintf("<<%s>>", name)
+	}
+	return fmt.Sprintf("%s %s >>", strings.TrimSpace(strings.TrimSuffix(stereotype, ">>")), name)
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const directivesSource = `package orders
+
+// Order is placed by a customer
+//
+//plantuml:stereotype Aggregate Root
+type Order struct {
+	Lines  []*Line
+	Secret *secret
+}
+
+//plantuml:stereotype Entity
+type Line struct{}
+
+// Repository stores the orders
+//plantuml:stereotype Port
+type Repository interface {
+	Save(o *Order) error
+}
+
+func (s *secret) Reveal() string {
+	return ""
+}
+
+//plantuml:ignore
+type secret struct{}
+
+//plantuml:ignore
+type Lines []*Line
+
+//plantuml:unknown
+type Status int
+`
+
+func TestAddStereotype(t *testing.T) {
+	tt := []struct {
+		Stereotype string
+		Name       string
+		Expected   string
+	}{
+		{Stereotype: "", Name: "Entity", Expected: "<<Entity>>"},
+		{Stereotype: " << (S,Aquamarine) >>", Name: "Entity", Expected: "<< (S,Aquamarine) Entity >>"},
+		{Stereotype: "<<Aggregate>>", Name: "Root", Expected: "<<Aggregate Root >>"},
+	}
+	for _, tc := range tt {
+		if result := addStereotype(tc.Stereotype, tc.Name); result != tc.Expected {
+			t.Errorf("TestAddStereotype: expected %q, got %q", tc.Expected, result)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"orders/orders.go": directivesSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{
+		"class Order << (S,Aquamarine) Aggregate Root >> {",
+		"class Line << (S,Aquamarine) Entity >> {",
+		"interface Repository <<Port>> {",
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestDirectives: expected %q in %s", expected, rendered)
+		}
+	}
+	for _, unexpected := range []string{"class secret", "Reveal", "orders.Lines"} {
+		if strings.Contains(rendered, unexpected) {
+			t.Errorf("TestDirectives: expected no %q in %s", unexpected, rendered)
+		}
+	}
+	diagnostics := parser.Diagnostics()
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0], "invalid directive //plantuml:unknown") {
+		t.Errorf("TestDirectives: expected the unknown directive to be reported, got %v", diagnostics)
+	}
+}
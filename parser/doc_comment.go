@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// renderDocComments renders the doc comments of the given structures and of their methods as notes attached to them
+func (p *ClassParser) renderDocComments(pack string, names []string, structures map[string]*Struct, str *LineStringBuilder) {
+	if !p.renderingOptions.DocComments {
+		return
+	}
+	for _, name := range names {
+		structure := structures[name]
+		if structure.Type == "alias" {
+			continue
+		}
+		fullName := getStructFullName(structure, pack, name)
+		if structure.Doc != "" {
+			str.WriteLineWithDepth(0, fmt.Sprintf(`note top of %s : %s`, fullName, p.formatDocComment(structure.Doc)))
+		}
+		if !p.renderingOptions.Methods {
+			continue
+		}
+		for _, method := range structure.Functions {
+			if method.Doc == "" || (!p.renderingOptions.PrivateMembers && unicode.IsLower(rune(method.Name[0]))) {
+				continue
+			}
+			str.WriteLineWithDepth(0, fmt.Sprintf(`note right of %s::%s : %s`, fullName, method.Name, p.formatDocComment(method.Doc)))
+		}
+	}
+}
+
+// formatDocComment collapses the doc comment into a single line and truncates it to DocCommentsMaxLength runes
+func (p *ClassParser) formatDocComment(doc string) string {
+	result := []rune(strings.Join(strings.Fields(doc), " "))
+	maxLength := p.renderingOptions.DocCommentsMaxLength
+	if maxLength > 0 && len(result) > maxLength {
+		return strings.TrimRightFunc(string(result[:maxLength]), unicode.IsSpace) + "..."
+	}
+	return string(result)
+}
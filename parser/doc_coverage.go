@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DocCoverage is the documentation coverage of the exported types of a package and of their exported methods
+type DocCoverage struct {
+	PackageName string `json:"package"`
+	Documented  int    `json:"documented"`
+	Total       int    `json:"total"`
+	// Coverage is the percentage of the exported types and methods that have a doc comment
+	Coverage float64 `json:"coverage"`
+	// Undocumented are the sorted package qualified names of the types (e.g. parser.Struct) and methods (e.g.
+	// parser.Struct.AddField) without doc comment
+	Undocumented []string `json:"undocumented"`
+}
+
+// DocCoverage returns the documentation coverage of every parsed package with exported types, sorted by package.
+// The types declared in _test.go files are not counted.
+func (p *ClassParser) DocCoverage() []*DocCoverage {
+	result := []*DocCoverage{}
+	for _, pack := range p.Packages() {
+		coverage := &DocCoverage{PackageName: pack, Undocumented: []string{}}
+		for name, structure := range p.structure[pack] {
+			fullName := getStructFullName(structure, pack, name)
+			if structure.Test || !isExportedName(strings.TrimPrefix(fullName, pack+".")) {
+				continue
+			}
+			coverage.add(fullName, structure.Doc)
+			for _, method := range structure.Functions {
+				if isExportedName(method.Name) {
+					coverage.add(fullName+"."+method.Name, method.Doc)
+				}
+			}
+		}
+		if coverage.Total == 0 {
+			continue
+		}
+		coverage.Coverage = float64(coverage.Documented) * 100 / float64(coverage.Total)
+		sort.Strings(coverage.Undocumented)
+		result = append(result, coverage)
+	}
+	return result
+}
+
+func (c *DocCoverage) add(name string, doc string) {
+	c.Total++
+	if strings.TrimSpace(doc) == "" {
+		c.Undocumented = append(c.Undocumented, name)
+		return
+	}
+	c.Documented++
+}
+
+func isExportedName(name string) bool {
+	return name != "" && unicode.IsUpper([]rune(name)[0])
+}
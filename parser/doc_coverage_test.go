@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocCoverage(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/doccomments"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	expected := []*DocCoverage{
+		{
+			PackageName:  "doccomments",
+			Documented:   5,
+			Total:        6,
+			Coverage:     float64(5) * 100 / 6,
+			Undocumented: []string{"doccomments.Undocumented"},
+		},
+	}
+	if coverage := parser.DocCoverage(); !reflect.DeepEqual(coverage, expected) {
+		t.Errorf("TestDocCoverage: expected %+v, got %+v", *expected[0], coverage)
+	}
+}
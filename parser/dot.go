@@ -0,0 +1,250 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var fontTagRegexp = regexp.MustCompile(`</?font[^>]*>`)
+
+var dotRecordEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+)
+
+// RenderDot returns a graphviz DOT representation of the class diagram this parser has generated.
+// It uses the same parsed structure and rendering options as Render().
+func (p *ClassParser) RenderDot() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	str := &LineStringBuilder{}
+	str.WriteLineWithDepth(0, "digraph goplantuml {")
+	str.WriteLineWithDepth(1, "rankdir=BT;")
+	str.WriteLineWithDepth(1, "node [shape=record];")
+	if p.renderingOptions.Title != "" {
+		str.WriteLineWithDepth(1, fmt.Sprintf(`label="%s";`, escapeDotString(p.renderingOptions.Title)))
+		str.WriteLineWithDepth(1, "labelloc=t;")
+	}
+	if note := strings.TrimSpace(p.renderingOptions.Notes); note != "" {
+		str.WriteLineWithDepth(1, fmt.Sprintf(`"__legend__" [shape=note, label="%s"];`, escapeDotString(note)))
+	}
+	var packages []string
+	for pack := range p.structure {
+		packages = append(packages, pack)
+	}
+	sort.Strings(packages)
+	edges := &LineStringBuilder{}
+	for _, pack := range packages {
+		p.renderDotPackage(pack, p.structure[pack], str, edges)
+	}
+	p.renderDotExternals(str)
+	str.WriteString(edges.String())
+	if p.renderingOptions.Aliases {
+		p.renderDotAliases(str)
+	}
+	str.WriteLineWithDepth(0, "}")
+	return str.String()
+}
+
+func (p *ClassParser) renderDotPackage(pack string, structures map[string]*Struct, str *LineStringBuilder, edges *LineStringBuilder) {
+	functions := p.getPackageFunctions(pack)
+	if len(structures) == 0 && functions == nil {
+		return
+	}
+	str.WriteLineWithDepth(1, fmt.Sprintf(`subgraph "cluster_%s" {`, pack))
+	str.WriteLineWithDepth(2, fmt.Sprintf(`label="%s";`, pack))
+	names := []string{}
+	for name := range structures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		structure := p.getFlattenedInterface(structures[name])
+		id := getStructFullName(structure, pack, name)
+		str.WriteLineWithDepth(2, fmt.Sprintf(`"%s" [label="%s"];`, id, p.getDotNodeLabel(structure, name)))
+		p.renderDotEdges(structure, id, edges)
+		if p.renderingOptions.Conversions {
+			p.renderDotConversions(structure, id, edges)
+		}
+		p.renderDotDependencies(structure, id, edges)
+	}
+	if functions != nil {
+		str.WriteLineWithDepth(2, fmt.Sprintf(`"%s.%s" [label="%s"];`, pack, packageFunctionsName, p.getDotNodeLabel(functions, packageFunctionsName)))
+	}
+	str.WriteLineWithDepth(1, "}")
+}
+
+func (p *ClassParser) getDotNodeLabel(structure *Struct, name string) string {
+	header := escapeDotRecord(strings.TrimPrefix(name, structure.PackageName+"."))
+	switch structure.Type {
+	case "interface":
+		header = fmt.Sprintf(`%s\n«interface»`, header)
+	case "alias":
+		header = fmt.Sprintf(`%s\n«alias»`, header)
+	case "enum":
+		header = fmt.Sprintf(`%s\n«enum»`, header)
+	case packageFunctionsName:
+		header = fmt.Sprintf(`%s\n«%s»`, header, packageFunctionsName)
+	}
+	sections := []string{header}
+	if p.renderingOptions.Fields {
+		fields := []string{}
+		for _, field := range structure.Fields {
+			if accessModifier, ok := p.getAccessModifier(field.Name); ok {
+				renderedField := withAccessModifier(accessModifier, fmt.Sprintf(`%s %s`, field.Name, field.Type))
+				if p.renderingOptions.FieldTags && field.Tag != "" {
+					renderedField = fmt.Sprintf(`%s %s`, renderedField, field.Tag)
+				}
+				fields = append(fields, escapeDotRecord(renderedField))
+			}
+		}
+		for _, value := range structure.EnumValues {
+			if _, ok := p.getAccessModifier(value.Name); ok {
+				fields = append(fields, escapeDotRecord(value.String()))
+			}
+		}
+		sections = append(sections, joinDotLines(fields))
+	}
+	if p.renderingOptions.Methods {
+		methods := []string{}
+		for _, method := range structure.Functions {
+			if accessModifier, ok := p.getAccessModifier(method.Name); ok {
+				methods = append(methods, escapeDotRecord(withAccessModifier(accessModifier, p.getDotMethodSignature(method))))
+			}
+		}
+		sections = append(sections, joinDotLines(methods))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(sections, "|"))
+}
+
+func (p *ClassParser) getDotMethodSignature(method *Function) string {
+	parameterList := make([]string, 0)
+	for _, parameter := range method.Parameters {
+		parameterList = append(parameterList, fmt.Sprintf("%s %s", parameter.Name, parameter.Type))
+	}
+	returnValues := ""
+	renderedReturnValues := p.getRenderedReturnValues(method)
+	if len(renderedReturnValues) == 1 {
+		returnValues = renderedReturnValues[0]
+	} else if len(renderedReturnValues) > 1 {
+		returnValues = fmt.Sprintf("(%s)", strings.Join(renderedReturnValues, ", "))
+	}
+	return strings.TrimSpace(fmt.Sprintf(`%s(%s) %s`, method.Name, strings.Join(parameterList, ", "), returnValues))
+}
+
+func (p *ClassParser) renderDotEdges(structure *Struct, id string, edges *LineStringBuilder) {
+	if p.renderingOptions.Compositions {
+		label := p.getDotEdgeLabel(extends)
+		for _, c := range getSortedKeys(structure.Composition) {
+			if !strings.Contains(c, ".") {
+				c = fmt.Sprintf("%s.%s", p.getPackageName(c, structure), c)
+			}
+			if isBuiltinName(c) {
+				continue
+			}
+			edges.WriteLineWithDepth(1, fmt.Sprintf(`"%s" -> "%s" [arrowhead=diamond%s];`, id, c, label))
+		}
+	}
+	if p.renderingOptions.Implementations {
+		label := p.getDotEdgeLabel(implements)
+		for _, c := range getSortedKeys(structure.Extends) {
+			if !strings.Contains(c, ".") {
+				c = fmt.Sprintf("%s.%s", structure.PackageName, c)
+			}
+			edges.WriteLineWithDepth(1, fmt.Sprintf(`"%s" -> "%s" [arrowhead=empty%s];`, id, c, label))
+		}
+	}
+	if p.renderingOptions.Aggregations {
+		label := p.getDotEdgeLabel(aggregates)
+		aggregationMap := structure.Aggregations
+		if p.renderingOptions.AggregatePrivateMembers {
+			aggregationMap = mergeSets(structure.Aggregations, structure.PrivateAggregations)
+		}
+		for _, a := range getSortedKeys(aggregationMap) {
+			qualifier := ""
+			if qualifierLabel := p.getQualifierLabel(structure, a); qualifierLabel != "" {
+				qualifier = fmt.Sprintf(`, headlabel="%s"`, qualifierLabel)
+			}
+			if !strings.Contains(a, ".") {
+				a = fmt.Sprintf("%s.%s", p.getPackageName(a, structure), a)
+			}
+			if p.getPackageName(a, structure) != builtinPackageName && !p.isIgnoredAggregation(a) {
+				edges.WriteLineWithDepth(1, fmt.Sprintf(`"%s" -> "%s" [dir=both, arrowhead=none, arrowtail=odiamond%s%s];`, id, a, label, qualifier))
+			}
+		}
+	}
+}
+
+func (p *ClassParser) renderDotAliases(str *LineStringBuilder) {
+	label := p.getDotEdgeLabel(aliasOf)
+	orderedAliases := AliasSlice{}
+	for _, alias := range p.allAliases {
+		orderedAliases = append(orderedAliases, *alias)
+	}
+	sort.Sort(orderedAliases)
+	for _, alias := range orderedAliases {
+		if isBuiltinName(alias.Name) {
+			continue
+		}
+		style := "dashed"
+		if alias.Assign {
+			style = "dotted"
+		}
+		str.WriteLineWithDepth(1, fmt.Sprintf(`"%s" -> "%s" [style=%s%s];`, alias.AliasOf, escapeDotString(stripFontTags(alias.Name)), style, label))
+	}
+}
+
+// getDotEdgeLabel returns the label attribute for an edge when connection labels are enabled. Labels are given quoted.
+func (p *ClassParser) getDotEdgeLabel(label string) string {
+	if !p.renderingOptions.ConnectionLabels {
+		return ""
+	}
+	return fmt.Sprintf(", label=%s", label)
+}
+
+func joinDotLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, `\l`) + `\l`
+}
+
+func stripFontTags(s string) string {
+	return fontTagRegexp.ReplaceAllString(s, "")
+}
+
+func escapeDotRecord(s string) string {
+	return dotRecordEscaper.Replace(stripFontTags(s))
+}
+
+func escapeDotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func getSortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergeSets(sets ...map[string]struct{}) map[string]struct{} {
+	result := map[string]struct{}{}
+	for _, set := range sets {
+		for k := range set {
+			result[k] = struct{}{}
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestRenderDot(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/connectionlabels"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderDot: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderConnectionLabels: true,
+		RenderAggregations:     true,
+		RenderPrivateMembers:   true,
+		RenderTitle:            "Test Title",
+	})
+	result := parser.RenderDot()
+	expectedResult := `digraph goplantuml {
+    rankdir=BT;
+    node [shape=record];
+    label="Test Title";
+    labelloc=t;
+    subgraph "cluster_connectionlabels" {
+        label="connectionlabels";
+        "connectionlabels.AbstractInterface" [label="{AbstractInterface\n«interface»||- interfaceFunction() bool\l}"];
+        "connectionlabels.ImplementsAbstractInterface" [label="{ImplementsAbstractInterface|+ PublicUse AbstractInterface\l|- interfaceFunction() bool\l}"];
+        "connectionlabels.AliasOfInt" [label="{AliasOfInt\n«alias»||}"];
+    }
+    "connectionlabels.ImplementsAbstractInterface" -> "connectionlabels.AliasOfInt" [arrowhead=diamond, label="extends"];
+    "connectionlabels.ImplementsAbstractInterface" -> "connectionlabels.AbstractInterface" [arrowhead=empty, label="implements"];
+    "connectionlabels.ImplementsAbstractInterface" -> "connectionlabels.AbstractInterface" [dir=both, arrowhead=none, arrowtail=odiamond, label="uses"];
+}
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderDot: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
+func TestEscapeDotRecord(t *testing.T) {
+	result := escapeDotRecord("<font color=blue>map</font>[string]<font color=blue>struct</font>{}")
+	expected := `map[string]struct\{\}`
+	if result != expected {
+		t.Errorf("TestEscapeDotRecord: expected %s, got %s", expected, result)
+	}
+}
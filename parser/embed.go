@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const embedDirective = "//go:embed "
+
+// EmbeddedAssets holds a package level variable initialized with a //go:embed directive. Users are the names of the
+// structures whose methods reference the variable.
+type EmbeddedAssets struct {
+	Name        string
+	PackageName string
+	Patterns    []string
+	Users       []string
+	spec        *ast.ValueSpec
+}
+
+// addEmbeddedAssets registers the variables of the given spec if it has a //go:embed directive. The directive is
+// written in the doc comment of the spec, or of the declaration when it is not parenthesized.
+func (p *ClassParser) addEmbeddedAssets(spec *ast.ValueSpec, declDoc *ast.CommentGroup) {
+	doc := spec.Doc
+	if doc == nil {
+		doc = declDoc
+	}
+	if doc == nil {
+		return
+	}
+	patterns := []string{}
+	for _, comment := range doc.List {
+		if strings.HasPrefix(comment.Text, embedDirective) {
+			patterns = append(patterns, getEmbedPatterns(strings.TrimPrefix(comment.Text, embedDirective))...)
+		}
+	}
+	if len(patterns) == 0 {
+		return
+	}
+	for _, name := range spec.Names {
+		p.allEmbeds[p.currentPackageName] = append(p.allEmbeds[p.currentPackageName], &EmbeddedAssets{
+			Name:        name.Name,
+			PackageName: p.currentPackageName,
+			Patterns:    patterns,
+			spec:        spec,
+		})
+	}
+}
+
+// getEmbedPatterns splits the arguments of a //go:embed directive. Patterns can be quoted to contain spaces.
+func getEmbedPatterns(arguments string) []string {
+	patterns := []string{}
+	for arguments = strings.TrimSpace(arguments); arguments != ""; arguments = strings.TrimSpace(arguments) {
+		end := strings.IndexAny(arguments, " \t")
+		if quote := arguments[0]; quote == '"' || quote == '`' {
+			end = getClosingQuote(arguments, quote) + 1
+		}
+		if end <= 0 || end > len(arguments) {
+			end = len(arguments)
+		}
+		pattern := arguments[:end]
+		if unquoted, err := strconv.Unquote(pattern); err == nil {
+			pattern = unquoted
+		}
+		patterns = append(patterns, pattern)
+		arguments = arguments[end:]
+	}
+	return patterns
+}
+
+// getClosingQuote returns the index of the quote closing the one the given string starts with or -1
+func getClosingQuote(quoted string, quote byte) int {
+	for i := 1; i < len(quoted); i++ {
+		switch {
+		case quoted[i] == '\\' && quote == '"':
+			i++
+		case quoted[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// addEmbeddedAssetsUsers finds the methods of the given files that reference the embedded assets of the current package
+func (p *ClassParser) addEmbeddedAssetsUsers(files []*ast.File) {
+	embeds := map[string]*EmbeddedAssets{}
+	for _, embed := range p.allEmbeds[p.currentPackageName] {
+		embeds[embed.Name] = embed
+	}
+	if len(embeds) == 0 {
+		return
+	}
+	for _, f := range files {
+		for _, d := range f.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Recv == nil || len(decl.Recv.List) == 0 || decl.Body == nil {
+				continue
+			}
+			theType, _ := getFieldType(decl.Recv.List[0].Type, p.allImports)
+			user := fmt.Sprintf("%s.%s", p.currentPackageName, strings.TrimPrefix(replacePackageConstant(theType, ""), "*"))
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				switch v := n.(type) {
+				case *ast.SelectorExpr:
+					// Only the left side can reference a package level variable
+					ast.Inspect(v.X, func(n ast.Node) bool {
+						addEmbeddedAssetsUser(embeds, n, user)
+						return true
+					})
+					return false
+				default:
+					addEmbeddedAssetsUser(embeds, n, user)
+				}
+				return true
+			})
+		}
+	}
+}
+
+func addEmbeddedAssetsUser(embeds map[string]*EmbeddedAssets, n ast.Node, user string) {
+	ident, ok := n.(*ast.Ident)
+	if !ok {
+		return
+	}
+	embed, ok := embeds[ident.Name]
+	// Identifiers declared in other files are not resolved by the parser, local ones are
+	if !ok || (ident.Obj != nil && ident.Obj.Decl != embed.spec) {
+		return
+	}
+	for _, existing := range embed.Users {
+		if existing == user {
+			return
+		}
+	}
+	embed.Users = append(embed.Users, user)
+}
+
+// renderEmbeddedAssets renders the embedded assets of the package as artifacts linked to the structures using them
+func (p *ClassParser) renderEmbeddedAssets(pack string, str *LineStringBuilder, edges *LineStringBuilder) {
+	if !p.renderingOptions.EmbeddedAssets {
+		return
+	}
+	embeds := append([]*EmbeddedAssets{}, p.allEmbeds[pack]...)
+	sort.SliceStable(embeds, func(i, j int) bool {
+		return embeds[i].Name < embeds[j].Name
+	})
+	for _, embed := range embeds {
+		str.WriteLineWithDepth(1, fmt.Sprintf(`artifact "%s: %s" as %s <<embed>>`, embed.Name, strings.Join(embed.Patterns, ", "), embed.Name))
+		users := append([]string{}, embed.Users...)
+		sort.Strings(users)
+		for _, user := range users {
+			if p.getStruct(user) != nil {
+				edges.WriteLineWithDepth(0, fmt.Sprintf(`"%s" ..> "%s.%s" : embeds`, user, pack, embed.Name))
+			}
+		}
+	}
+}
+
+// hasEmbeddedAssets returns true if the diagram renders embedded assets, which requires allowmixing
+func (p *ClassParser) hasEmbeddedAssets() bool {
+	if !p.renderingOptions.EmbeddedAssets {
+		return false
+	}
+	for _, embeds := range p.allEmbeds {
+		if len(embeds) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmbeddedAssets(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/embeds"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestEmbeddedAssets: expected no error but got %s", err.Error())
+	}
+	embeds := map[string]*EmbeddedAssets{}
+	for _, embed := range parser.allEmbeds["embeds"] {
+		embeds[embed.Name] = embed
+	}
+	if len(embeds) != 2 {
+		t.Fatalf("TestEmbeddedAssets: expected 2 embedded assets, got %d", len(embeds))
+	}
+	tt := []struct {
+		Name     string
+		Patterns []string
+		Users    []string
+	}{
+		{
+			Name:     "static",
+			Patterns: []string{"static/*.css"},
+			Users:    []string{"embeds.Handler"},
+		},
+		{
+			Name:     "style",
+			Patterns: []string{"static/style.css"},
+			Users:    []string{"embeds.Server"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			embed, ok := embeds[tc.Name]
+			if !ok {
+				t.Fatalf("expected %s to be an embedded asset", tc.Name)
+			}
+			if !reflect.DeepEqual(embed.Patterns, tc.Patterns) {
+				t.Errorf("expected patterns %v, got %v", tc.Patterns, embed.Patterns)
+			}
+			if !reflect.DeepEqual(embed.Users, tc.Users) {
+				t.Errorf("expected users %v, got %v", tc.Users, embed.Users)
+			}
+		})
+	}
+}
+
+func TestGetEmbedPatterns(t *testing.T) {
+	result := getEmbedPatterns(`images/*.png "with space.txt" ` + "`raw.txt`")
+	expected := []string{"images/*.png", "with space.txt", "raw.txt"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("TestGetEmbedPatterns: expected %v, got %v", expected, result)
+	}
+}
+
+func TestRenderEmbeddedAssets(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/embeds"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestRenderEmbeddedAssets: expected no error but got %s", err.Error())
+	}
+	str := &LineStringBuilder{}
+	edges := &LineStringBuilder{}
+	parser.renderEmbeddedAssets("embeds", str, edges)
+	if str.Len() != 0 || edges.Len() != 0 || parser.hasEmbeddedAssets() {
+		t.Errorf("TestRenderEmbeddedAssets: expected nothing to be rendered by default")
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderEmbeddedAssets: true})
+	parser.renderEmbeddedAssets("embeds", str, edges)
+	expected := `    artifact "static: static/*.css" as static <<embed>>
+    artifact "style: static/style.css" as style <<embed>>
+`
+	if str.String() != expected {
+		t.Errorf("TestRenderEmbeddedAssets: expected %s, got %s", expected, str.String())
+	}
+	expectedEdges := `"embeds.Handler" ..> "embeds.static" : embeds
+"embeds.Server" ..> "embeds.style" : embeds
+`
+	if edges.String() != expectedEdges {
+		t.Errorf("TestRenderEmbeddedAssets: expected %s, got %s", expectedEdges, edges.String())
+	}
+	if !parser.hasEmbeddedAssets() {
+		t.Errorf("TestRenderEmbeddedAssets: expected the diagram to have embedded assets")
+	}
+}
@@ -0,0 +1,45 @@
+package parser
+
+// getFlattenedInterface returns a copy of the given interface with the methods of its embedded interfaces inlined
+// and without the composition relationships to them. Structures that do not embed parsed interfaces are returned
+// as they are.
+func (p *ClassParser) getFlattenedInterface(structure *Struct) *Struct {
+	if !p.renderingOptions.FlattenInterfaces || structure.Type != "interface" || len(structure.EmbeddedInterfaces) == 0 {
+		return structure
+	}
+	flattened := *structure
+	flattened.Functions = p.getInterfaceMethodSet(structure, map[*Struct]struct{}{}, map[string]struct{}{})
+	flattened.Composition = make(map[string]struct{}, len(structure.Composition))
+	for c := range structure.Composition {
+		flattened.Composition[c] = struct{}{}
+	}
+	for _, embedded := range structure.EmbeddedInterfaces {
+		if p.getStruct(embedded) != nil {
+			delete(flattened.Composition, embedded)
+		}
+	}
+	return &flattened
+}
+
+// getInterfaceMethodSet returns the methods of the interface followed by the ones of the parsed interfaces it
+// embeds, recursively. Methods promoted through more than one path are only returned once.
+func (p *ClassParser) getInterfaceMethodSet(structure *Struct, visited map[*Struct]struct{}, names map[string]struct{}) []*Function {
+	visited[structure] = struct{}{}
+	functions := make([]*Function, 0, len(structure.Functions))
+	for _, function := range structure.Functions {
+		if _, ok := names[function.Name]; !ok {
+			names[function.Name] = struct{}{}
+			functions = append(functions, function)
+		}
+	}
+	for _, embedded := range structure.EmbeddedInterfaces {
+		st := p.getStruct(embedded)
+		if st == nil {
+			continue
+		}
+		if _, ok := visited[st]; !ok {
+			functions = append(functions, p.getInterfaceMethodSet(st, visited, names)...)
+		}
+	}
+	return functions
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestRenderFlattenInterfaces(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/embeddedinterfaces"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderFlattenInterfaces: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		FlattenInterfaces: true,
+	})
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace embeddedinterfaces {
+    interface Closer  {
+        + Close() error
+
+    }
+    interface ReadCloser  {
+        + Reset()
+        + Read() string
+        + Close() error
+
+    }
+    interface ReadResetCloser  {
+        + Reset()
+        + Read() string
+        + Close() error
+
+    }
+    interface Reader  {
+        + Read() string
+
+    }
+}
+
+
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderFlattenInterfaces: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
+func TestGetFlattenedInterfaceDisabled(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/embeddedinterfaces"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestGetFlattenedInterfaceDisabled: expected no error but got %s", err.Error())
+		return
+	}
+	st := parser.getStruct("embeddedinterfaces.ReadCloser")
+	if flattened := parser.getFlattenedInterface(st); flattened != st {
+		t.Errorf("TestGetFlattenedInterfaceDisabled: expected the interface to be returned as it is, got %v", flattened)
+	}
+	if len(st.EmbeddedInterfaces) != 2 || len(st.Composition) != 2 {
+		t.Errorf("TestGetFlattenedInterfaceDisabled: expected two embedded interfaces, got %v", st.EmbeddedInterfaces)
+	}
+}
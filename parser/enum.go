@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"unicode"
+)
+
+// EnumValue is one of the constants declared with the type of an enum. Value is only known when the package type
+// checks.
+type EnumValue struct {
+	Name  string
+	Value string
+}
+
+// String returns the name of the constant followed by its value when it is known
+func (v *EnumValue) String() string {
+	if v.Value == "" {
+		return v.Name
+	}
+	return fmt.Sprintf("%s = %s", v.Name, v.Value)
+}
+
+// addEnumValues registers the constants of the given const spec in the structure of their type. inherited is the
+// type of the previous spec in the same const block, which is implicitly repeated when the spec has no values
+// (e.g. iota enumerations). The type of this spec is returned so it can be passed to the next one.
+func (p *ClassParser) addEnumValues(spec *ast.ValueSpec, inherited ast.Expr) ast.Expr {
+	constType := spec.Type
+	if constType == nil && len(spec.Values) > 0 {
+		constType = getConversionType(spec.Values[0])
+	}
+	if constType == nil && len(spec.Values) == 0 {
+		constType = inherited
+	}
+	ident, ok := constType.(*ast.Ident)
+	if !ok || isPrimitiveString(ident.Name) {
+		return constType
+	}
+	st := p.getOrCreateStruct(fmt.Sprintf("%s.%s", p.currentPackageName, ident.Name))
+	for _, name := range spec.Names {
+		if name.Name != "_" {
+			st.EnumValues = append(st.EnumValues, &EnumValue{Name: name.Name})
+		}
+	}
+	return constType
+}
+
+// getConversionType returns the type of conversions like Color(iota). Other expressions return nil.
+func getConversionType(value ast.Expr) ast.Expr {
+	call, ok := value.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil
+	}
+	return call.Fun
+}
+
+// resolveEnums turns the aliases that have constants declared with their type into enums. Structures that only
+// exist because of constants whose type was not parsed (e.g. declared in a test file) are removed.
+func (p *ClassParser) resolveEnums() {
+	for _, structures := range p.structure {
+		for name, st := range structures {
+			if len(st.EnumValues) == 0 {
+				continue
+			}
+			switch st.Type {
+			case "alias":
+				st.Type = "enum"
+			case "":
+				delete(structures, name)
+			}
+		}
+	}
+}
+
+func (p *ClassParser) renderEnumValues(structure *Struct, privateValues *LineStringBuilder, publicValues *LineStringBuilder) {
+	for _, value := range structure.EnumValues {
+		renderedValue := value.String()
+		if unicode.IsLower(rune(value.Name[0])) {
+			if p.renderingOptions.PrivateMembers {
+				privateValues.WriteLineWithDepth(2, renderedValue)
+			}
+		} else {
+			publicValues.WriteLineWithDepth(2, renderedValue)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestRenderEnums(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/enums"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderEnums: expected no error but got %s", err.Error())
+		return
+	}
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace enums {
+    class Painter << (S,Aquamarine) >> {
+        + Color Color
+        + Shape Shape
+        + Size Size
+
+    }
+    enum enums.Color  {
+        Red = 0
+        Green = 1
+        Yellow = 10
+
+    }
+    enum enums.Shape  {
+        Circle = "circle"
+        Square = "square"
+
+    }
+    class enums.Size << (T, #FF7700) >>  {
+    }
+}
+
+
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderEnums: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
+func TestAddEnumValues(t *testing.T) {
+	parser := getEmptyParser("main")
+	colorType := parser.addEnumValues(&ast.ValueSpec{
+		Names:  []*ast.Ident{{Name: "Red"}},
+		Values: []ast.Expr{&ast.CallExpr{Fun: &ast.Ident{Name: "Color"}, Args: []ast.Expr{&ast.Ident{Name: "iota"}}}},
+	}, nil)
+	parser.addEnumValues(&ast.ValueSpec{
+		Names: []*ast.Ident{{Name: "Green"}, {Name: "_"}},
+	}, colorType)
+	parser.addEnumValues(&ast.ValueSpec{
+		Names:  []*ast.Ident{{Name: "Count"}},
+		Type:   &ast.Ident{Name: "int"},
+		Values: []ast.Expr{&ast.BasicLit{Value: "1"}},
+	}, colorType)
+	st := parser.getStruct("main.main.Color")
+	if st == nil {
+		t.Fatal("TestAddEnumValues: expected the main.Color structure to be created")
+	}
+	if len(st.EnumValues) != 2 || st.EnumValues[0].Name != "Red" || st.EnumValues[1].Name != "Green" {
+		t.Errorf("TestAddEnumValues: expected [Red Green], got %v", st.EnumValues)
+	}
+	if len(parser.structure["main"]) != 1 {
+		t.Errorf("TestAddEnumValues: expected only the main.Color structure, got %v", parser.structure["main"])
+	}
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+const externalNamespace = "external"
+
+// addExternalTypes registers stub structures for the types of imported packages that are referenced by the
+// parsed structures or that are implemented by them, so relationships to io.Reader, fmt.Stringer, etc. are visible.
+func (p *ClassParser) addExternalTypes() {
+	for _, pack := range p.structure {
+		for _, st := range pack {
+			for _, target := range getSortedKeys(mergeSets(st.Composition, st.Extends, st.Aggregations)) {
+				p.addExternalType(target)
+			}
+		}
+	}
+	var importedNames []string
+	for name := range p.importedPackages {
+		importedNames = append(importedNames, name)
+	}
+	sort.Strings(importedNames)
+	for _, name := range importedNames {
+		if _, ok := p.structure[name]; !ok {
+			p.addExternalImplementations(p.importedPackages[name])
+		}
+	}
+}
+
+// addExternalImplementations adds an extends relationship from every typed struct to the interfaces of the given
+// imported package that it implements
+func (p *ClassParser) addExternalImplementations(imported *types.Package) {
+	scope := imported.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !typeName.Exported() {
+			continue
+		}
+		iface, ok := typeName.Type().Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 {
+			continue
+		}
+		fullName := fmt.Sprintf("%s.%s", imported.Name(), name)
+		for s := range p.allStructs {
+			st := p.getStruct(s)
+			if st == nil || st.namedType == nil {
+				continue
+			}
+			if types.Implements(st.namedType, iface) || types.Implements(types.NewPointer(st.namedType), iface) {
+				st.AddToExtends(fullName)
+				p.addExternalType(fullName)
+			}
+		}
+	}
+}
+
+// addExternalType creates the stub for the given fully qualified type name if it belongs to a package that was
+// not parsed
+func (p *ClassParser) addExternalType(fullName string) {
+	split := strings.SplitN(fullName, ".", 2)
+	if len(split) != 2 || split[0] == builtinPackageName {
+		return
+	}
+	if _, ok := p.structure[split[0]]; ok {
+		return
+	}
+	if _, ok := p.allExternals[fullName]; ok {
+		return
+	}
+	external := &Struct{
+		PackageName:         split[0],
+		Functions:           make([]*Function, 0),
+		Fields:              make([]*Field, 0),
+		Type:                "class",
+		Composition:         make(map[string]struct{}),
+		Extends:             make(map[string]struct{}),
+		Aggregations:        make(map[string]struct{}),
+		PrivateAggregations: make(map[string]struct{}),
+		Conversions:         make(map[string]struct{}),
+	}
+	if imported, ok := p.importedPackages[split[0]]; ok {
+		if typeName, ok := imported.Scope().Lookup(split[1]).(*types.TypeName); ok {
+			external.namedType = typeName.Type()
+			if iface, ok := typeName.Type().Underlying().(*types.Interface); ok {
+				external.Type = "interface"
+				external.Functions = getInterfaceFunctions(iface, imported)
+			}
+		}
+	}
+	p.allExternals[fullName] = external
+}
+
+// getInterfaceFunctions returns the exported methods of the given interface in the same format the parser uses
+// for the parsed ones
+func getInterfaceFunctions(iface *types.Interface, pack *types.Package) []*Function {
+	qualifier := func(other *types.Package) string {
+		if other == pack {
+			return ""
+		}
+		return other.Name()
+	}
+	functions := make([]*Function, 0)
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if !method.Exported() {
+			continue
+		}
+		signature := method.Type().(*types.Signature)
+		function := &Function{
+			Name:         method.Name(),
+			Parameters:   make([]*Field, 0),
+			ReturnValues: make([]string, 0),
+			PackageName:  pack.Name(),
+		}
+		for j := 0; j < signature.Params().Len(); j++ {
+			param := signature.Params().At(j)
+			paramType := types.TypeString(param.Type(), qualifier)
+			if signature.Variadic() && j == signature.Params().Len()-1 {
+				paramType = "..." + strings.TrimPrefix(paramType, "[]")
+			}
+			function.Parameters = append(function.Parameters, &Field{Name: param.Name(), Type: paramType})
+		}
+		for j := 0; j < signature.Results().Len(); j++ {
+			function.ReturnValues = append(function.ReturnValues, types.TypeString(signature.Results().At(j).Type(), qualifier))
+		}
+		functions = append(functions, function)
+	}
+	return functions
+}
+
+// getExternalName returns the name under which the given type is rendered. External types are rendered inside
+// the external namespace.
+func (p *ClassParser) getExternalName(name string) string {
+	if _, ok := p.allExternals[name]; ok {
+		return fmt.Sprintf("%s.%s", externalNamespace, name)
+	}
+	return name
+}
+
+func (p *ClassParser) getSortedExternals() []string {
+	names := []string{}
+	for name := range p.allExternals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *ClassParser) renderExternals(str *LineStringBuilder) {
+	currentPackage := ""
+	for _, fullName := range p.getSortedExternals() {
+		external := p.allExternals[fullName]
+		if external.PackageName != currentPackage {
+			if currentPackage != "" {
+				str.WriteLineWithDepth(0, "}")
+			}
+			currentPackage = external.PackageName
+			str.WriteLineWithDepth(0, p.getNamespaceStart(fmt.Sprintf("%s.%s", externalNamespace, currentPackage)))
+		}
+		str.WriteLineWithDepth(1, fmt.Sprintf(`%s %s <<%s>> {`, external.Type, strings.TrimPrefix(fullName, currentPackage+"."), externalNamespace))
+		privateMethods := &LineStringBuilder{}
+		publicMethods := &LineStringBuilder{}
+		p.renderStructMethods(external, privateMethods, publicMethods)
+		if publicMethods.Len() > 0 {
+			str.WriteLineWithDepth(0, publicMethods.String())
+		}
+		str.WriteLineWithDepth(1, "}")
+	}
+	if currentPackage != "" {
+		str.WriteLineWithDepth(0, "}")
+	}
+}
+
+func (p *ClassParser) renderDotExternals(str *LineStringBuilder) {
+	if len(p.allExternals) == 0 {
+		return
+	}
+	str.WriteLineWithDepth(1, fmt.Sprintf(`subgraph "cluster_%s" {`, externalNamespace))
+	str.WriteLineWithDepth(2, fmt.Sprintf(`label="%s";`, externalNamespace))
+	for _, fullName := range p.getSortedExternals() {
+		external := p.allExternals[fullName]
+		str.WriteLineWithDepth(2, fmt.Sprintf(`"%s" [label="%s"];`, fullName, p.getDotNodeLabel(external, strings.TrimPrefix(fullName, external.PackageName+"."))))
+	}
+	str.WriteLineWithDepth(1, "}")
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIncludeExternal(t *testing.T) {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:  afero.NewOsFs(),
+		Directories: []string{"../testingsupport/externaltypes"},
+		RenderingOptions: map[RenderingOption]interface{}{
+			RenderAggregations: true,
+		},
+		IncludeExternal: true,
+	})
+	if err != nil {
+		t.Errorf("TestIncludeExternal: expected no error but got %s", err.Error())
+		return
+	}
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace externaltypes {
+    class Source << (S,Aquamarine) >> {
+        + Timeout time.Duration
+        + Next io.Reader
+
+        + Read(p []byte) (int, error)
+
+    }
+}
+
+"external.io.Reader" <|-- "externaltypes.Source"
+
+"externaltypes.Source" o-- "external.io.Reader"
+"externaltypes.Source" o-- "external.time.Duration"
+
+namespace external.io {
+    interface Reader <<external>> {
+        + Read(p []byte) (int, error)
+
+    }
+}
+namespace external.time {
+    class Duration <<external>> {
+    }
+}
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestIncludeExternal: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
+
+func TestExcludeExternalByDefault(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/externaltypes"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestExcludeExternalByDefault: expected no error but got %s", err.Error())
+		return
+	}
+	if len(parser.allExternals) != 0 {
+		t.Errorf("TestExcludeExternalByDefault: expected no external types, got %v", parser.allExternals)
+	}
+	if _, ok := parser.getStruct("externaltypes.Source").Extends["io.Reader"]; ok {
+		t.Errorf("TestExcludeExternalByDefault: expected externaltypes.Source to not extend io.Reader")
+	}
+}
@@ -14,6 +14,9 @@ type Field struct {
 	Name     string
 	Type     string
 	FullType string
+	Tag      string
+	// Line is the line of the declaration of the field in the file of its structure
+	Line int
 }
 
 // Returns a string representation of the given expression if it was recognized.
@@ -40,8 +43,10 @@ func getFieldType(exp ast.Expr, aliases map[string]string) (string, []string) {
 		return getFuncType(v, aliases)
 	case *ast.Ellipsis:
 		return getEllipsis(v, aliases)
+	case *ast.IndexExpr:
+		return getGenericType(v.X, []ast.Expr{v.Index}, aliases)
 	}
-	return "", []string{}
+	return getIndexListExpr(exp, aliases)
 }
 
 func getIdent(v *ast.Ident, aliases map[string]string) (string, []string) {
@@ -131,6 +136,19 @@ func getFuncType(v *ast.FuncType, aliases map[string]string) (string, []string)
 	return fmt.Sprintf("<font color=blue>func</font>(%s) %s", strings.Join(params, ", "), returns), []string{}
 }
 
+// getGenericType returns the instantiation of a generic type rendered as Repo~User~. The fundamental types are the
+// generic type followed by the fundamental types of the type arguments, so both get a relationship.
+func getGenericType(genericType ast.Expr, typeArguments []ast.Expr, aliases map[string]string) (string, []string) {
+	t, fundamentalTypes := getFieldType(genericType, aliases)
+	renderedArguments := make([]string, 0, len(typeArguments))
+	for _, typeArgument := range typeArguments {
+		argument, f := getFieldType(typeArgument, aliases)
+		renderedArguments = append(renderedArguments, argument)
+		fundamentalTypes = append(fundamentalTypes, f...)
+	}
+	return fmt.Sprintf("%s~%s~", t, strings.Join(renderedArguments, ", ")), fundamentalTypes
+}
+
 func getEllipsis(v *ast.Ellipsis, aliases map[string]string) (string, []string) {
 	t, _ := getFieldType(v.Elt, aliases)
 	return fmt.Sprintf("...%s", t), []string{}
@@ -188,9 +206,11 @@ func isPrimitiveString(t string) bool {
 	return ok
 }
 
+// replacePackageConstant qualifies every local type of the given field type with the given package name, or leaves
+// them unqualified if it is empty
 func replacePackageConstant(field, packageName string) string {
 	if packageName != "" {
 		packageName = fmt.Sprintf("%s.", packageName)
 	}
-	return strings.Replace(field, packageConstant, packageName, 1)
+	return strings.ReplaceAll(field, packageConstant, packageName)
 }
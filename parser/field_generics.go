@@ -0,0 +1,33 @@
+//go:build go1.18
+// +build go1.18
+
+package parser
+
+import "go/ast"
+
+// getIndexListExpr handles the instantiations of generic types with more than one type argument (e.g. Pair[K, V]).
+// Any other expression is not supported and returns an empty type.
+func getIndexListExpr(exp ast.Expr, aliases map[string]string) (string, []string) {
+	if v, ok := exp.(*ast.IndexListExpr); ok {
+		return getGenericType(v.X, v.Indices, aliases)
+	}
+	return "", []string{}
+}
+
+// getTypeSpecParameters returns the names of the type parameters of the given type declaration (e.g. K and V for
+// type Pair[K comparable, V any] struct)
+func getTypeSpecParameters(spec *ast.TypeSpec) []string {
+	return getFieldListNames(spec.TypeParams)
+}
+
+// getReceiverBaseType returns the generic type of a receiver instantiated with its type parameters (e.g. Repo for
+// Repo[T] or Pair for Pair[K, V]), and the names given to these parameters. Any other receiver is returned as is.
+func getReceiverBaseType(receiver ast.Expr) (ast.Expr, []string) {
+	switch v := receiver.(type) {
+	case *ast.IndexExpr:
+		return v.X, getIdentNames([]ast.Expr{v.Index})
+	case *ast.IndexListExpr:
+		return v.X, getIdentNames(v.Indices)
+	}
+	return receiver, nil
+}
@@ -0,0 +1,22 @@
+//go:build !go1.18
+// +build !go1.18
+
+package parser
+
+import "go/ast"
+
+// getIndexListExpr returns an empty type since generic types with more than one type argument can not be parsed
+// before go 1.18
+func getIndexListExpr(exp ast.Expr, aliases map[string]string) (string, []string) {
+	return "", []string{}
+}
+
+// getTypeSpecParameters returns no type parameters since they can not be parsed before go 1.18
+func getTypeSpecParameters(spec *ast.TypeSpec) []string {
+	return nil
+}
+
+// getReceiverBaseType returns the receiver as is since generic receivers can not be parsed before go 1.18
+func getReceiverBaseType(receiver ast.Expr) (ast.Expr, []string) {
+	return receiver, nil
+}
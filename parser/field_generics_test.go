@@ -0,0 +1,101 @@
+//go:build go1.18
+// +build go1.18
+
+package parser
+
+import (
+	"go/ast"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGetFieldTypeIndexListExpr(t *testing.T) {
+	result, fundamentalTypes := getFieldType(&ast.IndexListExpr{
+		X: &ast.Ident{
+			Name: "Pair",
+		},
+		Indices: []ast.Expr{
+			&ast.Ident{
+				Name: "string",
+			},
+			&ast.SelectorExpr{
+				X: &ast.Ident{
+					Name: "puml",
+				},
+				Sel: &ast.Ident{
+					Name: "User",
+				},
+			},
+		},
+	}, map[string]string{"puml": "goplantuml"})
+	expectedResult := packageConstant + "Pair~string, goplantuml.User~"
+	if result != expectedResult {
+		t.Errorf("TestGetFieldTypeIndexListExpr: Expected result to be %s, got %s", expectedResult, result)
+	}
+	expectedFundamentalTypes := []string{packageConstant + "Pair", "goplantuml.User"}
+	if !reflect.DeepEqual(fundamentalTypes, expectedFundamentalTypes) {
+		t.Errorf("TestGetFieldTypeIndexListExpr: Expected fundamental types to be %v, got %v", expectedFundamentalTypes, fundamentalTypes)
+	}
+}
+
+const genericReceiversSource = `package gen
+
+type Repo[T any] struct {
+	Items []T
+	byID  map[string]T
+}
+
+func (r *Repo[T]) Add(t T) {}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func (p Pair[K, V]) Get() V { return p.Value }
+
+func (Pair[_, _]) Reset() {}
+
+type M[K comparable, V any] map[K]V
+
+type Box[T any] struct {
+	Pair[string, T]
+}
+`
+
+func TestGenericReceivers(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"gen/gen.go": genericReceiversSource}, &ClassDiagramOptions{
+		RenderingOptions: map[RenderingOption]interface{}{RenderAggregations: true, AggregatePrivateMembers: true},
+	})
+	if err != nil {
+		t.Fatalf("TestGenericReceivers: expected no error but got %s", err.Error())
+	}
+	names := []string{}
+	for name := range parser.structure["gen"] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if expected := []string{"Box", "Pair", "Repo", "gen.M"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("TestGenericReceivers: expected the types %v, got %v", expected, names)
+	}
+	methods := map[string][]string{}
+	for _, name := range []string{"Repo", "Pair"} {
+		for _, function := range parser.structure["gen"][name].Functions {
+			methods[name] = append(methods[name], function.Name)
+		}
+	}
+	if expected := map[string][]string{"Repo": {"Add"}, "Pair": {"Get", "Reset"}}; !reflect.DeepEqual(methods, expected) {
+		t.Errorf("TestGenericReceivers: expected the methods %v, got %v", expected, methods)
+	}
+	rendered := parser.Render()
+	for _, typeParameter := range []string{`"gen.T"`, `"gen.K"`, `"gen.V"`} {
+		if strings.Contains(rendered, typeParameter) {
+			t.Errorf("TestGenericReceivers: expected no relationship to the type parameter %s, got\n%s", typeParameter, rendered)
+		}
+	}
+	if !strings.Contains(rendered, `"gen.Pair" *-- "gen.Box"`) {
+		t.Errorf("TestGenericReceivers: expected gen.Box to compose gen.Pair, got\n%s", rendered)
+	}
+}
@@ -254,6 +254,24 @@ func TestGetFieldType(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:           "Test *ast.IndexExpr",
+			ExpectedResult: fmt.Sprintf("%sRepo~*%sUser~", packageConstant, packageConstant),
+			ExpectedFundamentalTypes: []string{
+				fmt.Sprintf("%sRepo", packageConstant),
+				fmt.Sprintf("%sUser", packageConstant),
+			},
+			InputField: &ast.IndexExpr{
+				X: &ast.Ident{
+					Name: "Repo",
+				},
+				Index: &ast.StarExpr{
+					X: &ast.Ident{
+						Name: "User",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -277,3 +295,13 @@ func TestIsPrimitiveStringPointer(t *testing.T) {
 		t.Errorf("TestIsPrimitiveStringPointer: expecting true, got false")
 	}
 }
+
+func TestReplacePackageConstant(t *testing.T) {
+	field, _ := getFieldType(&ast.MapType{Key: &ast.Ident{Name: "UserID"}, Value: &ast.StarExpr{X: &ast.Ident{Name: "Session"}}}, map[string]string{})
+	if result := replacePackageConstant(field, "sessions"); result != "<font color=blue>map</font>[sessions.UserID]*sessions.Session" {
+		t.Errorf("TestReplacePackageConstant: expected every local type to be qualified, got %s", result)
+	}
+	if result := replacePackageConstant(field, ""); result != "<font color=blue>map</font>[UserID]*Session" {
+		t.Errorf("TestReplacePackageConstant: expected every local type to be unqualified, got %s", result)
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileGroup are the names of the structures of a package declared in the same file
+type fileGroup struct {
+	fileName string
+	names    []string
+}
+
+// getFileGroups returns the given sorted names of structures grouped by the file declaring them, sorted by file, when
+// RenderFileGroups is used. Otherwise all of them are returned in a group without file name. The structures of which
+// the file is not known, such as the types of which only methods were parsed, are also in the group without file name.
+func (p *ClassParser) getFileGroups(names []string, structures map[string]*Struct) []*fileGroup {
+	if !p.renderingOptions.FileGroups {
+		return []*fileGroup{{names: names}}
+	}
+	groups := map[string]*fileGroup{}
+	for _, name := range names {
+		fileName := structures[name].FileName
+		if _, ok := groups[fileName]; !ok {
+			groups[fileName] = &fileGroup{fileName: fileName}
+		}
+		groups[fileName].names = append(groups[fileName].names, name)
+	}
+	result := make([]*fileGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].fileName < result[j].fileName
+	})
+	return result
+}
+
+// renderFileGroup renders the structures of the given file of the package pack, rendered in group, nested in a
+// package block labelled with the base name of the file. Nothing is rendered for the group without file name, its
+// structures being rendered directly in the namespace.
+func renderFileGroup(pack string, fileName string, group *LineStringBuilder, str *LineStringBuilder) {
+	if fileName == "" {
+		return
+	}
+	// the name of the block cannot contain the namespace separator, and must be unique in the diagram even when
+	// files of several directories or packages share a name
+	alias := plantUMLAliasRegexp.ReplaceAllString(pack+"."+filepath.ToSlash(fileName), "_")
+	str.WriteLineWithDepth(1, fmt.Sprintf(`package "%s" as %s {`, filepath.Base(fileName), alias))
+	for _, line := range strings.Split(strings.TrimSuffix(group.String(), "\n"), "\n") {
+		if line == "" {
+			str.WriteLineWithDepth(0, "")
+			continue
+		}
+		str.WriteLineWithDepth(1, line)
+	}
+	str.WriteLineWithDepth(1, "}")
+}
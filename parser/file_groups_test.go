@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestRenderFileGroups(t *testing.T) {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/testfiles"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{RenderFileGroups: true},
+		IncludeTests:       true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if fileName := filepath.Base(parser.Structs()["testfiles.Client"].FileName); fileName != "client.go" {
+		t.Errorf("TestRenderFileGroups: expected Client to be declared in client.go, got %s", fileName)
+	}
+	result := parser.Render()
+	for _, expected := range []string{
+		"namespace testfiles {\n    package \"client.go\" as testfiles____testingsupport_testfiles_client_go {\n        class Client << (S,Aquamarine) >> {",
+		"    package \"client_test.go\" as testfiles____testingsupport_testfiles_client_test_go {\n        class fakeGetter << (S,Aquamarine) test >> {",
+		"namespace testfiles_test {\n    package \"external_test.go\" as testfiles_test____testingsupport_testfiles_external_test_go {",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("TestRenderFileGroups: expected the render to contain %s, got %s", expected, result)
+		}
+	}
+}
+
+func TestGetFileGroups(t *testing.T) {
+	parser := getEmptyParser("main")
+	structures := map[string]*Struct{
+		"A": {FileName: "dir/b.go"},
+		"B": {FileName: "dir/a.go"},
+		"C": {FileName: "dir/b.go"},
+		"D": {FileName: "other/b.go"},
+		"E": {},
+	}
+	names := []string{"A", "B", "C", "D", "E"}
+	if groups := parser.getFileGroups(names, structures); len(groups) != 1 || groups[0].fileName != "" || len(groups[0].names) != 5 {
+		t.Errorf("TestGetFileGroups: expected a single group without RenderFileGroups, got %v", groups)
+	}
+	parser.renderingOptions.FileGroups = true
+	groups := parser.getFileGroups(names, structures)
+	files := []string{}
+	for _, group := range groups {
+		files = append(files, group.fileName+":"+strings.Join(group.names, ","))
+	}
+	if expected := ":E dir/a.go:B dir/b.go:A,C other/b.go:D"; strings.Join(files, " ") != expected {
+		t.Errorf("TestGetFileGroups: expected the groups %s, got %v", expected, files)
+	}
+}
+
+func TestRenderFileGroupsOfFilesWithTheSameName(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{
+		"a/models/types.go": "package models\n\ntype A struct{}\n",
+		"b/models/types.go": "package models\n\ntype B struct{}\n",
+	}, &ClassDiagramOptions{RenderingOptions: map[RenderingOption]interface{}{RenderFileGroups: true}})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	result := parser.Render()
+	for _, expected := range []string{
+		"package \"types.go\" as models_a_models_types_go {\n        class A",
+		"package \"types.go\" as models_b_models_types_go {\n        class B",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("TestRenderFileGroupsOfFilesWithTheSameName: expected the render to contain %s, got %s", expected, result)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// filterTypes removes every structure whose fully qualified name (e.g. parser.Struct) does not match include or
+// matches exclude. Relationships pointing to the removed structures are pruned so they do not dangle in the diagram.
+// A nil regular expression is ignored.
+func (p *ClassParser) filterTypes(include *regexp.Regexp, exclude *regexp.Regexp) {
+	if include == nil && exclude == nil {
+		return
+	}
+	p.removeTypes(func(fullName string) bool {
+		return (include != nil && !include.MatchString(fullName)) || (exclude != nil && exclude.MatchString(fullName))
+	})
+}
+
+// removeTypes removes every structure whose fully qualified name matches and prunes the relationships pointing to them
+func (p *ClassParser) removeTypes(matches func(fullName string) bool) {
+	removed := map[string]struct{}{}
+	for pack, structures := range p.structure {
+		for name, st := range structures {
+			fullName := getStructFullName(st, pack, name)
+			if matches(fullName) {
+				removed[fullName] = struct{}{}
+				delete(structures, name)
+				delete(p.allStructs, fullName)
+				delete(p.allInterfaces, fullName)
+			}
+		}
+	}
+	p.removeAliasesOf(removed)
+	for pack, structures := range p.structure {
+		for _, st := range structures {
+			pruneRelationships(st.Composition, pack, removed)
+			pruneRelationships(st.Extends, pack, removed)
+			pruneRelationships(st.Aggregations, pack, removed)
+			pruneRelationships(st.PrivateAggregations, pack, removed)
+			pruneRelationships(st.Conversions, pack, removed)
+			pruneRelationships(st.Dependencies, pack, removed)
+		}
+	}
+	p.pruneAliases(removed)
+}
+
+// removeAliasesOf removes the aliases and defined types of the removed structures, and the ones of these aliases, adding
+// them to removed
+func (p *ClassParser) removeAliasesOf(removed map[string]struct{}) {
+	for changed := true; changed; {
+		changed = false
+		for key, alias := range p.allAliases {
+			if _, ok := removed[key]; ok {
+				continue
+			}
+			if _, ok := removed[getAliasTarget(alias)]; !ok {
+				continue
+			}
+			removed[key] = struct{}{}
+			delete(p.structure[alias.PackageName], key)
+			changed = true
+		}
+	}
+}
+
+// getAliasTarget returns the package qualified name of the type the alias is declared with, without its pointer,
+// slice and array prefixes (e.g. ex.Gone for type Pair [2]*Gone)
+func getAliasTarget(alias *Alias) string {
+	split := strings.SplitN(alias.Name, ".", 2)
+	if len(split) < 2 {
+		return alias.Name
+	}
+	name := strings.TrimLeft(split[1], "*[]0123456789")
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", split[0], name)
+}
+
+// pruneAliases removes the removed aliases, the ones whose AliasOf, which is the name of the alias type itself, was
+// removed
+func (p *ClassParser) pruneAliases(removed map[string]struct{}) {
+	for key, alias := range p.allAliases {
+		if _, ok := removed[key]; !ok {
+			continue
+		}
+		delete(p.allAliases, key)
+		if needsRenaming(alias.Name) {
+			pack := strings.SplitN(alias.Name, ".", 2)
+			for key, name := range p.allRenamedStructs[pack[0]] {
+				if name == pack[1] {
+					delete(p.allRenamedStructs[pack[0]], key)
+				}
+			}
+		}
+	}
+}
+
+// getStructFullName returns the package qualified name of the structure. Aliases and enums are already stored with
+// their package name.
+func getStructFullName(st *Struct, pack string, name string) string {
+	if st.Type == "alias" || st.Type == "enum" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", pack, name)
+}
+
+func pruneRelationships(relationships map[string]struct{}, pack string, removed map[string]struct{}) {
+	for target := range relationships {
+		fullName := strings.TrimPrefix(target, "*")
+		if !strings.Contains(fullName, ".") {
+			fullName = fmt.Sprintf("%s.%s", pack, fullName)
+		}
+		if _, ok := removed[fullName]; ok {
+			delete(relationships, target)
+		}
+	}
+}
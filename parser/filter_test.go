@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFilterTypes(t *testing.T) {
+	tt := []struct {
+		Name           string
+		Include        *regexp.Regexp
+		Exclude        *regexp.Regexp
+		ExpectedResult string
+	}{
+		{
+			Name:    "Include",
+			Include: regexp.MustCompile(`Interface$`),
+			ExpectedResult: `@startuml
+namespace connectionlabels {
+    interface AbstractInterface  {
+        - interfaceFunction() bool
+
+    }
+    class ImplementsAbstractInterface << (S,Aquamarine) >> {
+        + PublicUse AbstractInterface
+
+        - interfaceFunction() bool
+
+    }
+}
+
+"connectionlabels.AbstractInterface" <|-- "connectionlabels.ImplementsAbstractInterface"
+
+"connectionlabels.ImplementsAbstractInterface" o-- "connectionlabels.AbstractInterface"
+
+@enduml
+`,
+		},
+		{
+			Name:    "Exclude",
+			Exclude: regexp.MustCompile(`^connectionlabels\.AbstractInterface$`),
+			ExpectedResult: `@startuml
+namespace connectionlabels {
+    class ImplementsAbstractInterface << (S,Aquamarine) >> {
+        + PublicUse AbstractInterface
+
+        - interfaceFunction() bool
+
+    }
+    class connectionlabels.AliasOfInt << (T, #FF7700) >>  {
+    }
+}
+"connectionlabels.AliasOfInt" *-- "connectionlabels.ImplementsAbstractInterface"
+
+
+
+@enduml
+`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:  afero.NewOsFs(),
+				Directories: []string{"../testingsupport/connectionlabels"},
+				RenderingOptions: map[RenderingOption]interface{}{
+					RenderAggregations:   true,
+					RenderPrivateMembers: true,
+				},
+				IncludeTypes: tc.Include,
+				ExcludeTypes: tc.Exclude,
+			})
+			if err != nil {
+				t.Errorf("expected no error but got %s", err.Error())
+				return
+			}
+			result := parser.Render()
+			if result != tc.ExpectedResult {
+				t.Errorf("expecting \n%s\n got \n%s\n", tc.ExpectedResult, result)
+			}
+		})
+	}
+}
+
+const aliasedTypesSource = `package ex
+
+type Gone struct {
+	A int
+}
+
+type Kept struct {
+	G Gone
+}
+
+type DTO Gone
+
+type Ref = *DTO
+
+type Pair [2]Gone
+`
+
+func TestFilterTypesWithAliases(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"ex/ex.go": aliasedTypesSource}, &ClassDiagramOptions{
+		ExcludeTypes:     regexp.MustCompile(`ex.Gone$`),
+		RenderingOptions: map[RenderingOption]interface{}{RenderAliases: true, RenderAggregations: true},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	for name, rendered := range map[string]string{"plantuml": parser.Render(), "dot": parser.RenderDot()} {
+		if !strings.Contains(rendered, "Kept") {
+			t.Errorf("TestFilterTypesWithAliases: expected the %s render to keep Kept, got\n%s", name, rendered)
+		}
+		for _, unexpected := range []string{"Gone\"", "DTO", "Pair", "Ref"} {
+			if strings.Contains(rendered, unexpected) {
+				t.Errorf("TestFilterTypesWithAliases: expected no %s in the %s render, got\n%s", unexpected, name, rendered)
+			}
+		}
+	}
+}
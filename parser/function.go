@@ -12,6 +12,15 @@ type Function struct {
 	ReturnValues         []string
 	PackageName          string
 	FullNameReturnValues []string
+	Doc                  string
+	// FileName and Line are the position of the declaration of the method, unknown (empty and 0) for functions
+	FileName string
+	Line     int
+	// PointerReceiver is true for the methods declared on a pointer receiver (e.g. func (s *Server) Stop())
+	PointerReceiver bool
+	// Async is true for the methods launching goroutines. It is only found when ClassDiagramOptions.FindGoroutines is
+	// set.
+	Async bool
 }
 
 // SignturesAreEqual Returns true if the two functions have the same signature (parameter names are not checked)
@@ -30,6 +39,28 @@ func (f *Function) SignturesAreEqual(function *Function) bool {
 	return result
 }
 
+// signaturesAreEquivalent returns true if the two functions have the same signature once every parameter and return
+// value type is resolved with underlying. A nil underlying compares the types as they are.
+func (f *Function) signaturesAreEquivalent(function *Function, underlying func(string) string) bool {
+	if underlying == nil {
+		return f.SignturesAreEqual(function)
+	}
+	if function.Name != f.Name || len(f.Parameters) != len(function.Parameters) || len(f.FullNameReturnValues) != len(function.FullNameReturnValues) {
+		return false
+	}
+	for i, p := range f.Parameters {
+		if underlying(p.FullType) != underlying(function.Parameters[i].FullType) {
+			return false
+		}
+	}
+	for i, r := range f.FullNameReturnValues {
+		if underlying(r) != underlying(function.FullNameReturnValues[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // generate and return a function object from the given Functype. The names must be passed to this
 // function since the FuncType does not have this information
 func getFunction(f *ast.FuncType, name string, aliases map[string]string, packageName string) *Function {
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+const instanceOf = `"instance of"`
+
+// GlobalVariable holds the information of a package level variable. FundamentalTypes contains the package
+// qualified types the variable holds so it can be linked to the parsed structures.
+type GlobalVariable struct {
+	Name             string
+	PackageName      string
+	Type             string
+	FundamentalTypes []string
+}
+
+// addGlobalVariables registers every variable declared in the given spec
+func (p *ClassParser) addGlobalVariables(spec *ast.ValueSpec) {
+	for i, name := range spec.Names {
+		if name.Name == "_" {
+			continue
+		}
+		typeExpression := spec.Type
+		if typeExpression == nil && i < len(spec.Values) {
+			typeExpression = getLiteralType(spec.Values[i])
+		}
+		theType, fundamentalTypes := getFieldType(typeExpression, p.allImports)
+		qualifiedTypes := make([]string, 0, len(fundamentalTypes))
+		for _, t := range fundamentalTypes {
+			qualifiedTypes = append(qualifiedTypes, replacePackageConstant(t, p.currentPackageName))
+		}
+		p.allGlobals[p.currentPackageName] = append(p.allGlobals[p.currentPackageName], &GlobalVariable{
+			Name:             name.Name,
+			PackageName:      p.currentPackageName,
+			Type:             replacePackageConstant(theType, ""),
+			FundamentalTypes: qualifiedTypes,
+		})
+	}
+}
+
+// getLiteralType returns the type of composite literals (Foo{} and &Foo{}). Other expressions cannot be resolved
+// without type checking and return nil.
+func getLiteralType(value ast.Expr) ast.Expr {
+	switch v := value.(type) {
+	case *ast.CompositeLit:
+		return v.Type
+	case *ast.UnaryExpr:
+		if v.Op != token.AND {
+			return nil
+		}
+		if t := getLiteralType(v.X); t != nil {
+			return &ast.StarExpr{X: t}
+		}
+	}
+	return nil
+}
+
+// GlobalVariables returns all the package level variables found, sorted by package and name. These are the global
+// mutable state of every package.
+func (p *ClassParser) GlobalVariables() []*GlobalVariable {
+	result := []*GlobalVariable{}
+	for _, globals := range p.allGlobals {
+		result = append(result, globals...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].PackageName != result[j].PackageName {
+			return result[i].PackageName < result[j].PackageName
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// renderSingletons renders the package level variables holding one of the parsed structs as singleton objects
+func (p *ClassParser) renderSingletons(pack string, str *LineStringBuilder, edges *LineStringBuilder) {
+	if !p.renderingOptions.Singletons {
+		return
+	}
+	instanceString := ""
+	if p.renderingOptions.ConnectionLabels {
+		instanceString = instanceOf
+	}
+	globals := append([]*GlobalVariable{}, p.allGlobals[pack]...)
+	sort.SliceStable(globals, func(i, j int) bool {
+		return globals[i].Name < globals[j].Name
+	})
+	for _, global := range globals {
+		structTypes := []string{}
+		for _, t := range global.FundamentalTypes {
+			if st := p.getStruct(t); st != nil && st.Type == "class" {
+				structTypes = append(structTypes, t)
+			}
+		}
+		if len(structTypes) == 0 {
+			continue
+		}
+		str.WriteLineWithDepth(1, fmt.Sprintf(`object %s <<singleton>>`, global.Name))
+		for _, t := range structTypes {
+			edges.WriteLineWithDepth(0, fmt.Sprintf(`"%s.%s" ..> %s"%s"`, pack, global.Name, instanceString, t))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGlobalVariables(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/singletons"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestGlobalVariables: expected no error but got %s", err.Error())
+		return
+	}
+	expected := []*GlobalVariable{
+		{Name: "DefaultRegistry", PackageName: "singletons", Type: "*Registry", FundamentalTypes: []string{"singletons.Registry"}},
+		{Name: "counter", PackageName: "singletons", Type: "int", FundamentalTypes: []string{}},
+		{Name: "defaultConfig", PackageName: "singletons", Type: "Config", FundamentalTypes: []string{"singletons.Config"}},
+	}
+	result := parser.GlobalVariables()
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("TestGlobalVariables: expected %v, got %v", expected, result)
+	}
+}
+
+func TestRenderSingletons(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/singletons"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderSingletons: expected no error but got %s", err.Error())
+		return
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderSingletons: true,
+		RenderFields:     false,
+	})
+	result := parser.Render()
+	expectedResult := `@startuml
+namespace singletons {
+    class Config << (S,Aquamarine) >> {
+        + Name string
+
+    }
+    class Registry << (S,Aquamarine) >> {
+    }
+    object DefaultRegistry <<singleton>>
+    object defaultConfig <<singleton>>
+}
+
+
+"singletons.DefaultRegistry" ..> "singletons.Registry"
+"singletons.defaultConfig" ..> "singletons.Config"
+
+hide fields
+@enduml
+`
+	if result != expectedResult {
+		t.Errorf("TestRenderSingletons: expecting \n%s\n got \n%s\n", expectedResult, result)
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"sort"
+)
+
+// TypeCoupling holds the number of types related to a parsed type, through relationships of any kind
+type TypeCoupling struct {
+	Name string `json:"name"`
+	// FanIn is the number of parsed types with a relationship to this one
+	FanIn int `json:"fanIn"`
+	// FanOut is the number of non builtin types, parsed or not, this one has a relationship with
+	FanOut int `json:"fanOut"`
+}
+
+// MostDependedUponTypes returns the given number of parsed types with the highest FanIn, the highest first. The
+// types of the same FanIn are sorted by name and the ones nothing depends upon are not listed.
+func (p *ClassParser) MostDependedUponTypes(count int) []*TypeCoupling {
+	return getTopCouplings(p.getTypeCouplings(), count, func(c *TypeCoupling) int { return c.FanIn })
+}
+
+// MostDependentTypes returns the given number of parsed types with the highest FanOut, the highest first. The types
+// of the same FanOut are sorted by name and the ones depending on nothing are not listed.
+func (p *ClassParser) MostDependentTypes(count int) []*TypeCoupling {
+	return getTopCouplings(p.getTypeCouplings(), count, func(c *TypeCoupling) int { return c.FanOut })
+}
+
+// getTypeCouplings returns the coupling of every parsed type. A type related to another one by several kinds of
+// relationships is counted once.
+func (p *ClassParser) getTypeCouplings() []*TypeCoupling {
+	couplings := map[string]*TypeCoupling{}
+	for pack, structures := range p.structure {
+		for name, st := range structures {
+			fullName := getStructFullName(st, pack, name)
+			couplings[fullName] = &TypeCoupling{Name: fullName}
+		}
+	}
+	related := map[string]map[string]struct{}{}
+	for _, relation := range p.Relations() {
+		if relation.Source == relation.Target || isBuiltinName(relation.Target) {
+			continue
+		}
+		if _, ok := related[relation.Source]; !ok {
+			related[relation.Source] = map[string]struct{}{}
+		}
+		if _, ok := related[relation.Source][relation.Target]; ok {
+			continue
+		}
+		related[relation.Source][relation.Target] = struct{}{}
+		couplings[relation.Source].FanOut++
+		if target, ok := couplings[relation.Target]; ok {
+			target.FanIn++
+		}
+	}
+	result := make([]*TypeCoupling, 0, len(couplings))
+	for _, coupling := range couplings {
+		result = append(result, coupling)
+	}
+	return result
+}
+
+// getTopCouplings returns the given number of couplings with the highest non zero value, the highest first
+func getTopCouplings(couplings []*TypeCoupling, count int, value func(*TypeCoupling) int) []*TypeCoupling {
+	top := []*TypeCoupling{}
+	for _, coupling := range couplings {
+		if value(coupling) > 0 {
+			top = append(top, coupling)
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if value(top[i]) != value(top[j]) {
+			return value(top[i]) > value(top[j])
+		}
+		return top[i].Name < top[j].Name
+	})
+	if count < len(top) {
+		top = top[:count]
+	}
+	return top
+}
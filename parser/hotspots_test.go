@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+const hotspotsSource = `package shop
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type Customer struct {
+	Name string
+}
+
+type Order struct {
+	Customer *Customer
+	Buyer    Customer
+	clock    Clock
+	Created  time.Time
+}
+
+type Invoice struct {
+	Order    *Order
+	Customer *Customer
+}
+`
+
+func TestHotspots(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"shop/shop.go": hotspotsSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("TestHotspots: expected no error but got %s", err.Error())
+	}
+	expectedFanIn := []*TypeCoupling{
+		{Name: "shop.Customer", FanIn: 2},
+		{Name: "shop.Clock", FanIn: 1},
+	}
+	if types := parser.MostDependedUponTypes(2); !reflect.DeepEqual(types, expectedFanIn) {
+		t.Errorf("TestHotspots: expected the fan-in %v, got %v", expectedFanIn, types)
+	}
+	expectedFanOut := []*TypeCoupling{
+		{Name: "shop.Order", FanIn: 1, FanOut: 3},
+		{Name: "shop.Invoice", FanOut: 2},
+	}
+	if types := parser.MostDependentTypes(10); !reflect.DeepEqual(types, expectedFanOut) {
+		t.Errorf("TestHotspots: expected the fan-out %v, got %v", expectedFanOut, types)
+	}
+}
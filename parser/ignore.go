@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ignoreFileName is the name of the file listing the directories to ignore in the directories to parse
+const ignoreFileName = ".plantumlignore"
+
+// getDefaultIgnoredPatterns returns the patterns of the directories skipped by default when walking the
+// directories recursively
+func getDefaultIgnoredPatterns(options *ClassDiagramOptions) []string {
+	patterns := []string{"testdata", "node_modules"}
+	if !options.IncludeVendor {
+		patterns = append(patterns, "vendor")
+	}
+	if !options.IncludeHidden {
+		patterns = append(patterns, ".*")
+	}
+	return patterns
+}
+
+// isIgnoredDirectory returns true if the directory found walking root matches the given glob patterns. Patterns
+// without a slash (e.g. *_gen) match the name of the directory at any depth, the other ones (e.g. internal/*/testdata)
+// match its path relative to root, ** matching any number of directories. Like in a .gitignore file, the last matching
+// pattern wins and the ones starting with ! include the directories back.
+func isIgnoredDirectory(root string, directory string, patterns []string) bool {
+	relative, err := filepath.Rel(root, directory)
+	if err != nil || relative == "." {
+		return false
+	}
+	segments := strings.Split(filepath.ToSlash(relative), "/")
+	ignored := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimSuffix(filepath.ToSlash(strings.TrimPrefix(pattern, "!")), "/")
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if matchIgnorePattern(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), segments) {
+			ignored = !negated
+		}
+	}
+	return ignored
+}
+
+// matchIgnorePattern returns true if the segments of the path match the ones of the pattern
+func matchIgnorePattern(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchIgnorePattern(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+		return false
+	}
+	return matchIgnorePattern(pattern[1:], segments[1:])
+}
+
+// getIgnoreFilePatterns returns the patterns of the .plantumlignore file of the given directory, which uses the
+// .gitignore syntax, or nil if there is none
+func getIgnoreFilePatterns(fs afero.Fs, directory string) ([]string, error) {
+	content, err := afero.ReadFile(fs, filepath.Join(directory, ignoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	patterns := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIsIgnoredDirectory(t *testing.T) {
+	tt := []struct {
+		Name      string
+		Directory string
+		Patterns  []string
+		Expected  bool
+	}{
+		{
+			Name:      "root",
+			Directory: "/repo",
+			Patterns:  []string{"*"},
+			Expected:  false,
+		},
+		{
+			Name:      "name at any depth",
+			Directory: "/repo/services/users/mocks",
+			Patterns:  []string{"mocks"},
+			Expected:  true,
+		},
+		{
+			Name:      "name wildcard",
+			Directory: "/repo/api/models_gen",
+			Patterns:  []string{"*_gen"},
+			Expected:  true,
+		},
+		{
+			Name:      "double star",
+			Directory: "/repo/a/b/c/mocks",
+			Patterns:  []string{"**/mocks"},
+			Expected:  true,
+		},
+		{
+			Name:      "relative path",
+			Directory: "/repo/internal/users/testdata",
+			Patterns:  []string{"internal/*/testdata"},
+			Expected:  true,
+		},
+		{
+			Name:      "relative path at another depth",
+			Directory: "/repo/pkg/internal/users/testdata",
+			Patterns:  []string{"internal/*/testdata"},
+			Expected:  false,
+		},
+		{
+			Name:      "parent of a match",
+			Directory: "/repo/internal/users",
+			Patterns:  []string{"internal/*/testdata"},
+			Expected:  false,
+		},
+		{
+			Name:      "anchored name",
+			Directory: "/repo/services/mocks",
+			Patterns:  []string{"/mocks"},
+			Expected:  false,
+		},
+		{
+			Name:      "negated",
+			Directory: "/repo/api_gen",
+			Patterns:  []string{"*_gen", "!api_gen"},
+			Expected:  false,
+		},
+		{
+			Name:      "negated then ignored again",
+			Directory: "/repo/api_gen",
+			Patterns:  []string{"*_gen", "!api_gen", "api_*"},
+			Expected:  true,
+		},
+		{
+			Name:      "no match",
+			Directory: "/repo/services/users",
+			Patterns:  []string{"mocks", "*_gen"},
+			Expected:  false,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if result := isIgnoredDirectory("/repo", tc.Directory, tc.Patterns); result != tc.Expected {
+				t.Errorf("expected %s to be ignored by %v to be %t, got %t", tc.Directory, tc.Patterns, tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestIgnoredPatterns(t *testing.T) {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport"},
+		IgnoredDirectories: []string{},
+		IgnoredPatterns:    []string{"subfolder*"},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		Recursive:          true,
+	})
+	if err != nil {
+		t.Fatalf("TestIgnoredPatterns: expected no error but got %s", err.Error())
+	}
+	for _, pack := range []string{"subfolder", "subfolder2", "subfolder3"} {
+		if _, ok := parser.structure[pack]; ok {
+			t.Errorf("TestIgnoredPatterns: expected the package %s to be ignored", pack)
+		}
+	}
+	if _, ok := parser.structure["testingsupport"]; !ok {
+		t.Errorf("TestIgnoredPatterns: expected the package testingsupport to be parsed")
+	}
+}
+
+func TestIgnoreFile(t *testing.T) {
+	tt := []struct {
+		Name            string
+		IgnoredPatterns []string
+		Expected        map[string]bool
+	}{
+		{
+			Name: "file patterns",
+			Expected: map[string]bool{
+				"ignorefile": true,
+				"mocks":      false,
+				"models":     false,
+				"api":        true,
+			},
+		},
+		{
+			Name:            "given patterns win",
+			IgnoredPatterns: []string{"api_gen", "!mocks"},
+			Expected: map[string]bool{
+				"ignorefile": true,
+				"mocks":      true,
+				"models":     false,
+				"api":        false,
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:         afero.NewOsFs(),
+				Directories:        []string{"../testingsupport/ignorefile"},
+				IgnoredDirectories: []string{},
+				IgnoredPatterns:    tc.IgnoredPatterns,
+				RenderingOptions:   map[RenderingOption]interface{}{},
+				Recursive:          true,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			for pack, expected := range tc.Expected {
+				if _, ok := parser.structure[pack]; ok != expected {
+					t.Errorf("expected the package %s to be parsed to be %t, got %t", pack, expected, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultIgnoredDirectories(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, pack := range []string{"app", "vendor", "testdata", "node_modules", ".hidden"} {
+		directory := "/repo/" + pack
+		if pack == "app" {
+			directory = "/repo"
+		}
+		name := strings.TrimPrefix(pack, ".")
+		if err := afero.WriteFile(fs, directory+"/"+name+".go", []byte("package "+name+"\n\ntype T struct{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tt := []struct {
+		Name            string
+		IncludeVendor   bool
+		IncludeHidden   bool
+		IgnoredPatterns []string
+		Expected        []string
+	}{
+		{
+			Name:     "default",
+			Expected: []string{"app"},
+		},
+		{
+			Name:          "vendor and hidden",
+			IncludeVendor: true,
+			IncludeHidden: true,
+			Expected:      []string{"app", "hidden", "vendor"},
+		},
+		{
+			Name:            "included back",
+			IgnoredPatterns: []string{"!testdata", "!node_modules"},
+			Expected:        []string{"app", "node_modules", "testdata"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:      fs,
+				Directories:     []string{"/repo"},
+				IgnoredPatterns: tc.IgnoredPatterns,
+				Recursive:       true,
+				IncludeVendor:   tc.IncludeVendor,
+				IncludeHidden:   tc.IncludeHidden,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			packages := []string{}
+			for pack := range parser.structure {
+				packages = append(packages, pack)
+			}
+			sort.Strings(packages)
+			if !reflect.DeepEqual(packages, tc.Expected) {
+				t.Errorf("expected the packages %v, got %v", tc.Expected, packages)
+			}
+		})
+	}
+}
+
+func TestGetIgnoreFilePatterns(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	patterns, err := getIgnoreFilePatterns(fs, "/repo")
+	if err != nil || patterns != nil {
+		t.Errorf("TestGetIgnoreFilePatterns: expected no patterns nor error without a file, got %v and %v", patterns, err)
+	}
+	afero.WriteFile(fs, "/repo/.plantumlignore", []byte("# comment\n\nmocks/  \n\\#hash\n\\!bang\n!kept\n"), 0644)
+	patterns, err = getIgnoreFilePatterns(fs, "/repo")
+	if err != nil {
+		t.Fatalf("TestGetIgnoreFilePatterns: expected no error but got %s", err.Error())
+	}
+	expected := []string{"mocks/", "#hash", "!bang", "!kept"}
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Errorf("TestGetIgnoreFilePatterns: expected %v, got %v", expected, patterns)
+	}
+}
@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+)
+
+// DefaultIgnoredAggregations are the standard library types most structures hold (e.g. a time.Time or a sync.Mutex)
+// whose aggregations are usually noise. They can be given to the IgnoredAggregations rendering option.
+var DefaultIgnoredAggregations = []string{"context.Context", "sync.*", "time.*"}
+
+// setIgnoredAggregations sets the IgnoredAggregations rendering option, checking the patterns
+func (p *ClassParser) setIgnoredAggregations(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Invalid ignored aggregation pattern %s", pattern)
+		}
+	}
+	p.renderingOptions.IgnoredAggregations = patterns
+	return nil
+}
+
+// isIgnoredAggregation returns true if the package qualified name of the aggregated type (e.g. time.Time) matches
+// one of the IgnoredAggregations patterns
+func (p *ClassParser) isIgnoredAggregation(name string) bool {
+	for _, pattern := range p.renderingOptions.IgnoredAggregations {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
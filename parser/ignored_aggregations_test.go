@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnoredAggregations(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/externaltypes"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderAggregations: true}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if rendered := parser.Render(); !strings.Contains(rendered, `o-- "time.Duration"`) {
+		t.Errorf("TestIgnoredAggregations: expected the aggregation of time.Duration by default, got %s", rendered)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{IgnoredAggregations: DefaultIgnoredAggregations}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	if strings.Contains(rendered, `o-- "time.Duration"`) {
+		t.Errorf("TestIgnoredAggregations: expected the aggregation of time.Duration to be ignored, got %s", rendered)
+	}
+	if !strings.Contains(rendered, `o-- "io.Reader"`) {
+		t.Errorf("TestIgnoredAggregations: expected the aggregation of io.Reader, got %s", rendered)
+	}
+	if rendered := parser.RenderDot(); strings.Contains(rendered, `"time.Duration" [dir=both`) {
+		t.Errorf("TestIgnoredAggregations: expected the aggregation of time.Duration to be ignored in dot, got %s", rendered)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{IgnoredAggregations: []string{"[time"}}); err == nil {
+		t.Errorf("TestIgnoredAggregations: expected an error for an invalid pattern")
+	}
+}
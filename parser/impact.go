@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TypeReference describes a place in the parsed code that references a given type.
+// Kind is one of field, parameter, return, composition, implements or alias. Member holds the
+// field or method name when the reference comes from one.
+type TypeReference struct {
+	PackageName string
+	StructName  string
+	Kind        string
+	Member      string
+}
+
+// FindReferences returns every structure member and relationship referencing the given fully qualified
+// type name (e.g. parser.Struct). It can be used to estimate the blast radius of renaming that type.
+func (p *ClassParser) FindReferences(typeName string) []TypeReference {
+	split := strings.SplitN(typeName, ".", 2)
+	if len(split) != 2 {
+		return []TypeReference{}
+	}
+	qualified := regexp.MustCompile(fmt.Sprintf(`(^|[^\w.])%s\b`, regexp.QuoteMeta(typeName)))
+	local := regexp.MustCompile(fmt.Sprintf(`(^|[^\w.])%s\b`, regexp.QuoteMeta(split[1])))
+	result := []TypeReference{}
+	packages := []string{}
+	for pack := range p.structure {
+		packages = append(packages, pack)
+	}
+	sort.Strings(packages)
+	for _, pack := range packages {
+		matcher := qualified
+		if pack == split[0] {
+			matcher = local
+		}
+		structures := p.structure[pack]
+		names := []string{}
+		for name := range structures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if fmt.Sprintf("%s.%s", pack, name) == typeName {
+				continue
+			}
+			result = append(result, findStructReferences(structures[name], pack, name, typeName, matcher, qualified)...)
+		}
+	}
+	orderedAliases := AliasSlice{}
+	for _, alias := range p.allAliases {
+		orderedAliases = append(orderedAliases, *alias)
+	}
+	sort.Sort(orderedAliases)
+	for _, alias := range orderedAliases {
+		if alias.Name == typeName {
+			aliasName := strings.TrimPrefix(alias.AliasOf, alias.PackageName+".")
+			result = append(result, TypeReference{PackageName: alias.PackageName, StructName: aliasName, Kind: "alias"})
+		}
+	}
+	return result
+}
+
+// ReferencingPackages returns the sorted list of packages that contain at least one of the given references
+func ReferencingPackages(references []TypeReference) []string {
+	packages := map[string]struct{}{}
+	for _, r := range references {
+		packages[r.PackageName] = struct{}{}
+	}
+	return getSortedKeys(packages)
+}
+
+func findStructReferences(st *Struct, pack, name, typeName string, localMatcher, qualifiedMatcher *regexp.Regexp) []TypeReference {
+	result := []TypeReference{}
+	newReference := func(kind, member string) TypeReference {
+		return TypeReference{PackageName: pack, StructName: name, Kind: kind, Member: member}
+	}
+	for _, f := range st.Fields {
+		if localMatcher.MatchString(f.Type) {
+			result = append(result, newReference("field", f.Name))
+		}
+	}
+	for _, f := range st.Functions {
+		for _, param := range f.Parameters {
+			if qualifiedMatcher.MatchString(param.FullType) {
+				result = append(result, newReference("parameter", f.Name))
+				break
+			}
+		}
+		for _, returnValue := range f.FullNameReturnValues {
+			if qualifiedMatcher.MatchString(returnValue) {
+				result = append(result, newReference("return", f.Name))
+				break
+			}
+		}
+	}
+	for c := range st.Composition {
+		if c == typeName || fmt.Sprintf("%s.%s", pack, c) == typeName {
+			result = append(result, newReference("composition", ""))
+		}
+	}
+	if _, ok := st.Extends[typeName]; ok {
+		result = append(result, newReference("implements", ""))
+	}
+	return result
+}
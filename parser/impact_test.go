@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindReferences(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/connectionlabels"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestFindReferences: expected no error but got %s", err.Error())
+		return
+	}
+	tt := []struct {
+		Name     string
+		TypeName string
+		Expected []TypeReference
+	}{
+		{
+			Name:     "Field and implementation",
+			TypeName: "connectionlabels.AbstractInterface",
+			Expected: []TypeReference{
+				{PackageName: "connectionlabels", StructName: "ImplementsAbstractInterface", Kind: "field", Member: "PublicUse"},
+				{PackageName: "connectionlabels", StructName: "ImplementsAbstractInterface", Kind: "implements"},
+			},
+		},
+		{
+			Name:     "Composition",
+			TypeName: "connectionlabels.AliasOfInt",
+			Expected: []TypeReference{
+				{PackageName: "connectionlabels", StructName: "ImplementsAbstractInterface", Kind: "composition"},
+			},
+		},
+		{
+			Name:     "Alias",
+			TypeName: "__builtin__.int",
+			Expected: []TypeReference{
+				{PackageName: "connectionlabels", StructName: "AliasOfInt", Kind: "alias"},
+			},
+		},
+		{
+			Name:     "Not qualified",
+			TypeName: "AbstractInterface",
+			Expected: []TypeReference{},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			result := parser.FindReferences(tc.TypeName)
+			if !reflect.DeepEqual(result, tc.Expected) {
+				t.Errorf("expected %v, got %v", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestReferencingPackages(t *testing.T) {
+	result := ReferencingPackages([]TypeReference{
+		{PackageName: "b"},
+		{PackageName: "a"},
+		{PackageName: "b"},
+	})
+	if !reflect.DeepEqual(result, []string{"a", "b"}) {
+		t.Errorf("TestReferencingPackages: expected [a b], got %v", result)
+	}
+}
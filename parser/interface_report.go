@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// markupRegexp matches the PlantUML formatting of the rendered types (e.g. <font color=blue>map</font>)
+var markupRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// InterfaceSatisfaction lists the structures that implement a parsed interface and the ones that are one method away
+// from implementing it
+type InterfaceSatisfaction struct {
+	// Interface is the package qualified name of the interface
+	Interface string
+	// Implementations are the sorted package qualified names of the structures implementing the interface
+	Implementations []string
+	// NearMisses are the structures missing a single method of the interface, sorted by name
+	NearMisses []*NearMiss
+}
+
+// NearMiss is a structure that would implement an interface with one more method. The structure has at least one
+// method named like a method of the interface, so unrelated structures are not reported for small interfaces.
+type NearMiss struct {
+	// Struct is the package qualified name of the structure
+	Struct string
+	// MissingMethod is the signature of the method of the interface the structure lacks (e.g. Close() error). The
+	// structure may have a method with the same name and another signature.
+	MissingMethod string
+}
+
+// InterfaceSatisfactions returns the implementations and near misses of every parsed interface with methods, sorted
+// by interface name
+func (p *ClassParser) InterfaceSatisfactions() []*InterfaceSatisfaction {
+	var underlying func(string) string
+	if p.matchUnderlyingTypes {
+		underlying = p.getUnderlyingTypeName
+	}
+	structs := p.Structs()
+	result := []*InterfaceSatisfaction{}
+	for _, interfaceName := range getSortedKeys(p.allInterfaces) {
+		inter := p.getStruct(interfaceName)
+		if inter == nil || inter.Type != "interface" {
+			continue
+		}
+		methods := p.getInterfaceMethodSet(inter, map[*Struct]struct{}{}, map[string]struct{}{})
+		if len(methods) == 0 {
+			continue
+		}
+		satisfaction := &InterfaceSatisfaction{Interface: interfaceName, Implementations: []string{}, NearMisses: []*NearMiss{}}
+		for _, name := range getSortedKeys(p.allStructs) {
+			st := structs[name]
+			if st == nil || st.Type != "class" {
+				continue
+			}
+			if _, ok := st.Extends[interfaceName]; ok {
+				satisfaction.Implementations = append(satisfaction.Implementations, name)
+			} else if missing := getMissingMethod(st, methods, underlying); missing != nil {
+				satisfaction.NearMisses = append(satisfaction.NearMisses, &NearMiss{Struct: name, MissingMethod: getSignature(missing)})
+			}
+		}
+		result = append(result, satisfaction)
+	}
+	return result
+}
+
+// getMissingMethod returns the only method of the given ones the structure does not implement, provided the
+// structure has a method named like one of them. It returns nil otherwise.
+func getMissingMethod(st *Struct, methods []*Function, underlying func(string) string) *Function {
+	var missing *Function
+	related := false
+	for _, method := range methods {
+		implemented := false
+		for _, function := range st.Functions {
+			related = related || function.Name == method.Name
+			if method.signaturesAreEquivalent(function, underlying) {
+				implemented = true
+				break
+			}
+		}
+		if !implemented {
+			if missing != nil {
+				return nil
+			}
+			missing = method
+		}
+	}
+	if !related {
+		return nil
+	}
+	return missing
+}
+
+// getSignature returns the signature of the function without its parameter names (e.g. Write([]byte) (int, error))
+func getSignature(function *Function) string {
+	parameters := make([]string, 0, len(function.Parameters))
+	for _, parameter := range function.Parameters {
+		parameters = append(parameters, parameter.Type)
+	}
+	signature := fmt.Sprintf("%s(%s)", function.Name, strings.Join(parameters, ", "))
+	switch len(function.ReturnValues) {
+	case 0:
+	case 1:
+		signature = fmt.Sprintf("%s %s", signature, function.ReturnValues[0])
+	default:
+		signature = fmt.Sprintf("%s (%s)", signature, strings.Join(function.ReturnValues, ", "))
+	}
+	return markupRegexp.ReplaceAllString(signature, "")
+}
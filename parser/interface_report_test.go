@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestInterfaceSatisfactions(t *testing.T) {
+	tt := []struct {
+		Name                 string
+		Directory            string
+		MatchUnderlyingTypes bool
+		TypeCheckImports     bool
+		Expected             []*InterfaceSatisfaction
+	}{
+		{
+			Name:             "promoted methods and wrong signature",
+			Directory:        "../testingsupport/implementations",
+			TypeCheckImports: true,
+			Expected: []*InterfaceSatisfaction{
+				{
+					Interface:       "implementations.Writer",
+					Implementations: []string{"implementations.Base", "implementations.Promoted"},
+					NearMisses:      []*NearMiss{{Struct: "implementations.NotImplementing", MissingMethod: "WriteTo(*bytes.Buffer) error"}},
+				},
+			},
+		},
+		{
+			Name:      "compiler rules",
+			Directory: "../testingsupport/underlyingtypes",
+			Expected: []*InterfaceSatisfaction{
+				{
+					Interface:       "underlyingtypes.Writer",
+					Implementations: []string{"underlyingtypes.NameWriter"},
+					NearMisses:      []*NearMiss{{Struct: "underlyingtypes.StringWriter", MissingMethod: "Write(string) error"}},
+				},
+			},
+		},
+		{
+			Name:                 "underlying types",
+			Directory:            "../testingsupport/underlyingtypes",
+			MatchUnderlyingTypes: true,
+			Expected: []*InterfaceSatisfaction{
+				{
+					Interface:       "underlyingtypes.Writer",
+					Implementations: []string{"underlyingtypes.NameWriter", "underlyingtypes.StringWriter"},
+					NearMisses:      []*NearMiss{},
+				},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:           afero.NewOsFs(),
+				Directories:          []string{tc.Directory},
+				IgnoredDirectories:   []string{},
+				RenderingOptions:     map[RenderingOption]interface{}{},
+				MatchUnderlyingTypes: tc.MatchUnderlyingTypes,
+				TypeCheckImports:     tc.TypeCheckImports,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			result := parser.InterfaceSatisfactions()
+			if !reflect.DeepEqual(result, tc.Expected) {
+				t.Errorf("expected %v, got %v", tc.Expected, result)
+				for _, satisfaction := range result {
+					t.Logf("%+v", *satisfaction)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMissingMethod(t *testing.T) {
+	read := &Function{Name: "Read", Parameters: []*Field{{Type: "[]byte", FullType: "[]byte"}}, ReturnValues: []string{"int", "error"}, FullNameReturnValues: []string{"int", "error"}}
+	close := &Function{Name: "Close", Parameters: []*Field{}, ReturnValues: []string{"error"}, FullNameReturnValues: []string{"error"}}
+	tt := []struct {
+		Name      string
+		Functions []*Function
+		Expected  *Function
+	}{
+		{Name: "implementing", Functions: []*Function{read, close}, Expected: nil},
+		{Name: "one method away", Functions: []*Function{read}, Expected: close},
+		{Name: "unrelated", Functions: []*Function{}, Expected: nil},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			st := &Struct{Functions: tc.Functions}
+			if result := getMissingMethod(st, []*Function{read, close}, nil); result != tc.Expected {
+				t.Errorf("expected %v, got %v", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestGetSignature(t *testing.T) {
+	function := &Function{
+		Name:         "Get",
+		Parameters:   []*Field{{Name: "m", Type: "<font color=blue>map</font>[string]int"}},
+		ReturnValues: []string{"int", "error"},
+	}
+	if result := getSignature(function); result != "Get(map[string]int) (int, error)" {
+		t.Errorf("TestGetSignature: expected Get(map[string]int) (int, error), got %s", result)
+	}
+}
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DirectionTopToBottom is the RenderDirection value laying the diagram out from top to bottom
+	DirectionTopToBottom = "top-to-bottom"
+	// DirectionLeftToRight is the RenderDirection value laying the diagram out from left to right
+	DirectionLeftToRight = "left-to-right"
+)
+
+// renderDirection renders the direction of the diagram (e.g. left to right direction) when one is set
+func (p *ClassParser) renderDirection(str *LineStringBuilder) {
+	if p.renderingOptions.Direction != "" {
+		str.WriteLineWithDepth(0, fmt.Sprintf("%s direction", strings.ReplaceAll(p.renderingOptions.Direction, "-", " ")))
+	}
+}
+
+// getNamespaceStart returns the line opening the block of the given package, a namespace or a quoted package when
+// RenderPackageKeyword is set or the name would break a namespace
+func (p *ClassParser) getNamespaceStart(name string) string {
+	if p.renderingOptions.PackageKeyword || !isPlantUMLName(name) {
+		return fmt.Sprintf(`package %s {`, quoteName(name))
+	}
+	return fmt.Sprintf(`namespace %s {`, name)
+}
+
+// renderHiddenNamespaceEdges chains the given namespaces with hidden edges when enabled, so they are laid out one
+// after another instead of being packed by PlantUML
+func (p *ClassParser) renderHiddenNamespaceEdges(namespaces []string, str *LineStringBuilder) {
+	if !p.renderingOptions.HiddenNamespaceEdges {
+		return
+	}
+	for i := 1; i < len(namespaces); i++ {
+		str.WriteLineWithDepth(0, fmt.Sprintf("%s -[hidden]-> %s", namespaces[i-1], namespaces[i]))
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderLayout(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder", "../testingsupport/subfolder2", "../testingsupport/subfolder3"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderDirection:            DirectionLeftToRight,
+		HideEmptyMembers:           true,
+		RenderHiddenNamespaceEdges: true,
+	}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{
+		"@startuml\nleft to right direction\n",
+		"subfolder -[hidden]-> subfolder2\nsubfolder2 -[hidden]-> subfolder3\n",
+		"hide empty members\n@enduml",
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestRenderLayout: expected the render to contain\n%s\ngot\n%s", expected, rendered)
+		}
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderDirection: "", HideEmptyMembers: false, RenderHiddenNamespaceEdges: false})
+	rendered = parser.Render()
+	for _, notExpected := range []string{"direction", "-[hidden]->", "hide empty members"} {
+		if strings.Contains(rendered, notExpected) {
+			t.Errorf("TestRenderLayout: expected the render not to contain %s, got\n%s", notExpected, rendered)
+		}
+	}
+}
+
+func TestInvalidDirection(t *testing.T) {
+	parser := getEmptyParser("main")
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderDirection: "diagonal"}); err == nil {
+		t.Error("TestInvalidDirection: expected an error for an unknown direction")
+	}
+}
+
+func TestRenderPackageKeyword(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if rendered := parser.Render(); !strings.Contains(rendered, "namespace subfolder {\n") {
+		t.Errorf("TestRenderPackageKeyword: expected a namespace by default, got\n%s", rendered)
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderPackageKeyword: true})
+	rendered := parser.Render()
+	if !strings.Contains(rendered, "package \"subfolder\" {\n") || strings.Contains(rendered, "namespace ") {
+		t.Errorf("TestRenderPackageKeyword: expected a quoted package instead of a namespace, got\n%s", rendered)
+	}
+	if start := getEmptyParser("main").getNamespaceStart("external.http"); start != "namespace external.http {" {
+		t.Errorf("TestRenderPackageKeyword: expected a namespace, got %s", start)
+	}
+}
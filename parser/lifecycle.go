@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// lifecycleMethods are the methods moving a structure to a new state, in the order they are expected to be called,
+// with the state they move it to
+var lifecycleMethods = []struct {
+	name  string
+	state string
+}{
+	{name: "Start", state: "Running"},
+	{name: "Run", state: "Running"},
+	{name: "Stop", state: "Stopped"},
+	{name: "Close", state: "Closed"},
+}
+
+// RenderLifecycles returns a PlantUML state diagram for every parsed structure with lifecycle methods (Start, Run,
+// Stop or Close). The structure is created by its constructor, a package function whose name starts with New
+// returning it, and moves through the states of its lifecycle methods in that order. This is experimental: the
+// methods are recognized by their names only.
+func (p *ClassParser) RenderLifecycles() string {
+	str := &LineStringBuilder{}
+	for _, name := range p.getLifecycleStructs() {
+		transitions := p.getLifecycleTransitions(p.getStructByFullName(name), name)
+		str.WriteLineWithDepth(0, "@startuml")
+		str.WriteLineWithDepth(0, fmt.Sprintf("title %s", name))
+		for _, transition := range transitions {
+			str.WriteLineWithDepth(0, transition)
+		}
+		str.WriteLineWithDepth(0, "@enduml")
+	}
+	return str.String()
+}
+
+// getLifecycleStructs returns the sorted package qualified names of the structures with lifecycle methods
+func (p *ClassParser) getLifecycleStructs() []string {
+	names := []string{}
+	for pack, structures := range p.structure {
+		for name, structure := range structures {
+			if structure.Type != "class" {
+				continue
+			}
+			for _, method := range lifecycleMethods {
+				if hasMethod(structure, method.name) {
+					names = append(names, getStructFullName(structure, pack, name))
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getLifecycleTransitions returns the PlantUML transitions between the states of the lifecycle of the given structure
+func (p *ClassParser) getLifecycleTransitions(structure *Struct, fullName string) []string {
+	created := "[*] --> Created"
+	if constructor := p.getConstructor(structure, fullName); constructor != "" {
+		created = fmt.Sprintf("%s : %s", created, constructor)
+	}
+	transitions := []string{created}
+	from, state := "Created", "Created"
+	for _, method := range lifecycleMethods {
+		if !hasMethod(structure, method.name) {
+			continue
+		}
+		if method.state != state {
+			// the alternatives leading to the same state (e.g. Start and Run) start from the same state
+			from = state
+		}
+		transitions = append(transitions, fmt.Sprintf("%s --> %s : %s", from, method.state, method.name))
+		state = method.state
+	}
+	if state == "Stopped" || state == "Closed" {
+		transitions = append(transitions, fmt.Sprintf("%s --> [*]", state))
+	}
+	return transitions
+}
+
+// getConstructor returns the name of the first package function whose name starts with New returning the given
+// structure (or a pointer to it), or an empty string
+func (p *ClassParser) getConstructor(structure *Struct, fullName string) string {
+	name := strings.TrimPrefix(fullName, structure.PackageName+".")
+	functions := append([]*Function{}, p.allFunctions[structure.PackageName]...)
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].Name < functions[j].Name
+	})
+	for _, function := range functions {
+		if strings.HasPrefix(function.Name, "New") && len(function.ReturnValues) > 0 && strings.TrimPrefix(function.ReturnValues[0], "*") == name {
+			return function.Name
+		}
+	}
+	return ""
+}
+
+func hasMethod(structure *Struct, name string) bool {
+	for _, function := range structure.Functions {
+		if function.Name == name {
+			return true
+		}
+	}
+	return false
+}
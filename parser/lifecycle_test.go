@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestRenderLifecycles(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/lifecycle"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	expected := `@startuml
+title lifecycle.File
+[*] --> Created
+Created --> Closed : Close
+Closed --> [*]
+@enduml
+@startuml
+title lifecycle.Server
+[*] --> Created : NewServer
+Created --> Running : Start
+Running --> Stopped : Stop
+Stopped --> [*]
+@enduml
+@startuml
+title lifecycle.Worker
+[*] --> Created : NewWorker
+Created --> Running : Run
+Running --> Closed : Close
+Closed --> [*]
+@enduml
+`
+	if rendered := parser.RenderLifecycles(); rendered != expected {
+		t.Errorf("TestRenderLifecycles: expected\n%s\ngot\n%s", expected, rendered)
+	}
+}
+
+func TestGetLifecycleTransitions(t *testing.T) {
+	structure := &Struct{
+		PackageName: "main",
+		Type:        "class",
+		Functions:   []*Function{{Name: "Close"}, {Name: "Run"}, {Name: "Stop"}, {Name: "Start"}},
+	}
+	expected := []string{
+		"[*] --> Created",
+		"Created --> Running : Start",
+		"Created --> Running : Run",
+		"Running --> Stopped : Stop",
+		"Stopped --> Closed : Close",
+		"Closed --> [*]",
+	}
+	parser := getEmptyParser("main")
+	transitions := parser.getLifecycleTransitions(structure, "main.Service")
+	if len(transitions) != len(expected) {
+		t.Fatalf("TestGetLifecycleTransitions: expected %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("TestGetLifecycleTransitions: expected %s, got %s", expected[i], transitions[i])
+		}
+	}
+}
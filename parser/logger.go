@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"time"
+)
+
+// Logger receives the progress of the parsing and rendering (e.g. the directories scanned with the number of files
+// parsed and types found, and how long each step took). *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logf reports the given progress to the logger, if any
+func (p *ClassParser) logf(format string, a ...interface{}) {
+	if p.logger != nil {
+		p.logger.Printf(format, a...)
+	}
+}
+
+// verbosef reports the given detail of the progress (e.g. every file parsed) to the logger when verbose
+func (p *ClassParser) verbosef(format string, a ...interface{}) {
+	if p.verbose {
+		p.logf(format, a...)
+	}
+}
+
+// countTypes returns the number of types parsed so far
+func (p *ClassParser) countTypes() int {
+	count := 0
+	for _, structures := range p.structure {
+		count += len(structures)
+	}
+	return count
+}
+
+// getElapsed returns the time elapsed since the given start rounded to the millisecond, to be logged
+func getElapsed(start time.Time) time.Duration {
+	return time.Since(start).Round(time.Millisecond)
+}
+
+// logRenderTime reports how long the rendering started at the given time took. It must be deferred.
+func (p *ClassParser) logRenderTime(start time.Time) {
+	p.logf("rendered in %s", getElapsed(start))
+}
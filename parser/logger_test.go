@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestLogger(t *testing.T) {
+	for _, verbose := range []bool{false, true} {
+		logger := &testLogger{}
+		parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+			FileSystem:         afero.NewOsFs(),
+			Directories:        []string{"../testingsupport/subfolder"},
+			IgnoredDirectories: []string{},
+			RenderingOptions:   map[RenderingOption]interface{}{},
+			Logger:             logger,
+			Verbose:            verbose,
+		})
+		if err != nil {
+			t.Fatalf("TestLogger: expected no error but got %s", err.Error())
+		}
+		parser.Render()
+		expected := []string{
+			"scanned ../testingsupport/subfolder: 1 files, 2 types in ",
+			"parsed 1 files in 1 directories, found 2 types in ",
+			"resolved the relationships in ",
+			"rendered in ",
+		}
+		if verbose {
+			expected = append([]string{"parsing ../testingsupport/subfolder/subfolder.go"}, expected...)
+		}
+		if len(logger.lines) != len(expected) {
+			t.Fatalf("TestLogger: expected %v, got %v", expected, logger.lines)
+		}
+		for i := range expected {
+			if !strings.HasPrefix(logger.lines[i], expected[i]) {
+				t.Errorf("TestLogger: expected %s to start with %s", logger.lines[i], expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"fmt"
+)
+
+// Merge adds the types, relationships and aliases parsed by other to the ones of p, so the code parsed from
+// different roots, or the models loaded with LoadModel, can be rendered as a single diagram. The relationships
+// between the types of both parsers (e.g. a structure implementing an interface of the other one) are resolved
+// again. When a type is found in both, the one of p is kept with the relationships of both. A diagnostic is added if
+// they were declared in different places. The package functions, globals and embeds of the packages found in both
+// are the ones of p. The rendering options of other are ignored.
+func (p *ClassParser) Merge(other *ClassParser) {
+	if other == nil || other == p {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for pack, structures := range other.structure {
+		if _, ok := p.structure[pack]; !ok {
+			p.structure[pack] = map[string]*Struct{}
+			p.mergePackage(other, pack)
+		}
+		for name, st := range structures {
+			p.mergeStruct(pack, name, st)
+		}
+	}
+	mergeSet(p.allInterfaces, other.allInterfaces)
+	mergeSet(p.allStructs, other.allStructs)
+	p.mergeNames(other)
+	mergeRelations(p.allConversions, other.allConversions)
+	mergeRelations(p.allDependencies, other.allDependencies)
+	p.mergeTypedImplements(other)
+	p.allProviders = append(p.allProviders, other.allProviders...)
+	p.diagnostics = append(p.diagnostics, other.diagnostics...)
+	p.parseErrors = append(p.parseErrors, other.parseErrors...)
+	p.parsedDirectories += other.parsedDirectories
+	p.parsedFiles += other.parsedFiles
+	p.resolveImplementations()
+	p.resolveConversions()
+	p.resolveDependencies()
+	p.contextlessMethods = map[*Function]struct{}{}
+	p.resolveContextlessMethods()
+	if p.renderingOptions.Cycles {
+		p.cyclicPackages = p.getCyclicPackages()
+	}
+}
+
+// mergeStruct adds the given structure of another parser, merging its relationships into the one of p with the same
+// name if any
+func (p *ClassParser) mergeStruct(pack string, name string, st *Struct) {
+	existing, ok := p.structure[pack][name]
+	if !ok {
+		p.structure[pack][name] = st
+		return
+	}
+	if existing.FileName != st.FileName || existing.Line != st.Line {
+		p.diagnostics = append(p.diagnostics, fmt.Sprintf("%s.%s is declared in both %s:%d and %s:%d, keeping the first",
+			pack, name, existing.FileName, existing.Line, st.FileName, st.Line))
+	}
+	mergeSet(existing.Composition, st.Composition)
+	mergeSet(existing.Extends, st.Extends)
+	mergeSet(existing.Aggregations, st.Aggregations)
+	mergeSet(existing.PrivateAggregations, st.PrivateAggregations)
+	mergeSet(existing.Conversions, st.Conversions)
+	for dependency := range st.Dependencies {
+		existing.AddToDependencies(dependency)
+	}
+}
+
+// mergeNames adds the aliases, renamed imports, external types and calls of another parser that p does not know
+func (p *ClassParser) mergeNames(other *ClassParser) {
+	for name, alias := range other.allAliases {
+		if _, ok := p.allAliases[name]; !ok {
+			p.allAliases[name] = alias
+		}
+	}
+	for pack, renamed := range other.allRenamedStructs {
+		if _, ok := p.allRenamedStructs[pack]; !ok {
+			p.allRenamedStructs[pack] = renamed
+		}
+	}
+	for name, external := range other.allExternals {
+		if _, ok := p.allExternals[name]; !ok {
+			p.allExternals[name] = external
+		}
+	}
+	for name, function := range other.allCalls {
+		if _, ok := p.allCalls[name]; !ok {
+			p.allCalls[name] = function
+		}
+	}
+}
+
+// mergeTypedImplements adds the typed implementations of another parser that p does not know
+func (p *ClassParser) mergeTypedImplements(other *ClassParser) {
+	for s, typed := range other.typedImplements {
+		if _, ok := p.typedImplements[s]; !ok {
+			p.typedImplements[s] = map[string]bool{}
+		}
+		for i, implements := range typed {
+			if _, ok := p.typedImplements[s][i]; !ok {
+				p.typedImplements[s][i] = implements
+			}
+		}
+	}
+}
+
+// mergePackage adds the package functions, globals and embeds of the given package of another parser
+func (p *ClassParser) mergePackage(other *ClassParser, pack string) {
+	if functions, ok := other.allFunctions[pack]; ok {
+		p.allFunctions[pack] = functions
+	}
+	if globals, ok := other.allGlobals[pack]; ok {
+		p.allGlobals[pack] = globals
+	}
+	if embeds, ok := other.allEmbeds[pack]; ok {
+		p.allEmbeds[pack] = embeds
+	}
+}
+
+// mergeSet adds the elements of source to target
+func mergeSet(target map[string]struct{}, source map[string]struct{}) {
+	for element := range source {
+		target[element] = struct{}{}
+	}
+}
+
+// mergeRelations adds the relationships of source to target, both being keyed by the source of the relationships
+func mergeRelations(target map[string]map[string]struct{}, source map[string]map[string]struct{}) {
+	for from, targets := range source {
+		if _, ok := target[from]; !ok {
+			target[from] = map[string]struct{}{}
+		}
+		mergeSet(target[from], targets)
+	}
+}
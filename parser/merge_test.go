@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	expected, err := NewClassDiagram([]string{"../testingsupport/subfolder3", "../testingsupport/subfolder2"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder3"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	other, err := NewClassDiagram([]string{"../testingsupport/subfolder2"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	parser.Merge(other)
+	// the same package parsed twice is merged without duplicates
+	parser.Merge(other)
+	if result := parser.Render(); result != expected.Render() {
+		t.Errorf("TestMerge: expected\n%s\ngot\n%s", expected.Render(), result)
+	}
+	if diagnostics := parser.Diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("TestMerge: expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{
+		"a/a.go": "package a\n\ntype Service struct {\n\tName string\n}\n",
+	}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	other, err := NewClassDiagramFromSources(map[string]string{
+		"b/a.go": "package a\n\ntype Service struct {\n\tID int\n}\n\ntype Store interface {\n\tName() string\n}\n",
+	}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	parser.Merge(other)
+	rendered := parser.Render()
+	if !strings.Contains(rendered, "+ Name string") || strings.Contains(rendered, "+ ID int") {
+		t.Errorf("TestMergeConflict: expected the first Service to be kept, got %s", rendered)
+	}
+	if !strings.Contains(rendered, "interface Store") {
+		t.Errorf("TestMergeConflict: expected the Store interface to be merged, got %s", rendered)
+	}
+	expected := "a.Service is declared in both a/a.go:3 and b/a.go:3, keeping the first"
+	if diagnostics := parser.Diagnostics(); len(diagnostics) != 1 || diagnostics[0] != expected {
+		t.Errorf("TestMergeConflict: expected the diagnostic %s, got %v", expected, diagnostics)
+	}
+}
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Metrics holds the size and coupling figures of the parsed code
+type Metrics struct {
+	Packages      int
+	Structs       int
+	Interfaces    int
+	Methods       int
+	Relationships int
+}
+
+// Metrics returns the number of packages, types, methods and relationships (compositions, implementations and
+// public aggregations) found by the parser. Relationships to builtin types are not counted.
+func (p *ClassParser) Metrics() *Metrics {
+	metrics := &Metrics{}
+	for _, structures := range p.structure {
+		if len(structures) > 0 {
+			metrics.Packages++
+		}
+		for _, st := range structures {
+			switch st.Type {
+			case "class":
+				metrics.Structs++
+			case "interface":
+				metrics.Interfaces++
+			}
+			metrics.Methods += len(st.Functions)
+		}
+	}
+	for _, relation := range p.Relations() {
+		switch relation.Kind {
+		case RelationComposition, RelationExtends, RelationAggregation:
+			if !isBuiltinName(relation.Target) {
+				metrics.Relationships++
+			}
+		}
+	}
+	return metrics
+}
+
+// PackageMetrics holds the size and coupling figures of a parsed package
+type PackageMetrics struct {
+	PackageName string `json:"package"`
+	Structs     int    `json:"structs"`
+	Interfaces  int    `json:"interfaces"`
+	Methods     int    `json:"methods"`
+	// AfferentCoupling is the number of the other parsed packages depending on this one
+	AfferentCoupling int `json:"afferentCoupling"`
+	// EfferentCoupling is the number of the other parsed packages this one depends on
+	EfferentCoupling int `json:"efferentCoupling"`
+	// Instability is EfferentCoupling / (AfferentCoupling + EfferentCoupling), from 0 for a package nothing it depends
+	// on can break to 1 for a package nothing depends on
+	Instability float64 `json:"instability"`
+	// Relationships are the numbers of relationships of every kind the structures of the package have with non
+	// builtin types, of the package or not
+	Relationships map[RelationKind]int `json:"relationships"`
+}
+
+// TypeMetrics holds the size of a parsed type, and the number of parsed structures implementing it for an interface
+type TypeMetrics struct {
+	Name            string `json:"name"`
+	Fields          int    `json:"fields"`
+	Methods         int    `json:"methods"`
+	Implementations int    `json:"implementations,omitempty"`
+}
+
+// PackageMetrics returns the size and coupling figures of every parsed package with structures, sorted by package.
+// A package depends on another one when it has a relationship of PackageDependencies with it, or when one of its
+// types or functions uses it in the parameters or return values of a method or function. The afferent and efferent
+// couplings both count the packages reported here only, so the packages that were not parsed are left out of both.
+func (p *ClassParser) PackageMetrics() []*PackageMetrics {
+	packages := p.Packages()
+	dependencies := p.getCouplingDependencies(packages)
+	relationships := p.getPackageRelationshipCounts()
+	afferent := map[string]int{}
+	for _, packDependencies := range dependencies {
+		for dependency := range packDependencies {
+			afferent[dependency]++
+		}
+	}
+	result := []*PackageMetrics{}
+	for _, pack := range packages {
+		metrics := &PackageMetrics{
+			PackageName:      pack,
+			AfferentCoupling: afferent[pack],
+			EfferentCoupling: len(dependencies[pack]),
+			Relationships:    relationships[pack],
+		}
+		for _, st := range p.structure[pack] {
+			switch st.Type {
+			case "class":
+				metrics.Structs++
+			case "interface":
+				metrics.Interfaces++
+			}
+			metrics.Methods += len(st.Functions)
+		}
+		if coupling := metrics.AfferentCoupling + metrics.EfferentCoupling; coupling > 0 {
+			metrics.Instability = float64(metrics.EfferentCoupling) / float64(coupling)
+		}
+		result = append(result, metrics)
+	}
+	return result
+}
+
+// getCouplingDependencies returns, for every one of the given packages, the other ones of them it depends on through
+// a relationship or the signature of a method or function
+func (p *ClassParser) getCouplingDependencies(packages []string) map[string]map[string]struct{} {
+	reported := map[string]struct{}{}
+	for _, pack := range packages {
+		reported[pack] = struct{}{}
+	}
+	dependencies := map[string]map[string]struct{}{}
+	addDependency := func(pack string, dependency string) {
+		if _, ok := reported[dependency]; !ok || dependency == pack {
+			return
+		}
+		dependencies[pack][dependency] = struct{}{}
+	}
+	packageDependencies := p.PackageDependencies()
+	for _, pack := range packages {
+		dependencies[pack] = map[string]struct{}{}
+		for _, dependency := range packageDependencies[pack] {
+			addDependency(pack, dependency)
+		}
+		functions := append([]*Function{}, p.allFunctions[pack]...)
+		for _, st := range p.structure[pack] {
+			functions = append(functions, st.Functions...)
+		}
+		for _, function := range functions {
+			for _, dependency := range getSignaturePackages(function) {
+				addDependency(pack, dependency)
+			}
+		}
+	}
+	return dependencies
+}
+
+// getSignaturePackages returns the packages of the qualified type names (e.g. parser.Struct in []*parser.Struct) used
+// by the parameters and return values of the given function
+func getSignaturePackages(function *Function) []string {
+	types := append([]string{}, function.FullNameReturnValues...)
+	for _, parameter := range function.Parameters {
+		types = append(types, parameter.FullType)
+	}
+	packages := []string{}
+	for _, typeName := range types {
+		for _, name := range typeNameRegexp.FindAllString(typeName, -1) {
+			if split := strings.SplitN(name, ".", 2); len(split) == 2 {
+				packages = append(packages, split[0])
+			}
+		}
+	}
+	return packages
+}
+
+// getPackageRelationshipCounts returns, for every parsed package, the number of relationships of every kind its
+// structures have with non builtin types
+func (p *ClassParser) getPackageRelationshipCounts() map[string]map[RelationKind]int {
+	counts := map[string]map[RelationKind]int{}
+	for pack := range p.structure {
+		counts[pack] = map[RelationKind]int{}
+	}
+	for _, relation := range p.Relations() {
+		if isBuiltinName(relation.Target) {
+			continue
+		}
+		counts[strings.SplitN(relation.Source, ".", 2)[0]][relation.Kind]++
+	}
+	return counts
+}
+
+// LargestTypes returns the given number of parsed structs and interfaces with the most fields and methods, the
+// largest first. The types of the same size are sorted by name.
+func (p *ClassParser) LargestTypes(count int) []*TypeMetrics {
+	implementations := map[string]int{}
+	for _, relation := range p.Relations() {
+		if relation.Kind == RelationExtends {
+			implementations[relation.Target]++
+		}
+	}
+	types := []*TypeMetrics{}
+	for pack, structures := range p.structure {
+		for name, st := range structures {
+			if st.Type != "class" && st.Type != "interface" {
+				continue
+			}
+			fullName := getStructFullName(st, pack, name)
+			metrics := &TypeMetrics{Name: fullName, Fields: len(st.Fields), Methods: len(st.Functions)}
+			if st.Type == "interface" {
+				metrics.Implementations = implementations[fullName]
+			}
+			types = append(types, metrics)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if size, otherSize := types[i].Fields+types[i].Methods, types[j].Fields+types[j].Methods; size != otherSize {
+			return size > otherSize
+		}
+		return types[i].Name < types[j].Name
+	})
+	if count < len(types) {
+		types = types[:count]
+	}
+	return types
+}
+
+// getMetricsLegend returns the lines of the table of the package metrics rendered in the legend of the diagram
+func (p *ClassParser) getMetricsLegend() []string {
+	lines := []string{"|= Package |= Structs |= Interfaces |= Methods |= Ca |= Ce |= Instability |"}
+	for _, metrics := range p.PackageMetrics() {
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d | %d | %d | %d | %.2f |", metrics.PackageName, metrics.Structs, metrics.Interfaces, metrics.Methods, metrics.AfferentCoupling, metrics.EfferentCoupling, metrics.Instability))
+	}
+	return lines
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/connectionlabels"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestMetrics: expected no error but got %s", err.Error())
+		return
+	}
+	expected := &Metrics{
+		Packages:      1,
+		Structs:       1,
+		Interfaces:    1,
+		Methods:       2,
+		Relationships: 3,
+	}
+	if metrics := parser.Metrics(); !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("TestMetrics: expected %+v, got %+v", expected, metrics)
+	}
+}
+
+func TestPackageMetrics(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder3", "../testingsupport/subfolder2"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestPackageMetrics: expected no error but got %s", err.Error())
+	}
+	expected := []*PackageMetrics{
+		{PackageName: "subfolder2", Structs: 1, Methods: 2, EfferentCoupling: 1, Instability: 1, Relationships: map[RelationKind]int{RelationExtends: 1}},
+		{PackageName: "subfolder3", Interfaces: 1, Methods: 1, AfferentCoupling: 1, Relationships: map[RelationKind]int{}},
+	}
+	if metrics := parser.PackageMetrics(); !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("TestPackageMetrics: expected %v, got %v", expected, metrics)
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderMetricsLegend: true})
+	legend := "legend\n|= Package |= Structs |= Interfaces |= Methods |= Ca |= Ce |= Instability |\n| subfolder2 | 1 | 0 | 2 | 0 | 1 | 1.00 |\n| subfolder3 | 0 | 1 | 1 | 1 | 0 | 0.00 |\nend legend\n"
+	if rendered := parser.Render(); !strings.Contains(rendered, legend) {
+		t.Errorf("TestPackageMetrics: expected the render to contain\n%s\ngot\n%s", legend, rendered)
+	}
+}
+
+func TestPackageMetricsCoupling(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{
+		"model/model.go": `package model
+
+type Order struct {
+	ID string
+}
+`,
+		"store/store.go": `package store
+
+import (
+	"io"
+
+	"example.com/model"
+)
+
+type Store struct {
+	w io.Writer
+}
+
+func (s *Store) Save(orders []*model.Order) error {
+	return nil
+}
+`,
+		"api/api.go": `package api
+
+import "example.com/model"
+
+type Handler struct{}
+
+func Decode(data []byte) (model.Order, error) {
+	return model.Order{}, nil
+}
+`,
+	}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("TestPackageMetricsCoupling: expected no error but got %s", err.Error())
+	}
+	coupling := map[string][2]int{}
+	for _, metrics := range parser.PackageMetrics() {
+		coupling[metrics.PackageName] = [2]int{metrics.AfferentCoupling, metrics.EfferentCoupling}
+	}
+	// io is not parsed so it counts in neither coupling, and model is only used in signatures
+	expected := map[string][2]int{"api": {0, 1}, "model": {2, 0}, "store": {0, 1}}
+	if !reflect.DeepEqual(coupling, expected) {
+		t.Errorf("TestPackageMetricsCoupling: expected the couplings %v, got %v", expected, coupling)
+	}
+}
+
+func TestLargestTypes(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder3", "../testingsupport/subfolder2"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestLargestTypes: expected no error but got %s", err.Error())
+	}
+	expected := []*TypeMetrics{
+		{Name: "subfolder2.Subfolder2", Methods: 2},
+		{Name: "subfolder3.SubfolderInterface", Methods: 1, Implementations: 1},
+	}
+	if types := parser.LargestTypes(10); !reflect.DeepEqual(types, expected) {
+		t.Errorf("TestLargestTypes: expected %v, got %v", expected, types)
+	}
+	if types := parser.LargestTypes(1); len(types) != 1 || types[0].Name != "subfolder2.Subfolder2" {
+		t.Errorf("TestLargestTypes: expected the largest type only, got %v", types)
+	}
+}
+
+func TestPackageRelationshipCounts(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/connectionlabels"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestPackageRelationshipCounts: expected no error but got %s", err.Error())
+	}
+	expected := map[RelationKind]int{RelationComposition: 1, RelationExtends: 1, RelationAggregation: 1}
+	if metrics := parser.PackageMetrics(); len(metrics) != 1 || !reflect.DeepEqual(metrics[0].Relationships, expected) {
+		t.Errorf("TestPackageRelationshipCounts: expected %v, got %v", expected, metrics)
+	}
+}
@@ -0,0 +1,351 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// modelVersion is the version of the format written by WriteModel. Models of other versions cannot be read.
+const modelVersion = 1
+
+// model is the parsed structure of a ClassParser as written by WriteModel
+type model struct {
+	Version            int                            `json:"version"`
+	Structure          map[string]map[string]*Struct  `json:"structure"`
+	Interfaces         map[string]struct{}            `json:"interfaces"`
+	Structs            map[string]struct{}            `json:"structs"`
+	Aliases            map[string]*Alias              `json:"aliases"`
+	RenamedStructs     map[string]map[string]string   `json:"renamedStructs"`
+	Externals          map[string]*Struct             `json:"externals"`
+	Globals            map[string][]*GlobalVariable   `json:"globals"`
+	Conversions        map[string]map[string]struct{} `json:"conversions"`
+	Dependencies       map[string]map[string]struct{} `json:"dependencies"`
+	Functions          map[string][]*Function         `json:"functions"`
+	Embeds             map[string][]*EmbeddedAssets   `json:"embeds"`
+	Providers          []*Provider                    `json:"providers"`
+	ContextlessMethods []*ContextlessMethod           `json:"contextlessMethods"`
+	Diagnostics        []string                       `json:"diagnostics"`
+	TypedImplements    map[string]map[string]bool     `json:"typedImplements"`
+}
+
+// modelManifestName is the name of the manifest written by SavePackageModels
+const modelManifestName = "manifest.json"
+
+// modelManifest lists the package models written by SavePackageModels
+type modelManifest struct {
+	Version     int                     `json:"version"`
+	Packages    []*modelManifestPackage `json:"packages"`
+	Providers   []*Provider             `json:"providers"`
+	Diagnostics []string                `json:"diagnostics"`
+}
+
+// modelManifestPackage is a package model of a modelManifest
+type modelManifestPackage struct {
+	Name       string `json:"name"`
+	File       string `json:"file"`
+	Structs    int    `json:"structs"`
+	Interfaces int    `json:"interfaces"`
+}
+
+// WriteModel writes the parsed structure as JSON, to be read later by ReadModel and rendered without parsing the
+// code again. The calls found for sequence diagrams and the parse errors are not written.
+func (p *ClassParser) WriteModel(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p.getModel())
+}
+
+// getModel returns the parsed structure as written by WriteModel
+func (p *ClassParser) getModel() *model {
+	return &model{
+		Version:            modelVersion,
+		Structure:          p.structure,
+		Interfaces:         p.allInterfaces,
+		Structs:            p.allStructs,
+		Aliases:            p.allAliases,
+		RenamedStructs:     p.allRenamedStructs,
+		Externals:          p.allExternals,
+		Globals:            p.allGlobals,
+		Conversions:        p.allConversions,
+		Dependencies:       p.allDependencies,
+		Functions:          p.allFunctions,
+		Embeds:             p.allEmbeds,
+		Providers:          p.allProviders,
+		ContextlessMethods: p.ContextlessMethods(),
+		Diagnostics:        p.diagnostics,
+		TypedImplements:    p.typedImplements,
+	}
+}
+
+// SaveModel writes the parsed structure to the given file (see WriteModel)
+func (p *ClassParser) SaveModel(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteModel(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// SavePackageModels writes the parsed structure to the given directory as one model file per package, each of them
+// readable by ReadModel, and a manifest.json listing them, so large models can be loaded package by package. The
+// files of several packages are merged back with Merge. The dependency injection providers, which span packages, and
+// the diagnostics are only written to the manifest.
+func (p *ClassParser) SavePackageModels(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	manifest := &modelManifest{
+		Version:     modelVersion,
+		Packages:    []*modelManifestPackage{},
+		Providers:   p.allProviders,
+		Diagnostics: p.diagnostics,
+	}
+	files := map[string]struct{}{modelManifestName: {}}
+	for _, pack := range p.getModelPackages() {
+		packageModel := p.getPackageModel(pack)
+		entry := &modelManifestPackage{Name: pack, File: getPackageModelFileName(pack, files)}
+		for _, st := range packageModel.Structure[pack] {
+			switch st.Type {
+			case "class":
+				entry.Structs++
+			case "interface":
+				entry.Interfaces++
+			}
+		}
+		if err := writeJSONFile(filepath.Join(dir, entry.File), packageModel); err != nil {
+			return err
+		}
+		manifest.Packages = append(manifest.Packages, entry)
+	}
+	return writeJSONFile(filepath.Join(dir, modelManifestName), manifest)
+}
+
+// getPackageModelFileName returns the name of the model file of the given package, that is not in files nor differs
+// only by case from one of them, and adds it to files. The manifest name is reserved by adding it to files beforehand.
+func getPackageModelFileName(pack string, files map[string]struct{}) string {
+	base := plantUMLAliasRegexp.ReplaceAllString(pack, "_")
+	name := base + ".json"
+	for i := 2; ; i++ {
+		if _, ok := files[strings.ToLower(name)]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s_%d.json", base, i)
+	}
+	files[strings.ToLower(name)] = struct{}{}
+	return name
+}
+
+// getModelPackages returns the sorted names of the packages with structures, external types or package members
+func (p *ClassParser) getModelPackages() []string {
+	packages := map[string]struct{}{}
+	for pack := range p.structure {
+		packages[pack] = struct{}{}
+	}
+	for name := range p.allExternals {
+		packages[getNamePackage(name)] = struct{}{}
+	}
+	for pack := range p.allFunctions {
+		packages[pack] = struct{}{}
+	}
+	for pack := range p.allGlobals {
+		packages[pack] = struct{}{}
+	}
+	for pack := range p.allEmbeds {
+		packages[pack] = struct{}{}
+	}
+	result := make([]string, 0, len(packages))
+	for pack := range packages {
+		result = append(result, pack)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// getPackageModel returns the part of the parsed structure declared in the given package
+func (p *ClassParser) getPackageModel(pack string) *model {
+	all := p.getModel()
+	m := &model{
+		Version:        modelVersion,
+		Structure:      map[string]map[string]*Struct{pack: p.structure[pack]},
+		Interfaces:     filterPackageSet(all.Interfaces, pack),
+		Structs:        filterPackageSet(all.Structs, pack),
+		Aliases:        map[string]*Alias{},
+		RenamedStructs: map[string]map[string]string{},
+		Externals:      map[string]*Struct{},
+		Globals:        map[string][]*GlobalVariable{},
+		Conversions:    map[string]map[string]struct{}{},
+		Dependencies:   map[string]map[string]struct{}{},
+		Functions:      map[string][]*Function{},
+		Embeds:         map[string][]*EmbeddedAssets{},
+		Providers:      []*Provider{},
+	}
+	for name, alias := range all.Aliases {
+		if alias.PackageName == pack {
+			m.Aliases[name] = alias
+		}
+	}
+	if renamed, ok := all.RenamedStructs[pack]; ok {
+		m.RenamedStructs[pack] = renamed
+	}
+	for name, external := range all.Externals {
+		if getNamePackage(name) == pack {
+			m.Externals[name] = external
+		}
+	}
+	for source, targets := range all.Conversions {
+		if getNamePackage(source) == pack {
+			m.Conversions[source] = targets
+		}
+	}
+	m.TypedImplements = map[string]map[string]bool{}
+	for source, typed := range all.TypedImplements {
+		if getNamePackage(source) == pack {
+			m.TypedImplements[source] = typed
+		}
+	}
+	for source, targets := range all.Dependencies {
+		if getNamePackage(source) == pack {
+			m.Dependencies[source] = targets
+		}
+	}
+	if globals, ok := all.Globals[pack]; ok {
+		m.Globals[pack] = globals
+	}
+	if functions, ok := all.Functions[pack]; ok {
+		m.Functions[pack] = functions
+	}
+	if embeds, ok := all.Embeds[pack]; ok {
+		m.Embeds[pack] = embeds
+	}
+	for _, method := range all.ContextlessMethods {
+		if method.PackageName == pack {
+			m.ContextlessMethods = append(m.ContextlessMethods, method)
+		}
+	}
+	return m
+}
+
+// filterPackageSet returns the names of the given set that belong to the given package
+func filterPackageSet(names map[string]struct{}, pack string) map[string]struct{} {
+	result := map[string]struct{}{}
+	for name := range names {
+		if getNamePackage(name) == pack {
+			result[name] = struct{}{}
+		}
+	}
+	return result
+}
+
+// getNamePackage returns the package of the given package qualified name (e.g. parser for parser.Struct)
+func getNamePackage(name string) string {
+	return strings.SplitN(name, ".", 2)[0]
+}
+
+// writeJSONFile writes the given value as JSON to the given file
+func writeJSONFile(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadModel returns a parser of the structure written by WriteModel, with the default rendering options
+func ReadModel(r io.Reader) (*ClassParser, error) {
+	m := &model{}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, fmt.Errorf("invalid model: %s", err.Error())
+	}
+	if m.Version != modelVersion {
+		return nil, fmt.Errorf("invalid model: version %d is not supported", m.Version)
+	}
+	p := newClassParser(&ClassDiagramOptions{})
+	p.structure = m.Structure
+	p.allInterfaces = m.Interfaces
+	p.allStructs = m.Structs
+	p.allAliases = m.Aliases
+	p.allRenamedStructs = m.RenamedStructs
+	p.allExternals = m.Externals
+	p.allGlobals = m.Globals
+	p.allConversions = m.Conversions
+	p.allDependencies = m.Dependencies
+	p.allFunctions = m.Functions
+	p.allEmbeds = m.Embeds
+	p.allProviders = m.Providers
+	p.diagnostics = m.Diagnostics
+	p.typedImplements = m.TypedImplements
+	p.initNilMaps()
+	for _, method := range m.ContextlessMethods {
+		st := p.structure[method.PackageName][method.StructName]
+		if st == nil {
+			continue
+		}
+		for _, function := range st.Functions {
+			if function.Name == method.Method {
+				p.contextlessMethods[function] = struct{}{}
+			}
+		}
+	}
+	return p, nil
+}
+
+// LoadModel returns a parser of the structure written to the given file by SaveModel (see ReadModel)
+func LoadModel(path string) (*ClassParser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadModel(f)
+}
+
+// initNilMaps replaces the nil maps of a read model, which may be written to, by empty ones
+func (p *ClassParser) initNilMaps() {
+	if p.structure == nil {
+		p.structure = map[string]map[string]*Struct{}
+	}
+	if p.allInterfaces == nil {
+		p.allInterfaces = map[string]struct{}{}
+	}
+	if p.allStructs == nil {
+		p.allStructs = map[string]struct{}{}
+	}
+	if p.allAliases == nil {
+		p.allAliases = map[string]*Alias{}
+	}
+	if p.allRenamedStructs == nil {
+		p.allRenamedStructs = map[string]map[string]string{}
+	}
+	if p.allExternals == nil {
+		p.allExternals = map[string]*Struct{}
+	}
+	if p.allGlobals == nil {
+		p.allGlobals = map[string][]*GlobalVariable{}
+	}
+	if p.allConversions == nil {
+		p.allConversions = map[string]map[string]struct{}{}
+	}
+	if p.allDependencies == nil {
+		p.allDependencies = map[string]map[string]struct{}{}
+	}
+	if p.allFunctions == nil {
+		p.allFunctions = map[string][]*Function{}
+	}
+	if p.allEmbeds == nil {
+		p.allEmbeds = map[string][]*EmbeddedAssets{}
+	}
+	if p.typedImplements == nil {
+		p.typedImplements = map[string]map[string]bool{}
+	}
+}
@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModelRoundTrip(t *testing.T) {
+	for _, directory := range []string{"../testingsupport/contexts", "../testingsupport/typealiases", "../testingsupport/providers"} {
+		parser, err := NewClassDiagram([]string{directory}, []string{}, false)
+		if err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		options := map[RenderingOption]interface{}{
+			RenderAliases:         true,
+			RenderDependencies:    true,
+			RenderContextWarnings: true,
+		}
+		if err := parser.SetRenderingOptions(options); err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		var buffer bytes.Buffer
+		if err := parser.WriteModel(&buffer); err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		loaded, err := ReadModel(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		if err := loaded.SetRenderingOptions(options); err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		if expected, result := parser.Render(), loaded.Render(); expected != result {
+			t.Errorf("TestModelRoundTrip: %s: expected\n%s\ngot\n%s", directory, expected, result)
+		}
+		if expected, result := len(parser.ContextlessMethods()), len(loaded.ContextlessMethods()); expected != result {
+			t.Errorf("TestModelRoundTrip: %s: expected %d contextless methods, got %d", directory, expected, result)
+		}
+	}
+}
+
+func TestMergedModelsRenderLikeTheParsedCode(t *testing.T) {
+	options := map[RenderingOption]interface{}{RenderMetricsLegend: true}
+	parse := func(directories ...string) *ClassParser {
+		parser, err := NewClassDiagram(directories, []string{}, false)
+		if err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		if err := parser.SetRenderingOptions(options); err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		return parser
+	}
+	reload := func(parser *ClassParser) *ClassParser {
+		var buffer bytes.Buffer
+		if err := parser.WriteModel(&buffer); err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		loaded, err := ReadModel(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		return loaded
+	}
+	// testingsupport.test has the unexported method of subfolder.test2, which is another method for the compiler
+	expected := parse("../testingsupport", "../testingsupport/subfolder", "../testingsupport/implementations").Render()
+	for _, split := range [][][]string{
+		{{"../testingsupport", "../testingsupport/subfolder"}, {"../testingsupport/implementations"}},
+		{{"../testingsupport"}, {"../testingsupport/subfolder"}, {"../testingsupport/implementations"}},
+	} {
+		loaded := reload(parse(split[0]...))
+		for _, directories := range split[1:] {
+			loaded.Merge(reload(parse(directories...)))
+		}
+		if err := loaded.SetRenderingOptions(options); err != nil {
+			t.Fatalf("expected no error but got %s", err.Error())
+		}
+		if result := loaded.Render(); result != expected {
+			t.Errorf("TestMergedModelsRenderLikeTheParsedCode %v: expected\n%s\ngot\n%s", split, expected, result)
+		}
+	}
+}
+
+func TestSavePackageModels(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder2", "../testingsupport/subfolder3", "../testingsupport/typealiases"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	options := map[RenderingOption]interface{}{RenderAliases: true}
+	if err := parser.SetRenderingOptions(options); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	dir := t.TempDir()
+	if err := parser.SavePackageModels(dir); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	content, err := os.ReadFile(filepath.Join(dir, modelManifestName))
+	if err != nil {
+		t.Fatalf("expected no error reading the manifest but got %s", err.Error())
+	}
+	manifest := &modelManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		t.Fatalf("expected no error decoding the manifest but got %s", err.Error())
+	}
+	names := []string{}
+	var loaded *ClassParser
+	for _, pack := range manifest.Packages {
+		names = append(names, pack.Name)
+		packageModel, err := LoadModel(filepath.Join(dir, pack.File))
+		if err != nil {
+			t.Fatalf("expected no error loading %s but got %s", pack.File, err.Error())
+		}
+		if packages := packageModel.Packages(); len(packages) > 1 || (len(packages) == 1 && packages[0] != pack.Name) {
+			t.Errorf("TestSavePackageModels: expected %s to hold the package %s only, got %v", pack.File, pack.Name, packages)
+		}
+		if loaded == nil {
+			loaded = packageModel
+		} else {
+			loaded.Merge(packageModel)
+		}
+	}
+	if expected := "subfolder2,subfolder3,typealiases"; strings.Join(names, ",") != expected {
+		t.Errorf("TestSavePackageModels: expected the packages %s, got %v", expected, names)
+	}
+	if err := loaded.SetRenderingOptions(options); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if expected, result := parser.Render(), loaded.Render(); expected != result {
+		t.Errorf("TestSavePackageModels: expected the merged packages to render\n%s\ngot\n%s", expected, result)
+	}
+}
+
+func TestSavePackageModelsFileNames(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{
+		"manifest/manifest.go": "package manifest\n\ntype Entry struct{}\n",
+		"upper/upper.go":       "package Models\n\ntype Upper struct{}\n",
+		"lower/lower.go":       "package models\n\ntype Lower struct{}\n",
+	}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	dir := t.TempDir()
+	if err := parser.SavePackageModels(dir); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	content, err := os.ReadFile(filepath.Join(dir, modelManifestName))
+	if err != nil {
+		t.Fatalf("expected no error reading the manifest but got %s", err.Error())
+	}
+	manifest := &modelManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		t.Fatalf("expected no error decoding the manifest but got %s", err.Error())
+	}
+	files := []string{}
+	for _, pack := range manifest.Packages {
+		files = append(files, pack.Name+":"+pack.File)
+		packageModel, err := LoadModel(filepath.Join(dir, pack.File))
+		if err != nil {
+			t.Fatalf("expected no error loading %s but got %s", pack.File, err.Error())
+		}
+		if packages := packageModel.Packages(); len(packages) != 1 || packages[0] != pack.Name {
+			t.Errorf("TestSavePackageModelsFileNames: expected %s to hold the package %s, got %v", pack.File, pack.Name, packages)
+		}
+	}
+	if expected := "Models:Models.json,manifest:manifest_2.json,models:models_2.json"; strings.Join(files, ",") != expected {
+		t.Errorf("TestSavePackageModelsFileNames: expected the files %s, got %v", expected, files)
+	}
+}
+
+func TestReadInvalidModel(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{
+			Name:     "not json",
+			Input:    "class A",
+			Expected: "invalid model: ",
+		},
+		{
+			Name:     "other version",
+			Input:    `{"version": 2}`,
+			Expected: "invalid model: version 2 is not supported",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := ReadModel(strings.NewReader(tc.Input))
+			if err == nil || !strings.HasPrefix(err.Error(), tc.Expected) {
+				t.Errorf("TestReadInvalidModel: expected %s, got %v", tc.Expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+)
+
+// multiplicityMany is the multiplicity of the types held by slices and maps
+const multiplicityMany = "0..*"
+
+// addMultiplicities records the multiplicity of the types held by a slice, map or array field (e.g. 0..* for
+// []*Seat or 4 for [4]Wheel). The other fields hold a single value and have no multiplicity.
+func (st *Struct) addMultiplicities(field *Field, fieldType ast.Expr, aliases map[string]string) {
+	multiplicity, element := getMultiplicity(fieldType)
+	if multiplicity == "" {
+		return
+	}
+	multiplicities := &st.Multiplicities
+	if !unicode.IsUpper(rune(field.Name[0])) {
+		multiplicities = &st.PrivateMultiplicities
+	}
+	if *multiplicities == nil {
+		*multiplicities = map[string]map[string]struct{}{}
+	}
+	_, elementTypes := getFieldType(element, aliases)
+	for _, t := range elementTypes {
+		t = replacePackageConstant(t, st.PackageName)
+		if _, ok := (*multiplicities)[t]; !ok {
+			(*multiplicities)[t] = map[string]struct{}{}
+		}
+		(*multiplicities)[t][multiplicity] = struct{}{}
+	}
+}
+
+// getMultiplicity returns the multiplicity of the given collection type and the type of its elements, or an empty
+// multiplicity if it is not a collection. The length of an array is its multiplicity.
+func getMultiplicity(fieldType ast.Expr) (string, ast.Expr) {
+	switch t := fieldType.(type) {
+	case *ast.StarExpr:
+		return getMultiplicity(t.X)
+	case *ast.MapType:
+		return multiplicityMany, t.Value
+	case *ast.ArrayType:
+		switch length := t.Len.(type) {
+		case nil:
+			return multiplicityMany, t.Elt
+		case *ast.BasicLit:
+			return length.Value, t.Elt
+		case *ast.Ident:
+			return length.Name, t.Elt
+		}
+		return multiplicityMany, t.Elt
+	}
+	return "", nil
+}
+
+// getMultiplicityLabel returns the multiplicity of the aggregated end of the aggregation of target (e.g. 0..*) or an
+// empty string if it is not held by a collection
+func (p *ClassParser) getMultiplicityLabel(structure *Struct, target string) string {
+	if !p.renderingOptions.Multiplicity {
+		return ""
+	}
+	multiplicities := structure.Multiplicities[target]
+	if p.renderingOptions.AggregatePrivateMembers {
+		multiplicities = mergeSets(multiplicities, structure.PrivateMultiplicities[target])
+	}
+	return strings.Join(getSortedKeys(multiplicities), ", ")
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMultiplicities(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/multiplicity"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestMultiplicities: expected no error but got %s", err.Error())
+	}
+	st := parser.getStruct("multiplicity.Car")
+	expected := map[string]map[string]struct{}{
+		"multiplicity.Wheel":     {"4": {}},
+		"multiplicity.Seat":      {"0..*": {}},
+		"multiplicity.Passenger": {"0..*": {}},
+	}
+	if !reflect.DeepEqual(st.Multiplicities, expected) {
+		t.Errorf("TestMultiplicities: expected %v, got %v", expected, st.Multiplicities)
+	}
+	expected = map[string]map[string]struct{}{"multiplicity.Wheel": {"0..*": {}}}
+	if !reflect.DeepEqual(st.PrivateMultiplicities, expected) {
+		t.Errorf("TestMultiplicities: expected private multiplicities %v, got %v", expected, st.PrivateMultiplicities)
+	}
+}
+
+func TestRenderMultiplicity(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Options  map[RenderingOption]interface{}
+		Expected []string
+	}{
+		{
+			Name:    "disabled",
+			Options: map[RenderingOption]interface{}{RenderAggregations: true},
+			Expected: []string{
+				`"multiplicity.Car" o-- "multiplicity.Seat"`,
+			},
+		},
+		{
+			Name:    "public members",
+			Options: map[RenderingOption]interface{}{RenderAggregations: true, RenderMultiplicity: true},
+			Expected: []string{
+				`"multiplicity.Car" o-- "multiplicity.Engine"`,
+				`"multiplicity.Car" "1" o-- "0..*" "multiplicity.Seat"`,
+				`"multiplicity.Car" "1" o-- "4" "multiplicity.Wheel"`,
+			},
+		},
+		{
+			Name:    "private members",
+			Options: map[RenderingOption]interface{}{RenderAggregations: true, RenderMultiplicity: true, AggregatePrivateMembers: true},
+			Expected: []string{
+				`"multiplicity.Car" "1" o-- "0..*, 4" "multiplicity.Wheel"`,
+			},
+		},
+		{
+			Name:    "qualified associations and connection labels",
+			Options: map[RenderingOption]interface{}{RenderAggregations: true, RenderMultiplicity: true, RenderQualifiedAssociations: true, RenderConnectionLabels: true},
+			Expected: []string{
+				`"multiplicity.Car""uses" o-- "0..* per string" "multiplicity.Passenger"`,
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagram([]string{"../testingsupport/multiplicity"}, []string{}, false)
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			parser.SetRenderingOptions(tc.Options)
+			result := parser.Render()
+			for _, expected := range tc.Expected {
+				if !strings.Contains(result, expected+"\n") {
+					t.Errorf("expected the render to contain %s, got %s", expected, result)
+				}
+			}
+		})
+	}
+}
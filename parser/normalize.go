@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var typeNameRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?`)
+
+// normalizeNames renames every parsed package and type with the given hook. The hook is called with package names
+// (e.g. v2) and with package qualified type names (e.g. v2.Client), for which it must return a package qualified
+// name too. Types can be moved to another package that way.
+func (p *ClassParser) normalizeNames(hook func(string) string) {
+	structure := map[string]map[string]*Struct{}
+	for pack, structures := range p.structure {
+		if _, ok := structure[hook(pack)]; !ok {
+			structure[hook(pack)] = map[string]*Struct{}
+		}
+		for name, st := range structures {
+			fullName := p.normalizeTypeName(getStructFullName(st, pack, name), hook)
+			newPack := strings.SplitN(fullName, ".", 2)[0]
+			if _, ok := structure[newPack]; !ok {
+				structure[newPack] = map[string]*Struct{}
+			}
+			p.normalizeStruct(st, hook)
+			st.PackageName = newPack
+			if st.Type == "alias" || st.Type == "enum" {
+				structure[newPack][fullName] = st
+			} else {
+				structure[newPack][strings.TrimPrefix(fullName, newPack+".")] = st
+			}
+		}
+	}
+	externals := map[string]*Struct{}
+	for fullName, external := range p.allExternals {
+		fullName = p.normalizeTypeName(fullName, hook)
+		p.normalizeStruct(external, hook)
+		external.PackageName = strings.SplitN(fullName, ".", 2)[0]
+		externals[fullName] = external
+	}
+	aliases := map[string]*Alias{}
+	for _, alias := range p.allAliases {
+		if !needsRenaming(alias.Name) {
+			// the other names refer to the structures renamed to be rendered
+			alias.Name = p.normalizeTypeName(alias.Name, hook)
+		}
+		alias.AliasOf = p.normalizeTypeName(alias.AliasOf, hook)
+		alias.PackageName = hook(alias.PackageName)
+		aliases[alias.AliasOf] = alias
+	}
+	renamedStructs := map[string]map[string]string{}
+	for pack, renamed := range p.allRenamedStructs {
+		renamedStructs[hook(pack)] = renamed
+	}
+	typedImplements := map[string]map[string]bool{}
+	for s, interfaces := range p.typedImplements {
+		normalized := map[string]bool{}
+		for i, implements := range interfaces {
+			normalized[p.normalizeTypeName(i, hook)] = implements
+		}
+		typedImplements[p.normalizeTypeName(s, hook)] = normalized
+	}
+	p.typedImplements = typedImplements
+	p.allInterfaces = p.normalizeSet(p.allInterfaces, hook)
+	p.allStructs = p.normalizeSet(p.allStructs, hook)
+	p.normalizePackageMembers(hook)
+	p.normalizeCalls(hook)
+	p.normalizeProviders(hook)
+	p.structure = structure
+	p.allExternals = externals
+	p.allAliases = aliases
+	p.allRenamedStructs = renamedStructs
+}
+
+// normalizePackageMembers renames the package level variables, functions and embedded assets
+func (p *ClassParser) normalizePackageMembers(hook func(string) string) {
+	globals := map[string][]*GlobalVariable{}
+	for pack, variables := range p.allGlobals {
+		for _, global := range variables {
+			global.PackageName = hook(pack)
+			global.Type = p.normalizeType(global.Type, pack, hook)
+			for i, t := range global.FundamentalTypes {
+				global.FundamentalTypes[i] = p.normalizeTypeName(t, hook)
+			}
+		}
+		globals[hook(pack)] = append(globals[hook(pack)], variables...)
+	}
+	p.allGlobals = globals
+	functions := map[string][]*Function{}
+	for pack, packageFunctions := range p.allFunctions {
+		for _, function := range packageFunctions {
+			p.normalizeFunction(function, pack, hook)
+		}
+		functions[hook(pack)] = append(functions[hook(pack)], packageFunctions...)
+	}
+	p.allFunctions = functions
+	embeds := map[string][]*EmbeddedAssets{}
+	for pack, packageEmbeds := range p.allEmbeds {
+		for _, embed := range packageEmbeds {
+			embed.PackageName = hook(pack)
+			for i, user := range embed.Users {
+				embed.Users[i] = p.normalizeTypeName(user, hook)
+			}
+		}
+		embeds[hook(pack)] = append(embeds[hook(pack)], packageEmbeds...)
+	}
+	p.allEmbeds = embeds
+}
+
+// normalizeCalls renames the participants of the recorded calls
+func (p *ClassParser) normalizeCalls(hook func(string) string) {
+	normalizeParticipant := func(participant string) string {
+		if strings.Contains(participant, ".") {
+			return p.normalizeTypeName(participant, hook)
+		}
+		return hook(participant)
+	}
+	calls := map[string]*sequenceFunction{}
+	for key, function := range p.allCalls {
+		name := strings.TrimPrefix(key, function.participant+".")
+		function.participant = normalizeParticipant(function.participant)
+		for _, call := range function.calls {
+			call.participant = normalizeParticipant(call.participant)
+		}
+		calls[fmt.Sprintf("%s.%s", function.participant, name)] = function
+	}
+	p.allCalls = calls
+}
+
+// normalizeProviders renames the provided and required types and the package of the parsed providers
+func (p *ClassParser) normalizeProviders(hook func(string) string) {
+	for _, provider := range p.allProviders {
+		if split := strings.SplitN(provider.Name, ".", 2); len(split) == 2 && p.structure[split[0]] != nil {
+			provider.Name = fmt.Sprintf("%s.%s", hook(split[0]), split[1])
+		}
+		for i, t := range provider.Provides {
+			provider.Provides[i] = p.normalizeType(t, "", hook)
+		}
+		for i, t := range provider.Requires {
+			provider.Requires[i] = p.normalizeType(t, "", hook)
+		}
+	}
+}
+
+// normalizeStruct renames the types referenced by the members and relationships of the given structure. It must
+// be called before its package name is changed.
+func (p *ClassParser) normalizeStruct(st *Struct, hook func(string) string) {
+	for _, field := range st.Fields {
+		field.Type = p.normalizeType(field.Type, st.PackageName, hook)
+		field.FullType = p.normalizeType(field.FullType, st.PackageName, hook)
+	}
+	for _, function := range st.Functions {
+		p.normalizeFunction(function, st.PackageName, hook)
+	}
+	for i, embedded := range st.EmbeddedInterfaces {
+		st.EmbeddedInterfaces[i] = p.normalizeTypeName(embedded, hook)
+	}
+	for _, relationships := range []*map[string]struct{}{&st.Composition, &st.Extends, &st.Aggregations, &st.PrivateAggregations, &st.Conversions, &st.Dependencies} {
+		normalized := map[string]struct{}{}
+		for target := range *relationships {
+			normalized[p.normalizeRelationship(target, st.PackageName, hook)] = struct{}{}
+		}
+		*relationships = normalized
+	}
+	st.Qualifiers = p.normalizeQualifiers(st.Qualifiers, st.PackageName, hook)
+	st.PrivateQualifiers = p.normalizeQualifiers(st.PrivateQualifiers, st.PackageName, hook)
+	for _, multiplicities := range []*map[string]map[string]struct{}{&st.Multiplicities, &st.PrivateMultiplicities} {
+		if *multiplicities == nil {
+			continue
+		}
+		normalized := map[string]map[string]struct{}{}
+		for target, values := range *multiplicities {
+			normalized[p.normalizeRelationship(target, st.PackageName, hook)] = values
+		}
+		*multiplicities = normalized
+	}
+}
+
+// normalizeQualifiers renames the aggregated types and the map key types of the given qualifiers of a structure of pack
+func (p *ClassParser) normalizeQualifiers(qualifiers map[string]map[string]struct{}, pack string, hook func(string) string) map[string]map[string]struct{} {
+	if qualifiers == nil {
+		return nil
+	}
+	normalized := map[string]map[string]struct{}{}
+	for target, keys := range qualifiers {
+		normalizedKeys := map[string]struct{}{}
+		for key := range keys {
+			normalizedKeys[p.normalizeType(key, pack, hook)] = struct{}{}
+		}
+		normalized[p.normalizeRelationship(target, pack, hook)] = normalizedKeys
+	}
+	return normalized
+}
+
+func (p *ClassParser) normalizeFunction(function *Function, pack string, hook func(string) string) {
+	for _, parameter := range function.Parameters {
+		parameter.Type = p.normalizeType(parameter.Type, pack, hook)
+		parameter.FullType = p.normalizeType(parameter.FullType, pack, hook)
+	}
+	for i, returnValue := range function.ReturnValues {
+		function.ReturnValues[i] = p.normalizeType(returnValue, pack, hook)
+	}
+	for i, returnValue := range function.FullNameReturnValues {
+		function.FullNameReturnValues[i] = p.normalizeType(returnValue, pack, hook)
+	}
+	function.PackageName = hook(function.PackageName)
+}
+
+// normalizeRelationship renames the target of a relationship, which is either package qualified or local to pack
+func (p *ClassParser) normalizeRelationship(target string, pack string, hook func(string) string) string {
+	name := strings.TrimPrefix(target, "*")
+	prefix := target[:len(target)-len(name)]
+	if !strings.Contains(name, ".") {
+		if isPrimitiveString(name) {
+			return target
+		}
+		name = pack + "." + name
+	}
+	return prefix + p.normalizeTypeName(name, hook)
+}
+
+// normalizeType renames the types in the rendered type of a field, parameter or return value
+// (e.g. map[string]*pkg.Type). Local types of pack stay unqualified unless they are moved to another package.
+func (p *ClassParser) normalizeType(t string, pack string, hook func(string) string) string {
+	return typeNameRegexp.ReplaceAllStringFunc(t, func(name string) string {
+		if strings.Contains(name, ".") {
+			return p.normalizeTypeName(name, hook)
+		}
+		_, isType := p.structure[pack][name]
+		_, isAlias := p.structure[pack][pack+"."+name]
+		if !isType && !isAlias {
+			return name
+		}
+		normalized := p.normalizeTypeName(pack+"."+name, hook)
+		return strings.TrimPrefix(normalized, hook(pack)+".")
+	})
+}
+
+// normalizeTypeName renames a package qualified type name. Builtin types are left as they are.
+func (p *ClassParser) normalizeTypeName(name string, hook func(string) string) string {
+	if !strings.Contains(name, ".") || isBuiltinName(name) {
+		return name
+	}
+	return hook(name)
+}
+
+func (p *ClassParser) normalizeSet(set map[string]struct{}, hook func(string) string) map[string]struct{} {
+	normalized := map[string]struct{}{}
+	for name := range set {
+		normalized[p.normalizeTypeName(name, hook)] = struct{}{}
+	}
+	return normalized
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestNormalizeName(t *testing.T) {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/underlyingtypes"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		NormalizeName: func(name string) string {
+			if name == "underlyingtypes.MyString" {
+				return "u.Text"
+			}
+			return strings.Replace(name, "underlyingtypes", "u", 1)
+		},
+	})
+	if err != nil {
+		t.Fatalf("TestNormalizeName: expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	for _, name := range []string{"u.Writer", "u.NameWriter", "u.StringWriter"} {
+		if _, ok := structs[name]; !ok {
+			t.Errorf("TestNormalizeName: expected %s to be a structure, got %v", name, structs)
+		}
+	}
+	if _, ok := structs["underlyingtypes.Writer"]; ok {
+		t.Error("TestNormalizeName: expected underlyingtypes.Writer to be renamed")
+	}
+	result := parser.Render()
+	for _, expected := range []string{
+		"namespace u {",
+		"+ Write(s Text) error",
+		"class u.Text << (T, #FF7700) >>",
+		`"u.Writer" <|-- "u.NameWriter"`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("TestNormalizeName: expected the render to contain %s, got %s", expected, result)
+		}
+	}
+	if strings.Contains(result, "underlyingtypes") {
+		t.Errorf("TestNormalizeName: expected every name to be normalized, got %s", result)
+	}
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"path"
+	"sort"
+)
+
+// packagePalette are the background colors given in turn to the packages when RenderPackageColors is used
+var packagePalette = []string{"#DAE8FC", "#D5E8D4", "#FFF2CC", "#F8CECC", "#E1D5E7", "#FFE6CC", "#D0CEE2", "#F5F5F5"}
+
+// getPackageColor returns the background color of the classes of the given package: the color of the first
+// PackageColors pattern (in alphabetical order) matching its name, else the color of the palette given to it when
+// RenderPackageColors is used, else an empty string
+func (p *ClassParser) getPackageColor(pack string) string {
+	patterns := make([]string, 0, len(p.renderingOptions.PackageColors))
+	for pattern := range p.renderingOptions.PackageColors {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, pack); matched {
+			return p.renderingOptions.PackageColors[pattern]
+		}
+	}
+	if !p.renderingOptions.ColorPackages {
+		return ""
+	}
+	packages := make([]string, 0, len(p.structure))
+	for name := range p.structure {
+		packages = append(packages, name)
+	}
+	sort.Strings(packages)
+	return packagePalette[sort.SearchStrings(packages, pack)%len(packagePalette)]
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageColors(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder", "../testingsupport/subfolder2", "../testingsupport/subfolder3"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	tt := []struct {
+		Name     string
+		Options  map[RenderingOption]interface{}
+		Expected []string
+	}{
+		{
+			Name:    "palette",
+			Options: map[RenderingOption]interface{}{RenderPackageColors: true},
+			Expected: []string{
+				"interface TestInterfaceAsField #DAE8FC {",
+				"class Subfolder2 << (S,Aquamarine) >> #D5E8D4 {",
+				"interface SubfolderInterface #FFF2CC {",
+			},
+		},
+		{
+			Name:    "patterns",
+			Options: map[RenderingOption]interface{}{RenderPackageColors: true, PackageColors: map[string]string{"*2": "#FF0000", "subfolder?": "#00FF00"}},
+			Expected: []string{
+				"interface TestInterfaceAsField #DAE8FC {",
+				"class Subfolder2 << (S,Aquamarine) >> #FF0000 {",
+				"interface SubfolderInterface #00FF00 {",
+			},
+		},
+		{
+			Name:    "patterns only",
+			Options: map[RenderingOption]interface{}{RenderPackageColors: false, PackageColors: map[string]string{"*3": "#00FF00"}},
+			Expected: []string{
+				"interface TestInterfaceAsField  {",
+				"interface SubfolderInterface #00FF00 {",
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser.SetRenderingOptions(tc.Options)
+			rendered := parser.Render()
+			for _, expected := range tc.Expected {
+				if !strings.Contains(rendered, expected) {
+					t.Errorf("TestPackageColors: expected the render to contain %s, got\n%s", expected, rendered)
+				}
+			}
+		})
+	}
+}
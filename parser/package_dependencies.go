@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Packages returns the sorted names of the parsed packages that contain at least one structure
+func (p *ClassParser) Packages() []string {
+	packages := []string{}
+	for pack, structures := range p.structure {
+		if len(structures) > 0 {
+			packages = append(packages, pack)
+		}
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// defaultPackageRelationKinds are the kinds of the relationships between types that make the dependencies between
+// their packages
+var defaultPackageRelationKinds = []RelationKind{RelationComposition, RelationExtends, RelationAggregation, RelationPrivateAggregation}
+
+// PackageDependencies returns, for every parsed package, the sorted names of the other packages its structures
+// have a relationship with (composition, implementation or aggregation, including private ones). Packages are
+// identified by name, so the dependencies may include packages that were not parsed.
+func (p *ClassParser) PackageDependencies() map[string][]string {
+	result := map[string][]string{}
+	for pack, packDependencies := range p.getPackageDependencyCounts(defaultPackageRelationKinds) {
+		result[pack] = getSortedDependencies(packDependencies)
+	}
+	return result
+}
+
+// getPackageDependencyCounts returns, for every parsed package, the number of relationships of the given kinds its
+// structures have with the types of every other package
+func (p *ClassParser) getPackageDependencyCounts(kinds []RelationKind) map[string]map[string]int {
+	counted := map[RelationKind]struct{}{}
+	for _, kind := range kinds {
+		counted[kind] = struct{}{}
+	}
+	dependencies := map[string]map[string]int{}
+	for pack := range p.structure {
+		dependencies[pack] = map[string]int{}
+	}
+	for _, relation := range p.Relations() {
+		if _, ok := counted[relation.Kind]; !ok {
+			continue
+		}
+		pack := strings.SplitN(relation.Source, ".", 2)[0]
+		target := strings.SplitN(relation.Target, ".", 2)[0]
+		if target != pack && target != builtinPackageName {
+			dependencies[pack][target]++
+		}
+	}
+	return dependencies
+}
+
+// getSortedDependencies returns the sorted names of the packages of the given counts
+func getSortedDependencies(counts map[string]int) []string {
+	dependencies := make([]string, 0, len(counts))
+	for dependency := range counts {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+// setPackageRelationKinds sets the kinds of the relationships making the relationships between packages of RenderC4
+func (p *ClassParser) setPackageRelationKinds(kinds []RelationKind) error {
+	for _, kind := range kinds {
+		if !isRelationKind(kind) {
+			return fmt.Errorf("Invalid relation kind %s", kind)
+		}
+	}
+	p.renderingOptions.PackageRelationKinds = kinds
+	return nil
+}
+
+// isRelationKind returns true if the given kind is one of RelationKinds
+func isRelationKind(kind RelationKind) bool {
+	for _, relationKind := range RelationKinds {
+		if kind == relationKind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPackageDependencies(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder3", "../testingsupport/subfolder2"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestPackageDependencies: expected no error but got %s", err.Error())
+		return
+	}
+	packages := parser.Packages()
+	if !reflect.DeepEqual(packages, []string{"subfolder2", "subfolder3"}) {
+		t.Errorf("TestPackageDependencies: expected packages [subfolder2 subfolder3], got %v", packages)
+	}
+	expected := map[string][]string{
+		"subfolder2": {"subfolder3"},
+		"subfolder3": {},
+	}
+	if dependencies := parser.PackageDependencies(); !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("TestPackageDependencies: expected %v, got %v", expected, dependencies)
+	}
+}
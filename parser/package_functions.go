@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"unicode"
+)
+
+const packageFunctionsName = "functions"
+
+// addPackageFunction registers a function declared without a receiver so it can be rendered in the functions class
+// of its package. init functions are ignored since they cannot be called.
+func (p *ClassParser) addPackageFunction(decl *ast.FuncDecl) {
+	if decl.Name.Name == "init" || decl.Name.Name == "_" {
+		return
+	}
+	function := getFunction(decl.Type, decl.Name.Name, p.allImports, p.currentPackageName)
+	p.allFunctions[p.currentPackageName] = append(p.allFunctions[p.currentPackageName], function)
+}
+
+// getPackageFunctions returns a synthetic structure holding the free functions and the exported variables of the
+// given package. nil is returned when the package has none of them or they are not to be rendered.
+func (p *ClassParser) getPackageFunctions(pack string) *Struct {
+	if !p.renderingOptions.PackageFunctions {
+		return nil
+	}
+	fields := make([]*Field, 0)
+	for _, global := range p.allGlobals[pack] {
+		if unicode.IsUpper(rune(global.Name[0])) {
+			fields = append(fields, &Field{Name: global.Name, Type: global.Type})
+		}
+	}
+	if len(fields) == 0 && len(p.allFunctions[pack]) == 0 {
+		return nil
+	}
+	return &Struct{
+		PackageName: pack,
+		Functions:   p.allFunctions[pack],
+		Fields:      fields,
+		Type:        packageFunctionsName,
+	}
+}
+
+func (p *ClassParser) renderPackageFunctions(pack string, str *LineStringBuilder) {
+	functions := p.getPackageFunctions(pack)
+	if functions == nil {
+		return
+	}
+	str.WriteLineWithDepth(1, fmt.Sprintf(`class %s <<%s>> {`, packageFunctionsName, packageFunctionsName))
+	privateFields := &LineStringBuilder{}
+	publicFields := &LineStringBuilder{}
+	privateMethods := &LineStringBuilder{}
+	publicMethods := &LineStringBuilder{}
+	p.renderStructFields(functions, privateFields, publicFields)
+	p.renderStructMethods(functions, privateMethods, publicMethods)
+	for _, members := range []*LineStringBuilder{privateFields, publicFields, privateMethods, publicMethods} {
+		if members.Len() > 0 {
+			str.WriteLineWithDepth(0, members.String())
+		}
+	}
+	str.WriteLineWithDepth(1, "}")
+}
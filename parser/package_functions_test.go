@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestAddPackageFunction(t *testing.T) {
+	parser := getEmptyParser("main")
+	parser.allFunctions = map[string][]*Function{}
+	for _, name := range []string{"New", "init", "helper"} {
+		parser.addPackageFunction(&ast.FuncDecl{
+			Name: &ast.Ident{Name: name},
+			Type: &ast.FuncType{Params: &ast.FieldList{}},
+		})
+	}
+	functions := parser.allFunctions["main"]
+	if len(functions) != 2 || functions[0].Name != "New" || functions[1].Name != "helper" {
+		t.Errorf("TestAddPackageFunction: expected [New helper], got %v", functions)
+	}
+}
+
+func TestRenderPackageFunctions(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/singletons"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestRenderPackageFunctions: expected no error but got %s", err.Error())
+		return
+	}
+	if parser.getPackageFunctions("singletons") != nil {
+		t.Errorf("TestRenderPackageFunctions: expected no functions class when the option is disabled")
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderPackageFunctions: true,
+	})
+	str := &LineStringBuilder{}
+	parser.renderPackageFunctions("singletons", str)
+	expectedResult := `    class functions <<functions>> {
+        + DefaultRegistry *Registry
+
+    }
+`
+	if str.String() != expectedResult {
+		t.Errorf("TestRenderPackageFunctions: expecting \n%s\n got \n%s\n", expectedResult, str.String())
+	}
+}
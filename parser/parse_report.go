@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ParseError is an error found while parsing a file. The files with syntax errors are skipped, and the files whose
+// source is not handled by the parser are only parsed up to the declaration where it failed.
+type ParseError struct {
+	File string
+	Err  error
+}
+
+// Error returns the error prefixed by the file, unless it is a syntax error already giving its position
+func (e *ParseError) Error() string {
+	if _, ok := e.Err.(scanner.ErrorList); ok {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Err.Error())
+}
+
+// ParseReport holds the errors found while parsing, sorted by file. It is an error itself so it can be returned as
+// one when there are errors.
+type ParseReport struct {
+	Errors []*ParseError
+}
+
+// HasErrors returns true if any file could not be parsed completely
+func (r *ParseReport) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// Error returns the errors of the report, one per line
+func (r *ParseReport) Error() string {
+	lines := make([]string, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Errors returns the report of the files that could not be parsed completely. The diagram is generated from the rest
+// of the parsed code.
+func (p *ClassParser) Errors() *ParseReport {
+	report := &ParseReport{Errors: append([]*ParseError{}, p.parseErrors...)}
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].File < report.Errors[j].File
+	})
+	return report
+}
+
+func (p *ClassParser) addParseError(file string, err error) {
+	p.parseErrors = append(p.parseErrors, &ParseError{File: file, Err: err})
+}
+
+// recoverParseError reports the panic of the parsing of the given file or directory, if any, as a parse error. It
+// must be deferred.
+func (p *ClassParser) recoverParseError(path string) {
+	if r := recover(); r != nil {
+		p.addParseError(path, fmt.Errorf("unexpected source: %v", r))
+	}
+}
+
+// parseDirectoryFiles parses the go files of the given directory matching the build constraints grouped by package,
+// like parser.ParseDir, except that every file with syntax errors is reported and skipped instead of only the first.
+// The files are read from the file system of the parser.
+func (p *ClassParser) parseDirectoryFiles(ctx context.Context, directoryPath string) (map[string]*ast.Package, error) {
+	infos, err := afero.ReadDir(p.fileSystem, directoryPath)
+	if err != nil {
+		return nil, err
+	}
+	filter := p.getBuildConstraintsFilter(directoryPath)
+	packages := map[string]*ast.Package{}
+	for _, info := range infos {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		if filter != nil && !filter(info) {
+			continue
+		}
+		fileName := filepath.Join(directoryPath, info.Name())
+		content, err := afero.ReadFile(p.fileSystem, fileName)
+		if err != nil {
+			p.addParseError(fileName, err)
+			continue
+		}
+		file, err := parser.ParseFile(p.fileSet, fileName, content, parser.ParseComments)
+		if err != nil {
+			p.addParseError(fileName, err)
+			continue
+		}
+		pack, ok := packages[file.Name.Name]
+		if !ok {
+			pack = &ast.Package{Name: file.Name.Name, Files: map[string]*ast.File{}}
+			packages[file.Name.Name] = pack
+		}
+		pack.Files[fileName] = file
+	}
+	return packages, nil
+}
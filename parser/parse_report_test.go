@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestParseErrors(t *testing.T) {
+	directory := t.TempDir()
+	files := map[string]string{
+		"valid.go":   "package broken\n\ntype Valid struct{}\n",
+		"broken.go":  "package broken\n\ntype Broken struct {\n",
+		"broken2.go": "package broken\n\nfunc (\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatalf("TestParseErrors: expected no error writing %s but got %s", name, err.Error())
+		}
+	}
+	parser, err := NewClassDiagram([]string{directory}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestParseErrors: expected no error but got %s", err.Error())
+	}
+	if _, ok := parser.Structs()["broken.Valid"]; !ok {
+		t.Errorf("TestParseErrors: expected broken.Valid to be parsed, got %v", parser.Structs())
+	}
+	report := parser.Errors()
+	if !report.HasErrors() || len(report.Errors) != 2 {
+		t.Fatalf("TestParseErrors: expected 2 errors, got %v", report.Errors)
+	}
+	for i, name := range []string{"broken.go", "broken2.go"} {
+		if report.Errors[i].File != filepath.Join(directory, name) {
+			t.Errorf("TestParseErrors: expected an error for %s, got %s", name, report.Errors[i].File)
+		}
+		if !strings.HasPrefix(report.Errors[i].Error(), filepath.Join(directory, name)+":") {
+			t.Errorf("TestParseErrors: expected the error to start with the file, got %s", report.Errors[i].Error())
+		}
+	}
+	if lines := strings.Split(report.Error(), "\n"); len(lines) != 2 {
+		t.Errorf("TestParseErrors: expected one line per error, got %s", report.Error())
+	}
+}
+
+func TestStrictParseErrors(t *testing.T) {
+	directory := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(directory, "broken.go"), []byte("package broken\n\ntype Broken struct {\n"), 0644); err != nil {
+		t.Fatalf("TestStrictParseErrors: expected no error writing broken.go but got %s", err.Error())
+	}
+	for _, strict := range []bool{false, true} {
+		_, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+			FileSystem:         afero.NewOsFs(),
+			Directories:        []string{directory},
+			IgnoredDirectories: []string{},
+			RenderingOptions:   map[RenderingOption]interface{}{},
+			Strict:             strict,
+		})
+		if !strict && err != nil {
+			t.Errorf("TestStrictParseErrors: expected no error when lenient but got %s", err.Error())
+		}
+		if report, ok := err.(*ParseReport); strict && (!ok || len(report.Errors) != 1) {
+			t.Errorf("TestStrictParseErrors: expected the parse report as error when strict, got %v", err)
+		}
+	}
+}
+
+func TestNoParseErrors(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestNoParseErrors: expected no error but got %s", err.Error())
+	}
+	if report := parser.Errors(); report.HasErrors() {
+		t.Errorf("TestNoParseErrors: expected no errors, got %s", report.Error())
+	}
+}
+
+func TestRecoverParseError(t *testing.T) {
+	parser := getEmptyParser("main")
+	func() {
+		defer parser.recoverParseError("main.go")
+		panic(errors.New("index out of range"))
+	}()
+	report := parser.Errors()
+	if len(report.Errors) != 1 || report.Errors[0].Error() != "main.go: unexpected source: index out of range" {
+		t.Errorf("TestRecoverParseError: expected the panic to be reported, got %v", report.Errors)
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getLine returns the line of the given position in the parsed files, 0 if it is unknown
+func (p *ClassParser) getLine(pos token.Pos) int {
+	if p.fileSet == nil || !pos.IsValid() {
+		return 0
+	}
+	return p.fileSet.Position(pos).Line
+}
+
+// setLastFieldLine sets the line of the field added last to the given structure, if it was added by AddField
+func (p *ClassParser) setLastFieldLine(st *Struct, fields int, pos token.Pos) {
+	if len(st.Fields) > fields {
+		st.Fields[len(st.Fields)-1].Line = p.getLine(pos)
+	}
+}
+
+// setLastMethodPosition sets the file and line of the method added last to the given structure, if it was added by
+// AddMethod
+func (p *ClassParser) setLastMethodPosition(st *Struct, methods int, pos token.Pos) {
+	if len(st.Functions) > methods {
+		st.Functions[len(st.Functions)-1].FileName = p.parsingFileName
+		st.Functions[len(st.Functions)-1].Line = p.getLine(pos)
+	}
+}
+
+// getSourceLink returns the PlantUML link to the declaration of the given structure when its position is known: the
+// LinkTemplate with its position when there is one, else [[file:line]] when RenderSourceLinks is used. It returns an
+// empty string otherwise.
+func (p *ClassParser) getSourceLink(structure *Struct) string {
+	if structure.FileName == "" {
+		return ""
+	}
+	if p.renderingOptions.LinkTemplate != "" {
+		return fmt.Sprintf("[[%s]]", getLinkURL(p.renderingOptions.LinkTemplate, p.renderingOptions.LinkRoot, structure.FileName, structure.Line))
+	}
+	if !p.renderingOptions.SourceLinks {
+		return ""
+	}
+	return fmt.Sprintf("[[%s:%d]]", structure.FileName, structure.Line)
+}
+
+// getLinkURL returns the given template with {path} replaced by the path of the file relative to root, with / as
+// separator, and {line} by the line
+func getLinkURL(template string, root string, fileName string, line int) string {
+	relative := fileName
+	if root != "" {
+		if rel, err := filepath.Rel(root, fileName); err == nil {
+			relative = rel
+		}
+	}
+	return strings.NewReplacer("{path}", filepath.ToSlash(relative), "{line}", strconv.Itoa(line)).Replace(template)
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSourcePositions(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/testfiles"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	client := structs["testfiles.Client"]
+	if filepath.Base(client.FileName) != "client.go" || client.Line != 9 {
+		t.Errorf("TestSourcePositions: expected Client at client.go:9, got %s:%d", client.FileName, client.Line)
+	}
+	if client.Fields[0].Line != 10 {
+		t.Errorf("TestSourcePositions: expected the URL field at line 10, got %d", client.Fields[0].Line)
+	}
+	if method := client.Functions[0]; filepath.Base(method.FileName) != "client.go" || method.Line != 14 {
+		t.Errorf("TestSourcePositions: expected the Get method at client.go:14, got %s:%d", method.FileName, method.Line)
+	}
+	if method := structs["testfiles.Getter"].Functions[0]; method.Line != 5 {
+		t.Errorf("TestSourcePositions: expected the Get interface method at line 5, got %d", method.Line)
+	}
+	if rendered := parser.Render(); strings.Contains(rendered, "[[") {
+		t.Errorf("TestSourcePositions: expected no links by default, got %s", rendered)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderSourceLinks: true}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	expected := "class Client << (S,Aquamarine) >> [[" + client.FileName + ":9]] {"
+	if rendered := parser.Render(); !strings.Contains(rendered, expected) {
+		t.Errorf("TestSourcePositions: expected %s in %s", expected, rendered)
+	}
+}
+
+func TestGetLinkURL(t *testing.T) {
+	template := "https://github.com/org/repo/blob/main/{path}#L{line}"
+	root := filepath.Join("home", "repo")
+	fileName := filepath.Join(root, "parser", "struct.go")
+	expected := "https://github.com/org/repo/blob/main/parser/struct.go#L12"
+	if url := getLinkURL(template, root, fileName, 12); url != expected {
+		t.Errorf("TestGetLinkURL: expected %s, got %s", expected, url)
+	}
+	if url := getLinkURL("{path}:{line}", "", "struct.go", 3); url != "struct.go:3" {
+		t.Errorf("TestGetLinkURL: expected struct.go:3 without root, got %s", url)
+	}
+}
+
+func TestLinkTemplate(t *testing.T) {
+	parser := getEmptyParser("main")
+	st := &Struct{PackageName: "main", FileName: filepath.Join("repo", "main", "main.go"), Line: 7}
+	if link := parser.getSourceLink(st); link != "" {
+		t.Errorf("TestLinkTemplate: expected no link by default, got %s", link)
+	}
+	err := parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		LinkTemplate: "https://example.com/{path}#L{line}",
+		LinkRoot:     "repo",
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if link := parser.getSourceLink(st); link != "[[https://example.com/main/main.go#L7]]" {
+		t.Errorf("TestLinkTemplate: unexpected link %s", link)
+	}
+	if link := parser.getSourceLink(&Struct{PackageName: "main"}); link != "" {
+		t.Errorf("TestLinkTemplate: expected no link without position, got %s", link)
+	}
+}
@@ -0,0 +1,285 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	wirePath = "github.com/google/wire"
+	fxPath   = "go.uber.org/fx"
+)
+
+// dependencyInjectionPackages maps the import paths of the recognized dependency injection packages to the one of the
+// package they are, wire or fx. Tests register their stubs in it.
+var dependencyInjectionPackages = map[string]string{
+	wirePath: wirePath,
+	fxPath:   fxPath,
+}
+
+// Provider is a constructor registered in a dependency injection container: a function given to wire.NewSet,
+// wire.Build or fx.Provide, a wire.Bind or a value given to wire.Value or fx.Supply. The parameters of a wire injector
+// (the function calling wire.Build) are provided by it and its results are required. Types are rendered with their
+// package name (e.g. *parser.Struct). Parameter and result objects (fx.In and fx.Out) are not expanded.
+type Provider struct {
+	Name     string
+	Provides []string
+	Requires []string
+	// Injector is true for the wire injectors
+	Injector bool
+}
+
+// MissingProvider is a type required by some providers that no parsed provider provides
+type MissingProvider struct {
+	Type       string
+	RequiredBy []string
+}
+
+// addProviders records the providers registered with wire or fx in the given files of the package pack
+func (p *ClassParser) addProviders(info *types.Info, pack string, files []*ast.File) {
+	for _, file := range files {
+		imports := getDependencyInjectionImports(file)
+		if len(imports) == 0 {
+			continue
+		}
+		for _, d := range file.Decls {
+			decl, _ := d.(*ast.FuncDecl)
+			ast.Inspect(d, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				p.allProviders = append(p.allProviders, p.getProviders(info, pack, imports, call, decl)...)
+				return true
+			})
+		}
+	}
+}
+
+// getDependencyInjectionImports returns the import paths of wire and fx keyed by the name they are imported with
+func getDependencyInjectionImports(file *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || dependencyInjectionPackages[path] == "" {
+			continue
+		}
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = dependencyInjectionPackages[path]
+	}
+	return imports
+}
+
+// getProviders returns the providers registered by the given call, which is made in the function decl or at the
+// package level if decl is nil
+func (p *ClassParser) getProviders(info *types.Info, pack string, imports map[string]string, call *ast.CallExpr, decl *ast.FuncDecl) []*Provider {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	function := fmt.Sprintf("%s.%s", imports[ident.Name], selector.Sel.Name)
+	providers := []*Provider{}
+	switch function {
+	case wirePath + ".Build":
+		if decl != nil {
+			if injector := p.getFunctionProvider(info, pack, decl.Name); injector != nil {
+				providers = append(providers, &Provider{Name: injector.Name, Provides: injector.Requires, Requires: injector.Provides, Injector: true})
+			}
+		}
+		fallthrough
+	case wirePath + ".NewSet", fxPath + ".Provide":
+		for _, arg := range call.Args {
+			if provider := p.getFunctionProvider(info, pack, arg); provider != nil {
+				providers = append(providers, provider)
+			}
+		}
+	case wirePath + ".Bind":
+		if len(call.Args) == 2 {
+			providers = append(providers, &Provider{
+				Name:     "wire.Bind",
+				Provides: getTypeNames(getNewType(info, call.Args[0])),
+				Requires: getTypeNames(getNewType(info, call.Args[1])),
+			})
+		}
+	case wirePath + ".Value", fxPath + ".Supply":
+		for _, arg := range call.Args {
+			providers = append(providers, &Provider{Name: function[strings.LastIndex(function, "/")+1:], Provides: getTypeNames(info.TypeOf(arg))})
+		}
+	}
+	return providers
+}
+
+// getFunctionProvider returns the provider of the given function, which requires its parameters and provides its
+// results but the errors and cleanup functions, or nil if expr is not a function
+func (p *ClassParser) getFunctionProvider(info *types.Info, pack string, expr ast.Expr) *Provider {
+	signature, ok := info.TypeOf(expr).(*types.Signature)
+	if !ok {
+		return nil
+	}
+	name := types.ExprString(expr)
+	if _, ok := expr.(*ast.FuncLit); ok {
+		position := p.fileSet.Position(expr.Pos())
+		name = fmt.Sprintf("func literal (%s:%d)", filepath.Base(position.Filename), position.Line)
+	} else if !strings.Contains(name, ".") {
+		name = fmt.Sprintf("%s.%s", pack, name)
+	}
+	provider := &Provider{Name: name, Provides: []string{}, Requires: []string{}}
+	for i := 0; i < signature.Params().Len(); i++ {
+		t := signature.Params().At(i).Type()
+		if !isDependencyInjectionBuiltin(t) {
+			provider.Requires = append(provider.Requires, getTypeNames(t)...)
+		}
+	}
+	for i := 0; i < signature.Results().Len(); i++ {
+		t := signature.Results().At(i).Type()
+		if !isErrorOrCleanup(t) {
+			provider.Provides = append(provider.Provides, getTypeNames(t)...)
+		}
+	}
+	return provider
+}
+
+// getNewType returns the type T of new(T)
+func getNewType(info *types.Info, expr ast.Expr) types.Type {
+	if pointer, ok := info.TypeOf(expr).(*types.Pointer); ok {
+		return pointer.Elem()
+	}
+	return nil
+}
+
+// getTypeNames returns the given type with its package name, or no name if it could not be resolved
+func getTypeNames(t types.Type) []string {
+	if t == nil {
+		return []string{}
+	}
+	name := types.TypeString(t, func(pack *types.Package) string {
+		return pack.Name()
+	})
+	if strings.Contains(name, "invalid type") {
+		return []string{}
+	}
+	return []string{name}
+}
+
+// isDependencyInjectionBuiltin returns true for the types provided by the containers themselves (e.g. fx.Lifecycle)
+// and the types that could not be resolved, typically because they are declared in an unavailable package
+func isDependencyInjectionBuiltin(t types.Type) bool {
+	if len(getTypeNames(t)) == 0 {
+		return true
+	}
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && dependencyInjectionPackages[named.Obj().Pkg().Path()] == fxPath
+}
+
+// isErrorOrCleanup returns true for the error and the func() results of a provider
+func isErrorOrCleanup(t types.Type) bool {
+	if types.Identical(t, types.Universe.Lookup("error").Type()) {
+		return true
+	}
+	signature, ok := t.(*types.Signature)
+	return ok && signature.Params().Len() == 0 && signature.Results().Len() == 0
+}
+
+// Providers returns the parsed dependency injection providers sorted by name. They are only found when
+// ClassDiagramOptions.FindProviders is set.
+func (p *ClassParser) Providers() []*Provider {
+	providers := append([]*Provider{}, p.allProviders...)
+	sort.SliceStable(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
+
+// MissingProviders returns the types required by the parsed providers that none of them provides, sorted by type
+func (p *ClassParser) MissingProviders() []*MissingProvider {
+	provided := map[string]struct{}{}
+	for _, provider := range p.allProviders {
+		for _, t := range provider.Provides {
+			provided[t] = struct{}{}
+		}
+	}
+	requiredBy := map[string]map[string]struct{}{}
+	for _, provider := range p.allProviders {
+		for _, t := range provider.Requires {
+			if _, ok := provided[t]; ok {
+				continue
+			}
+			if _, ok := requiredBy[t]; !ok {
+				requiredBy[t] = map[string]struct{}{}
+			}
+			requiredBy[t][provider.Name] = struct{}{}
+		}
+	}
+	missing := []*MissingProvider{}
+	for _, t := range getSortedKeys(mapKeysToSet(requiredBy)) {
+		missing = append(missing, &MissingProvider{Type: t, RequiredBy: getSortedKeys(requiredBy[t])})
+	}
+	return missing
+}
+
+// RenderProviders returns a PlantUML diagram of the dependency injection graph. The provided types are connected to
+// the types their providers require and the types without provider are rendered in red. The wire injectors are
+// rendered as the providers of their parameters.
+func (p *ClassParser) RenderProviders() string {
+	providers := map[string]map[string]struct{}{}
+	requires := map[string]map[string]struct{}{}
+	for _, provider := range p.allProviders {
+		for _, t := range provider.Provides {
+			if _, ok := providers[t]; !ok {
+				providers[t] = map[string]struct{}{}
+				requires[t] = map[string]struct{}{}
+			}
+			providers[t][provider.Name] = struct{}{}
+			if provider.Injector {
+				continue
+			}
+			for _, required := range provider.Requires {
+				requires[t][required] = struct{}{}
+			}
+		}
+	}
+	str := &LineStringBuilder{}
+	str.WriteLineWithDepth(0, "@startuml")
+	str.WriteLineWithDepth(0, "title dependency injection graph")
+	for _, t := range getSortedKeys(mapKeysToSet(providers)) {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`class "%s" as %s << (P,LightBlue) >> {`, t, getPlantUMLAlias(t)))
+		for _, name := range getSortedKeys(providers[t]) {
+			str.WriteLineWithDepth(1, name)
+		}
+		str.WriteLineWithDepth(0, "}")
+	}
+	for _, missing := range p.MissingProviders() {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`class "%s" as %s << (M,Red) missing >> #Pink`, missing.Type, getPlantUMLAlias(missing.Type)))
+	}
+	for _, t := range getSortedKeys(mapKeysToSet(requires)) {
+		for _, required := range getSortedKeys(requires[t]) {
+			str.WriteLineWithDepth(0, fmt.Sprintf(`%s --> %s`, getPlantUMLAlias(t), getPlantUMLAlias(required)))
+		}
+	}
+	str.WriteLineWithDepth(0, "hide empty members")
+	str.WriteLineWithDepth(0, "@enduml")
+	return str.String()
+}
+
+func mapKeysToSet(m map[string]map[string]struct{}) map[string]struct{} {
+	set := map[string]struct{}{}
+	for k := range m {
+		set[k] = struct{}{}
+	}
+	return set
+}
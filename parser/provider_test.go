@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func getProvidersParser(t *testing.T, findProviders bool) *ClassParser {
+	stubs := map[string]string{
+		"github.com/jfeliu007/goplantuml/testingsupport/providers/wire": wirePath,
+		"github.com/jfeliu007/goplantuml/testingsupport/providers/fx":   fxPath,
+	}
+	for stub, path := range stubs {
+		dependencyInjectionPackages[stub] = path
+	}
+	t.Cleanup(func() {
+		for stub := range stubs {
+			delete(dependencyInjectionPackages, stub)
+		}
+	})
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/providers"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		FindProviders:      findProviders,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	return parser
+}
+
+func TestProviders(t *testing.T) {
+	expected := []*Provider{
+		{Name: "func literal (providers.go:67)", Provides: []string{"string"}, Requires: []string{"*providers.Service"}},
+		{Name: "providers.InitializeService", Provides: []string{"providers.Config"}, Requires: []string{"*providers.Service"}, Injector: true},
+		{Name: "providers.NewRepository", Provides: []string{"*providers.Repository"}, Requires: []string{"providers.Config"}},
+		{Name: "providers.NewSMTPMailer", Provides: []string{"*providers.SMTPMailer"}, Requires: []string{}},
+		{Name: "providers.NewService", Provides: []string{"*providers.Service"}, Requires: []string{"*providers.Repository", "providers.Mailer", "providers.Clock"}},
+		{Name: "providers.NewService", Provides: []string{"*providers.Service"}, Requires: []string{"*providers.Repository", "providers.Mailer", "providers.Clock"}},
+		{Name: "wire.Bind", Provides: []string{"providers.Mailer"}, Requires: []string{"*providers.SMTPMailer"}},
+	}
+	if providers := getProvidersParser(t, true).Providers(); !reflect.DeepEqual(providers, expected) {
+		t.Errorf("TestProviders: expected %v, got %v", expected, providers)
+	}
+	if providers := getProvidersParser(t, false).Providers(); len(providers) != 0 {
+		t.Errorf("TestProviders: expected no providers without FindProviders, got %v", providers)
+	}
+}
+
+func TestMissingProviders(t *testing.T) {
+	expected := []*MissingProvider{
+		{Type: "providers.Clock", RequiredBy: []string{"providers.NewService"}},
+	}
+	if missing := getProvidersParser(t, true).MissingProviders(); !reflect.DeepEqual(missing, expected) {
+		t.Errorf("TestMissingProviders: expected %v, got %v", expected, missing)
+	}
+}
+
+func TestRenderProviders(t *testing.T) {
+	rendered := getProvidersParser(t, true).RenderProviders()
+	expected := []string{
+		"class \"*providers.Service\" as _providers_Service << (P,LightBlue) >> {\n    providers.NewService\n}",
+		`class "providers.Clock" as providers_Clock << (M,Red) missing >> #Pink`,
+		`_providers_Service --> providers_Clock`,
+		`providers_Mailer --> _providers_SMTPMailer`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(rendered, e) {
+			t.Errorf("TestRenderProviders: expected the render to contain %s, got\n%s", e, rendered)
+		}
+	}
+	if strings.Contains(rendered, "providers_Config --> ") {
+		t.Errorf("TestRenderProviders: expected the injector requirements not to be connected to its parameters, got\n%s", rendered)
+	}
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+)
+
+// addQualifiers records the key type of a map field (e.g. UserID for map[UserID]*Session) as a qualifier of the
+// aggregations of the types of its values
+func (st *Struct) addQualifiers(field *Field, fieldType ast.Expr, aliases map[string]string) {
+	mapType, ok := fieldType.(*ast.MapType)
+	if !ok {
+		return
+	}
+	key, _ := getFieldType(mapType.Key, aliases)
+	key = replacePackageConstant(key, "")
+	_, valueTypes := getFieldType(mapType.Value, aliases)
+	qualifiers := &st.Qualifiers
+	if !unicode.IsUpper(rune(field.Name[0])) {
+		qualifiers = &st.PrivateQualifiers
+	}
+	if *qualifiers == nil {
+		*qualifiers = map[string]map[string]struct{}{}
+	}
+	for _, t := range valueTypes {
+		t = replacePackageConstant(t, st.PackageName)
+		if _, ok := (*qualifiers)[t]; !ok {
+			(*qualifiers)[t] = map[string]struct{}{}
+		}
+		(*qualifiers)[t][key] = struct{}{}
+	}
+}
+
+// getQualifierLabel returns the label of the aggregated end of the aggregation of target (e.g. per UserID) or an empty
+// string if it is not qualified
+func (p *ClassParser) getQualifierLabel(structure *Struct, target string) string {
+	if !p.renderingOptions.QualifiedAssociations {
+		return ""
+	}
+	keys := structure.Qualifiers[target]
+	if p.renderingOptions.AggregatePrivateMembers {
+		keys = mergeSets(keys, structure.PrivateQualifiers[target])
+	}
+	return getQualifierText(keys)
+}
+
+// getQualifierText returns the qualifier of the given map key types (e.g. per UserID) or an empty string if there are
+// none
+func getQualifierText(keys map[string]struct{}) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("per %s", strings.Join(getSortedKeys(keys), ", "))
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQualifiers(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/qualifiedassociations"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("TestQualifiers: expected no error but got %s", err.Error())
+	}
+	st := parser.getStruct("qualifiedassociations.Store")
+	expected := map[string]map[string]struct{}{"qualifiedassociations.Session": {"UserID": {}}}
+	if !reflect.DeepEqual(st.Qualifiers, expected) {
+		t.Errorf("TestQualifiers: expected %v, got %v", expected, st.Qualifiers)
+	}
+	expected = map[string]map[string]struct{}{"qualifiedassociations.Session": {"string": {}}}
+	if !reflect.DeepEqual(st.PrivateQualifiers, expected) {
+		t.Errorf("TestQualifiers: expected private qualifiers %v, got %v", expected, st.PrivateQualifiers)
+	}
+}
+
+func TestRenderQualifiedAssociations(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Options  map[RenderingOption]interface{}
+		Expected string
+	}{
+		{
+			Name:     "disabled",
+			Options:  map[RenderingOption]interface{}{RenderAggregations: true},
+			Expected: `"qualifiedassociations.Store" o-- "qualifiedassociations.Session"`,
+		},
+		{
+			Name:     "public members",
+			Options:  map[RenderingOption]interface{}{RenderAggregations: true, RenderQualifiedAssociations: true},
+			Expected: `"qualifiedassociations.Store" o-- "per UserID" "qualifiedassociations.Session"`,
+		},
+		{
+			Name:     "private members",
+			Options:  map[RenderingOption]interface{}{RenderAggregations: true, RenderQualifiedAssociations: true, AggregatePrivateMembers: true},
+			Expected: `"qualifiedassociations.Store" o-- "per UserID, string" "qualifiedassociations.Session"`,
+		},
+		{
+			Name:     "connection labels",
+			Options:  map[RenderingOption]interface{}{RenderAggregations: true, RenderQualifiedAssociations: true, RenderConnectionLabels: true},
+			Expected: `"qualifiedassociations.Store""uses" o-- "per UserID" "qualifiedassociations.Session"`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagram([]string{"../testingsupport/qualifiedassociations"}, []string{}, false)
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			parser.SetRenderingOptions(tc.Options)
+			if result := parser.Render(); !strings.Contains(result, tc.Expected+"\n") {
+				t.Errorf("expected the render to contain %s, got %s", tc.Expected, result)
+			}
+		})
+	}
+}
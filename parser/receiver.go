@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"go/ast"
+)
+
+// setLastMethodReceiver records whether the method added last to the given structure, if it was added by AddMethod,
+// is declared on a pointer receiver
+func setLastMethodReceiver(st *Struct, methods int, receiver ast.Expr) {
+	if len(st.Functions) > methods {
+		_, pointer := receiver.(*ast.StarExpr)
+		st.Functions[len(st.Functions)-1].PointerReceiver = pointer
+	}
+}
+
+// getReceiverAnnotation returns the annotation rendered next to the given method when it is declared on a pointer
+// receiver. The methods declared on value receivers, which are in the method set of both the value and the pointer,
+// are not annotated.
+func (p *ClassParser) getReceiverAnnotation(method *Function) string {
+	if !p.renderingOptions.Receivers || !method.PointerReceiver {
+		return ""
+	}
+	return "{ptr}"
+}
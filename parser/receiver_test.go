@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const receiversSource = `package server
+
+type Server struct{}
+
+func (s *Server) Start() error {
+	return nil
+}
+
+func (s Server) Name() string {
+	return ""
+}
+
+type Starter interface {
+	Start() error
+}
+`
+
+func TestPointerReceiver(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"server/server.go": receiversSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	structs := parser.Structs()
+	expected := map[string]bool{
+		"server.Server.Start":  true,
+		"server.Server.Name":   false,
+		"server.Starter.Start": false,
+	}
+	for name, pointer := range expected {
+		split := strings.Split(name, ".")
+		st := structs[split[0]+"."+split[1]]
+		found := false
+		for _, method := range st.Functions {
+			if method.Name == split[2] {
+				found = true
+				if method.PointerReceiver != pointer {
+					t.Errorf("TestPointerReceiver: expected the pointer receiver of %s to be %t", name, pointer)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("TestPointerReceiver: expected the method %s", name)
+		}
+	}
+}
+
+func TestRenderReceivers(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"server/server.go": receiversSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if rendered := parser.Render(); strings.Contains(rendered, "{ptr}") {
+		t.Errorf("TestRenderReceivers: expected no annotation by default, got %s", rendered)
+	}
+	if err := parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderReceivers: true}); err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{"+ Start() error {ptr}\n", "+ Name() string\n"} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestRenderReceivers: expected %q in %s", expected, rendered)
+		}
+	}
+	if strings.Count(rendered, "{ptr}") != 1 {
+		t.Errorf("TestRenderReceivers: expected the interface method not to be annotated, got %s", rendered)
+	}
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RelationKind is the kind of a relationship between two types
+type RelationKind string
+
+const (
+	// RelationComposition is the relationship of a structure with the types it embeds
+	RelationComposition RelationKind = "composition"
+	// RelationExtends is the relationship of a structure with the interfaces it implements or embeds
+	RelationExtends RelationKind = "extends"
+	// RelationAggregation is the relationship of a structure with the types of its exported fields
+	RelationAggregation RelationKind = "aggregation"
+	// RelationPrivateAggregation is the relationship of a structure with the types of its unexported fields
+	RelationPrivateAggregation RelationKind = "private aggregation"
+	// RelationConversion is the relationship of a structure with the types it is explicitly converted to
+	RelationConversion RelationKind = "conversion"
+	// RelationDependency is the relationship of a structure with the types used in its methods. They are only found
+	// when ClassDiagramOptions.FindDependencies is set.
+	RelationDependency RelationKind = "dependency"
+)
+
+// RelationKinds are the kinds of the relationships between types, in the order they are listed
+var RelationKinds = []RelationKind{RelationComposition, RelationExtends, RelationAggregation, RelationPrivateAggregation, RelationConversion, RelationDependency}
+
+// Relation is a relationship between two types. Source and Target are package qualified names, the builtin types
+// belonging to the __builtin__ package (e.g. __builtin__.string).
+type Relation struct {
+	Source string
+	Target string
+	Kind   RelationKind
+	// Label is the qualifier of the aggregations of the values of map fields (e.g. per UserID)
+	Label string
+	// Multiplicity is the multiplicity of the aggregations of slice, map and array fields (e.g. 0..*)
+	Multiplicity string
+}
+
+// Relations returns the relationships of the parsed structures sorted by source, kind and target. A relationship
+// is listed once even when a structure refers to its target by both its local and its package qualified name. The
+// relationships are derived on every call from the sets of the structures (Composition, Extends, Aggregations, ...),
+// which remain the stored model the renderers draw from, so changing the result does not change the diagrams.
+func (p *ClassParser) Relations() []*Relation {
+	relations := []*Relation{}
+	for pack, structures := range p.structure {
+		for name, st := range structures {
+			relations = append(relations, getStructRelations(st, getStructFullName(st, pack, name))...)
+		}
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		if relations[i].Source != relations[j].Source {
+			return relations[i].Source < relations[j].Source
+		}
+		if relations[i].Kind != relations[j].Kind {
+			return relations[i].Kind < relations[j].Kind
+		}
+		return relations[i].Target < relations[j].Target
+	})
+	return relations
+}
+
+// getStructRelations returns the relationships of the given structure, named source
+func getStructRelations(st *Struct, source string) []*Relation {
+	relationships := []struct {
+		kind           RelationKind
+		targets        map[string]struct{}
+		qualifiers     map[string]map[string]struct{}
+		multiplicities map[string]map[string]struct{}
+	}{
+		{kind: RelationComposition, targets: st.Composition},
+		{kind: RelationExtends, targets: st.Extends},
+		{kind: RelationAggregation, targets: st.Aggregations, qualifiers: st.Qualifiers, multiplicities: st.Multiplicities},
+		{kind: RelationPrivateAggregation, targets: st.PrivateAggregations, qualifiers: st.PrivateQualifiers, multiplicities: st.PrivateMultiplicities},
+		{kind: RelationConversion, targets: st.Conversions},
+		{kind: RelationDependency, targets: st.Dependencies},
+	}
+	relations := []*Relation{}
+	for _, relationship := range relationships {
+		added := map[string]struct{}{}
+		for _, target := range getSortedKeys(relationship.targets) {
+			qualified := qualifyRelationTarget(target, st.PackageName)
+			if _, ok := added[qualified]; ok {
+				continue
+			}
+			added[qualified] = struct{}{}
+			relations = append(relations, &Relation{
+				Source:       source,
+				Target:       qualified,
+				Kind:         relationship.kind,
+				Label:        getQualifierText(relationship.qualifiers[target]),
+				Multiplicity: strings.Join(getSortedKeys(relationship.multiplicities[target]), ", "),
+			})
+		}
+	}
+	return relations
+}
+
+// qualifyRelationTarget returns the package qualified name of the given target of a relationship of a structure of
+// pack. The targets in the same package may be stored with or without their package name.
+func qualifyRelationTarget(target string, pack string) string {
+	target = strings.TrimPrefix(target, "*")
+	switch {
+	case strings.Contains(target, "."):
+		return target
+	case isPrimitiveString(target):
+		return fmt.Sprintf("%s.%s", builtinPackageName, target)
+	}
+	return fmt.Sprintf("%s.%s", pack, target)
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelations(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/qualifiedassociations"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	expected := []*Relation{
+		{Source: "qualifiedassociations.Session", Target: "qualifiedassociations.UserID", Kind: RelationAggregation},
+		{Source: "qualifiedassociations.Store", Target: "qualifiedassociations.Session", Kind: RelationAggregation, Label: "per UserID", Multiplicity: "0..*"},
+		{Source: "qualifiedassociations.Store", Target: "qualifiedassociations.UserID", Kind: RelationAggregation},
+		{Source: "qualifiedassociations.Store", Target: "qualifiedassociations.Session", Kind: RelationPrivateAggregation, Label: "per string", Multiplicity: "0..*"},
+	}
+	if relations := parser.Relations(); !reflect.DeepEqual(relations, expected) {
+		t.Errorf("TestRelations: expected %v, got %v", expected, relations)
+	}
+}
+
+func TestGetStructRelations(t *testing.T) {
+	st := &Struct{
+		PackageName: "main",
+		Composition: map[string]struct{}{"Base": {}, "main.Base": {}, "*io.Reader": {}},
+		Extends:     map[string]struct{}{"main.Interface": {}},
+	}
+	expected := []*Relation{
+		{Source: "main.Struct", Target: "io.Reader", Kind: RelationComposition},
+		{Source: "main.Struct", Target: "main.Base", Kind: RelationComposition},
+		{Source: "main.Struct", Target: "main.Interface", Kind: RelationExtends},
+	}
+	if relations := getStructRelations(st, "main.Struct"); !reflect.DeepEqual(relations, expected) {
+		t.Errorf("TestGetStructRelations: expected %v, got %v", expected, relations)
+	}
+}
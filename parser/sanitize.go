@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// plantUMLIdentifierRegexp matches the names PlantUML parses without quotes
+var plantUMLIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var plantUMLAliasRegexp = regexp.MustCompile(`\W`)
+
+// isPlantUMLName returns true when every dot separated part of the given name is a PlantUML identifier, so it can
+// be rendered as is (e.g. parser.ClassParser, but not map[string]interface{}, Pair[K, V] or a hyphenated directory)
+func isPlantUMLName(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if !plantUMLIdentifierRegexp.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// needsRenaming returns true when the given package qualified name is not a package followed by a PlantUML
+// identifier (e.g. a map, a function, a generic instantiation or a type of another package). Such names break the
+// namespaces, so they are rendered as a class whose alias is generateRenamedStructName of the name.
+func needsRenaming(fullName string) bool {
+	split := strings.SplitN(fullName, ".", 2)
+	return len(split) == 2 && !plantUMLIdentifierRegexp.MatchString(split[1])
+}
+
+// renamedStructPrefix starts the aliases of the classes of the names that need renaming, so they do not collide with
+// the parsed types
+const renamedStructPrefix = "__"
+
+// generateRenamedStructName returns a PlantUML identifier for the given name, ignoring its PlantUML formatting (e.g.
+// __mapKV for <font color=blue>map</font>[K]V). It starts with renamedStructPrefix, the ASCII letters and digits are
+// kept, the other letters and digits are replaced by their code point (e.g. U00E9 for é) and anything else is
+// dropped.
+func generateRenamedStructName(currentName string) string {
+	renamed := &strings.Builder{}
+	renamed.WriteString(renamedStructPrefix)
+	for _, r := range markupRegexp.ReplaceAllString(currentName, "") {
+		switch {
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			renamed.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			renamed.WriteString(fmt.Sprintf("U%04X", r))
+		}
+	}
+	return renamed.String()
+}
+
+// getRenamedStructNames returns the aliases of the classes of the names of the given package that need renaming,
+// keyed by name. The names generating the same identifier, or the one of a parsed type, get a numeric suffix in
+// alphabetical order.
+func (p *ClassParser) getRenamedStructNames(pack string) map[string]string {
+	taken := map[string]struct{}{}
+	for name := range p.structure[pack] {
+		taken[strings.TrimPrefix(name, pack+".")] = struct{}{}
+	}
+	names := map[string]struct{}{}
+	for _, name := range p.allRenamedStructs[pack] {
+		names[name] = struct{}{}
+	}
+	result := map[string]string{}
+	for _, name := range getSortedKeys(names) {
+		renamed := generateRenamedStructName(name)
+		unique := renamed
+		for i := 2; ; i++ {
+			if _, ok := taken[unique]; !ok {
+				break
+			}
+			unique = fmt.Sprintf("%s_%d", renamed, i)
+		}
+		taken[unique] = struct{}{}
+		result[name] = unique
+	}
+	return result
+}
+
+// getPlantUMLAlias returns an identifier of the given name (e.g. a type) usable in the PlantUML relationships
+func getPlantUMLAlias(t string) string {
+	return plantUMLAliasRegexp.ReplaceAllString(t, "_")
+}
+
+// quoteName returns the given name between double quotes, to be used as the label of a class or a package. The
+// double quotes of the name are replaced by single quotes since PlantUML has no escape for them.
+func quoteName(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `'`))
+}
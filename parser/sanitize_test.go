@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsPlantUMLName(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "parser", expected: true},
+		{name: "parser.ClassParser", expected: true},
+		{name: "external.http.Handler", expected: true},
+		{name: "my-module", expected: false},
+		{name: "parser.map[string]interface{}", expected: false},
+		{name: "parser.Pair[K, V]", expected: false},
+		{name: "parser.~Temp", expected: false},
+		{name: "", expected: false},
+	}
+	for _, tc := range tt {
+		if result := isPlantUMLName(tc.name); result != tc.expected {
+			t.Errorf("TestIsPlantUMLName: expected %t for %s, got %t", tc.expected, tc.name, result)
+		}
+	}
+}
+
+func TestNeedsRenaming(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "parser.ClassParser", expected: false},
+		{name: "parser.http.Handler", expected: true},
+		{name: "parser.map[string]interface{}", expected: true},
+		{name: "parser.Pair[string, int]", expected: true},
+		{name: "ClassParser", expected: false},
+	}
+	for _, tc := range tt {
+		if result := needsRenaming(tc.name); result != tc.expected {
+			t.Errorf("TestNeedsRenaming: expected %t for %s, got %t", tc.expected, tc.name, result)
+		}
+	}
+}
+
+func TestGenerateRenamedStructNameSanitizes(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected string
+	}{
+		{name: "map[string]interface{}", expected: "__mapstringinterface"},
+		{name: "<font color=blue>map</font>[K]V", expected: "__mapKV"},
+		{name: "Pair[string, int]", expected: "__Pairstringint"},
+		{name: "café", expected: "__cafU00E9"},
+		{name: "[]int", expected: "__int"},
+		{name: "[2]int", expected: "__2int"},
+		{name: "~", expected: "__"},
+	}
+	for _, tc := range tt {
+		if result := generateRenamedStructName(tc.name); result != tc.expected {
+			t.Errorf("TestGenerateRenamedStructNameSanitizes: expected %s for %s, got %s", tc.expected, tc.name, result)
+		}
+	}
+}
+
+func TestRenamedStructNamesDoNotCollide(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"sessions/sessions.go": `package sessions
+
+type Session struct {
+	ID string
+}
+
+type __Session struct{}
+
+type Ptr = *Session
+
+type Sessions []Session
+`}, &ClassDiagramOptions{RenderingOptions: map[RenderingOption]interface{}{RenderAliases: true}})
+	if err != nil {
+		t.Fatalf("TestRenamedStructNamesDoNotCollide: expected no error but got %s", err.Error())
+	}
+	expected := map[string]string{"*Session": "__Session_2", "[]Session": "__Session_3"}
+	if result := parser.getRenamedStructNames("sessions"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("TestRenamedStructNamesDoNotCollide: expected %v, got %v", expected, result)
+	}
+	rendered := parser.Render()
+	for _, line := range []string{
+		`class "*Session" as __Session_2 {`,
+		`class "[]Session" as __Session_3 {`,
+		`"sessions.Ptr" ..> "sessions.__Session_2"`,
+		`"sessions.__Session_3" #.. "sessions.Sessions"`,
+	} {
+		if !strings.Contains(rendered, line) {
+			t.Errorf("TestRenamedStructNamesDoNotCollide: expected the render to contain %s, got\n%s", line, rendered)
+		}
+	}
+	if strings.Contains(rendered, " as Session ") {
+		t.Errorf("TestRenamedStructNamesDoNotCollide: expected no class to be renamed as the struct Session, got\n%s", rendered)
+	}
+}
+
+func TestQuoteName(t *testing.T) {
+	if result := quoteName(`struct{ A int "json:\"a\"" }`); result != `"struct{ A int 'json:\'a\'' }"` {
+		t.Errorf("TestQuoteName: got %s", result)
+	}
+}
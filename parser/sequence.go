@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// sequenceCall is a call to a function or method. The participant is the package qualified name of the receiver type
+// of a method (e.g. parser.ClassParser) or the package of a function (e.g. parser).
+type sequenceCall struct {
+	participant string
+	name        string
+}
+
+func (c *sequenceCall) key() string {
+	return fmt.Sprintf("%s.%s", c.participant, c.name)
+}
+
+// sequenceFunction is a parsed function or method with the calls it makes, in the order they are made
+type sequenceFunction struct {
+	participant string
+	calls       []*sequenceCall
+}
+
+// addCalls records the calls made by the functions and methods of the given files (see RenderSequence). The calls
+// whose participant is not parsed (e.g. fmt) are dropped by resolveCalls.
+func (p *ClassParser) addCalls(info *types.Info, files []*ast.File) {
+	for _, file := range files {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+			function, ok := info.Defs[decl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			declared := newSequenceCall(function)
+			if declared == nil {
+				continue
+			}
+			p.allCalls[declared.key()] = &sequenceFunction{
+				participant: declared.participant,
+				calls:       getCalls(info, decl.Body),
+			}
+		}
+	}
+}
+
+// getCalls returns the calls made in the given body. The arguments and receivers of a call are evaluated, and
+// therefore listed, before it.
+func getCalls(info *types.Info, body *ast.BlockStmt) []*sequenceCall {
+	calls := []*sequenceCall{}
+	var inspect func(node ast.Node) bool
+	inspect = func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var ident *ast.Ident
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			ident = fun
+		case *ast.SelectorExpr:
+			ast.Inspect(fun.X, inspect)
+			ident = fun.Sel
+		default:
+			ast.Inspect(call.Fun, inspect)
+		}
+		for _, arg := range call.Args {
+			ast.Inspect(arg, inspect)
+		}
+		if function, ok := info.Uses[ident].(*types.Func); ok {
+			if called := newSequenceCall(function); called != nil {
+				calls = append(calls, called)
+			}
+		}
+		return false
+	}
+	ast.Inspect(body, inspect)
+	return calls
+}
+
+// newSequenceCall returns the call to the given function or method, or nil if it has no package (e.g. error.Error)
+func newSequenceCall(function *types.Func) *sequenceCall {
+	signature, ok := function.Type().(*types.Signature)
+	if !ok {
+		return nil
+	}
+	if signature.Recv() != nil {
+		participant := getNamedTypeName(signature.Recv().Type())
+		if participant == "" {
+			return nil
+		}
+		return &sequenceCall{participant: participant, name: function.Name()}
+	}
+	if function.Pkg() == nil {
+		return nil
+	}
+	return &sequenceCall{participant: function.Pkg().Name(), name: function.Name()}
+}
+
+// resolveCalls keeps the recorded calls whose participant is a parsed type or package
+func (p *ClassParser) resolveCalls() {
+	for _, function := range p.allCalls {
+		calls := []*sequenceCall{}
+		for _, call := range function.calls {
+			if p.isParsedParticipant(call.participant) {
+				calls = append(calls, call)
+			}
+		}
+		function.calls = calls
+	}
+}
+
+func (p *ClassParser) isParsedParticipant(participant string) bool {
+	if !strings.Contains(participant, ".") {
+		_, ok := p.structure[participant]
+		return ok
+	}
+	return p.getStructByFullName(participant) != nil
+}
+
+// RenderSequence returns a PlantUML sequence diagram of the calls made by the given function or method (e.g.
+// parser.ClassParser.Render or parser.NewClassDiagram) between the parsed types and packages, following the calls up
+// to the given depth. The calls are only recorded when ClassDiagramOptions.FindCalls is set. Recursive calls are not
+// followed.
+func (p *ClassParser) RenderSequence(entrypoint string, depth int) (string, error) {
+	function, ok := p.allCalls[entrypoint]
+	if !ok {
+		return "", fmt.Errorf("function or method %s not found", entrypoint)
+	}
+	if depth < 1 {
+		return "", fmt.Errorf("the depth of the sequence must be at least 1, got %d", depth)
+	}
+	str := &LineStringBuilder{}
+	str.WriteLineWithDepth(0, "@startuml")
+	str.WriteLineWithDepth(0, fmt.Sprintf("title %s", entrypoint))
+	p.renderSequenceCalls(function, depth, map[string]struct{}{entrypoint: {}}, str)
+	str.WriteLineWithDepth(0, "@enduml")
+	return str.String(), nil
+}
+
+// renderSequenceCalls writes the calls of the given function, activating the callees whose own calls are rendered.
+// The functions in stack are being rendered and are not followed again.
+func (p *ClassParser) renderSequenceCalls(function *sequenceFunction, depth int, stack map[string]struct{}, str *LineStringBuilder) {
+	for _, call := range function.calls {
+		str.WriteLineWithDepth(0, fmt.Sprintf(`"%s" -> "%s" : %s`, function.participant, call.participant, call.name))
+		callee, ok := p.allCalls[call.key()]
+		if _, recursive := stack[call.key()]; recursive || !ok || depth == 1 || len(callee.calls) == 0 {
+			continue
+		}
+		stack[call.key()] = struct{}{}
+		str.WriteLineWithDepth(0, fmt.Sprintf(`activate "%s"`, call.participant))
+		p.renderSequenceCalls(callee, depth-1, stack, str)
+		str.WriteLineWithDepth(0, fmt.Sprintf(`deactivate "%s"`, call.participant))
+		delete(stack, call.key())
+	}
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func getSequenceParser(t *testing.T, normalizeName func(string) string) *ClassParser {
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/sequence"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+		FindCalls:          true,
+		NormalizeName:      normalizeName,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	return parser
+}
+
+func TestRenderSequence(t *testing.T) {
+	tt := []struct {
+		Name       string
+		Entrypoint string
+		Depth      int
+		Expected   string
+	}{
+		{
+			Name:       "nested calls",
+			Entrypoint: "sequence.Handler.Handle",
+			Depth:      3,
+			Expected: `@startuml
+title sequence.Handler.Handle
+"sequence.Handler" -> "sequence.Service" : Register
+activate "sequence.Service"
+"sequence.Service" -> "sequence" : validate
+"sequence.Service" -> "sequence" : normalize
+"sequence.Service" -> "sequence.Repository" : Save
+"sequence.Service" -> "sequence.Mailer" : Send
+deactivate "sequence.Service"
+@enduml
+`,
+		},
+		{
+			Name:       "limited depth",
+			Entrypoint: "sequence.Handler.Handle",
+			Depth:      1,
+			Expected: `@startuml
+title sequence.Handler.Handle
+"sequence.Handler" -> "sequence.Service" : Register
+@enduml
+`,
+		},
+		{
+			Name:       "recursive calls",
+			Entrypoint: "sequence.Tree.Walk",
+			Depth:      5,
+			Expected: `@startuml
+title sequence.Tree.Walk
+"sequence.Tree" -> "sequence.Tree" : Walk
+@enduml
+`,
+		},
+		{
+			Name:       "function",
+			Entrypoint: "sequence.validate",
+			Depth:      2,
+			Expected: `@startuml
+title sequence.validate
+@enduml
+`,
+		},
+	}
+	parser := getSequenceParser(t, nil)
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			rendered, err := parser.RenderSequence(tc.Entrypoint, tc.Depth)
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			if rendered != tc.Expected {
+				t.Errorf("TestRenderSequence: expected\n%s\ngot\n%s", tc.Expected, rendered)
+			}
+		})
+	}
+}
+
+func TestRenderSequenceErrors(t *testing.T) {
+	parser := getSequenceParser(t, nil)
+	if _, err := parser.RenderSequence("sequence.Handler.Missing", 3); err == nil {
+		t.Error("TestRenderSequenceErrors: expected an error for an unknown entrypoint")
+	}
+	if _, err := parser.RenderSequence("sequence.Handler.Handle", 0); err == nil {
+		t.Error("TestRenderSequenceErrors: expected an error for a depth of 0")
+	}
+	parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        []string{"../testingsupport/sequence"},
+		IgnoredDirectories: []string{},
+		RenderingOptions:   map[RenderingOption]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if _, err := parser.RenderSequence("sequence.Handler.Handle", 3); err == nil {
+		t.Error("TestRenderSequenceErrors: expected an error when the calls are not recorded")
+	}
+}
+
+func TestRenderSequenceNormalizedNames(t *testing.T) {
+	parser := getSequenceParser(t, func(name string) string {
+		return strings.Replace(name, "sequence", "seq", 1)
+	})
+	rendered, err := parser.RenderSequence("seq.Handler.Handle", 2)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	if !strings.Contains(rendered, `"seq.Service" -> "seq" : validate`) {
+		t.Errorf("TestRenderSequenceNormalizedNames: expected the normalized participants, got\n%s", rendered)
+	}
+}
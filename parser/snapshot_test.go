@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const snapshotsDirectory = "../testingsupport/snapshots"
+
+var updateSnapshots = flag.Bool("update", false, "update the renderer snapshots in testingsupport/snapshots")
+
+// snapshotRenderers are the renderers compared against the golden files in testingsupport/snapshots. New renderers
+// must be added here so their output stays in lockstep with the parser.
+var snapshotRenderers = []struct {
+	extension string
+	render    func(*ClassParser) string
+}{
+	{extension: "puml", render: (*ClassParser).Render},
+	{extension: "dot", render: (*ClassParser).RenderDot},
+	{extension: "c4.puml", render: (*ClassParser).RenderC4},
+}
+
+// snapshotRenderingOptions enables everything that is disabled by default so the snapshots cover as much of the
+// renderers as possible
+var snapshotRenderingOptions = map[RenderingOption]interface{}{
+	RenderTitle:                 "Snapshot",
+	RenderAggregations:          true,
+	RenderConnectionLabels:      true,
+	AggregatePrivateMembers:     true,
+	RenderPrivateMembers:        true,
+	RenderSingletons:            true,
+	RenderFieldTags:             true,
+	RenderBuiltinNotes:          true,
+	RenderDocComments:           true,
+	RenderConversions:           true,
+	RenderPackageFunctions:      true,
+	RenderEmbeddedAssets:        true,
+	RenderContextWarnings:       true,
+	RenderQualifiedAssociations: true,
+}
+
+// getSnapshotFixtures returns the directories of testingsupport that contain go files
+func getSnapshotFixtures(t *testing.T) []string {
+	fixtures := []string{"../testingsupport"}
+	entries, err := ioutil.ReadDir("../testingsupport")
+	if err != nil {
+		t.Fatalf("getSnapshotFixtures: expected no error reading testingsupport, got %s", err.Error())
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		directory := filepath.Join("../testingsupport", entry.Name())
+		if goFiles, _ := filepath.Glob(filepath.Join(directory, "*.go")); len(goFiles) > 0 {
+			fixtures = append(fixtures, directory)
+		}
+	}
+	return fixtures
+}
+
+func TestRendererSnapshots(t *testing.T) {
+	for _, fixture := range getSnapshotFixtures(t) {
+		parser, err := NewClassDiagram([]string{fixture}, []string{}, false)
+		if err != nil {
+			t.Errorf("TestRendererSnapshots: expected no error parsing %s, got %s", fixture, err.Error())
+			continue
+		}
+		parser.SetRenderingOptions(snapshotRenderingOptions)
+		for _, renderer := range snapshotRenderers {
+			snapshot := filepath.Join(snapshotsDirectory, filepath.Base(fixture)+"."+renderer.extension)
+			t.Run(filepath.Base(snapshot), func(t *testing.T) {
+				result := renderer.render(parser)
+				if *updateSnapshots {
+					if err := ioutil.WriteFile(snapshot, []byte(result), 0644); err != nil {
+						t.Fatalf("expected no error writing %s, got %s", snapshot, err.Error())
+					}
+					return
+				}
+				expected, err := ioutil.ReadFile(snapshot)
+				if err != nil {
+					t.Fatalf("expected no error reading %s, got %s. Run go test ./parser -run TestRendererSnapshots -update to create it", snapshot, err.Error())
+				}
+				if string(expected) != result {
+					t.Errorf("expected the render to match %s. Run go test ./parser -run TestRendererSnapshots -update after checking the diff.\nexpected:\n%s\ngot:\n%s", snapshot, strings.TrimSpace(string(expected)), strings.TrimSpace(result))
+				}
+			})
+		}
+	}
+}
+
+// TestRenderIsDeterministic parses every fixture twice and renders it repeatedly, toggling the snapshot options on and
+// off, to make sure neither the parsing order nor a previous render changes the output
+func TestRenderIsDeterministic(t *testing.T) {
+	// aggregations stay enabled so private aggregations leaking into them would be visible
+	disabledOptions := map[RenderingOption]interface{}{RenderAggregations: true}
+	for option, value := range snapshotRenderingOptions {
+		if option == RenderAggregations {
+			continue
+		}
+		switch value.(type) {
+		case bool:
+			disabledOptions[option] = false
+		case string:
+			disabledOptions[option] = ""
+		}
+	}
+	for _, fixture := range getSnapshotFixtures(t) {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			renders := [][]string{}
+			for i := 0; i < 2; i++ {
+				parser, err := NewClassDiagram([]string{fixture}, []string{}, false)
+				if err != nil {
+					t.Fatalf("expected no error parsing %s, got %s", fixture, err.Error())
+				}
+				for _, options := range []map[RenderingOption]interface{}{disabledOptions, snapshotRenderingOptions, disabledOptions, snapshotRenderingOptions} {
+					parser.SetRenderingOptions(options)
+					for _, renderer := range snapshotRenderers {
+						if len(renders) <= i {
+							renders = append(renders, []string{})
+						}
+						renders[i] = append(renders[i], renderer.render(parser))
+					}
+				}
+			}
+			for i := range renders[0] {
+				// every parser renders the same and the second pass over the options renders like the first one
+				expected := renders[0][i%(2*len(snapshotRenderers))]
+				for _, render := range [][]string{renders[0], renders[1]} {
+					if render[i] != expected {
+						t.Errorf("expected render %d to be deterministic.\nexpected:\n%s\ngot:\n%s", i, strings.TrimSpace(expected), strings.TrimSpace(render[i]))
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// NewClassDiagramFromFS returns the class diagram of the Directories of the options (e.g. . for its root) read from
+// the given file system (e.g. an embed.FS). The FileSystem of the options is not used.
+func NewClassDiagramFromFS(fsys fs.FS, options *ClassDiagramOptions) (*ClassParser, error) {
+	fsOptions := *options
+	fsOptions.FileSystem = afero.FromIOFS{FS: fsys}
+	return NewClassDiagramWithOptions(&fsOptions)
+}
+
+// NewClassDiagramFromSources returns the class diagram of the given go sources keyed by their file name (e.g.
+// shop/cart.go), parsed in memory. The directories of the files are parsed unless the Directories of the options are
+// given. The FileSystem of the options is not used.
+func NewClassDiagramFromSources(sources map[string]string, options *ClassDiagramOptions) (*ClassParser, error) {
+	fileSystem := afero.NewMemMapFs()
+	directories := map[string]struct{}{}
+	for fileName, source := range sources {
+		if err := afero.WriteFile(fileSystem, fileName, []byte(source), 0644); err != nil {
+			return nil, err
+		}
+		directories[filepath.Dir(fileName)] = struct{}{}
+	}
+	sourcesOptions := *options
+	sourcesOptions.FileSystem = fileSystem
+	if len(sourcesOptions.Directories) == 0 {
+		sourcesOptions.Directories = getSortedKeys(directories)
+	}
+	return NewClassDiagramWithOptions(&sourcesOptions)
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const cartSource = `package shop
+
+// Cart holds the items to buy
+type Cart struct {
+	Items []*Item
+}
+
+type Item struct {
+	Name string
+}
+`
+
+func TestNewClassDiagramFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"shop/cart.go":   {Data: []byte(cartSource)},
+		"shop/README.md": {Data: []byte("not go")},
+	}
+	for _, recursive := range []bool{false, true} {
+		directory := "shop"
+		if recursive {
+			directory = "."
+		}
+		parser, err := NewClassDiagramFromFS(fsys, &ClassDiagramOptions{
+			Directories:      []string{directory},
+			RenderingOptions: map[RenderingOption]interface{}{},
+			Recursive:        recursive,
+		})
+		if err != nil {
+			t.Fatalf("TestNewClassDiagramFromFS: expected no error when recursive is %t but got %s", recursive, err.Error())
+		}
+		if _, ok := parser.Structs()["shop.Cart"]; !ok {
+			t.Errorf("TestNewClassDiagramFromFS: expected shop.Cart to be parsed when recursive is %t, got %v", recursive, parser.Structs())
+		}
+	}
+}
+
+func TestNewClassDiagramFromSources(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{
+		"shop/cart.go":    cartSource,
+		"billing/bill.go": "package billing\n\ntype Bill struct {\n\tTotal int\n}\n",
+	}, &ClassDiagramOptions{
+		RenderingOptions: map[RenderingOption]interface{}{RenderAggregations: true},
+	})
+	if err != nil {
+		t.Fatalf("TestNewClassDiagramFromSources: expected no error but got %s", err.Error())
+	}
+	rendered := parser.Render()
+	for _, expected := range []string{"namespace billing {", "class Cart << (S,Aquamarine) >> {", `"shop.Cart" o-- "shop.Item"`} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("TestNewClassDiagramFromSources: expected %s in %s", expected, rendered)
+		}
+	}
+}
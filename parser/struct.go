@@ -2,10 +2,13 @@ package parser
 
 import (
 	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
 	"unicode"
 )
 
-// Struct represent a struct in golang, it can be of Type "class" or "interface" and can be associated
+// Struct represent a struct in golang, it can be of Type "class", "interface", "alias" or "enum" and can be associated
 // with other structs via Composition and Extends
 type Struct struct {
 	PackageName         string
@@ -16,17 +19,72 @@ type Struct struct {
 	Extends             map[string]struct{}
 	Aggregations        map[string]struct{}
 	PrivateAggregations map[string]struct{}
+	Conversions         map[string]struct{}
+	// Dependencies are the types whose methods are called or which are constructed in the methods of the structure.
+	// They are only found when ClassDiagramOptions.FindDependencies is set.
+	Dependencies       map[string]struct{}
+	Doc                string
+	EnumValues         []*EnumValue
+	EmbeddedInterfaces []string
+	// Qualifiers are the key types of the map fields holding the aggregated types, keyed by the aggregated type (e.g.
+	// {Session: {UserID}} for a map[UserID]*Session field)
+	Qualifiers map[string]map[string]struct{}
+	// PrivateQualifiers are the Qualifiers of the unexported map fields
+	PrivateQualifiers map[string]map[string]struct{}
+	// Multiplicities are the multiplicities of the slice, map and array fields holding the aggregated types, keyed by
+	// the aggregated type (e.g. {Seat: {0..*}} for a []*Seat field)
+	Multiplicities map[string]map[string]struct{}
+	// PrivateMultiplicities are the Multiplicities of the unexported fields
+	PrivateMultiplicities map[string]map[string]struct{}
+	// Channels are the directions (send and receive) of the channel fields carrying the aggregated types, keyed by
+	// the aggregated type (e.g. {Job: {receive}} for a <-chan Job field)
+	Channels map[string]map[string]struct{}
+	// PrivateChannels are the Channels of the unexported fields
+	PrivateChannels map[string]map[string]struct{}
+	// Stereotypes are the stereotypes given by the //plantuml:stereotype directives of the doc comment of the type
+	Stereotypes []string
+	// Test is true if the structure is declared in a _test.go file
+	Test bool
+	// FileName is the path of the file declaring the structure. It is empty for the types of which only methods were
+	// found
+	FileName string
+	// Line is the line of the declaration of the structure in FileName
+	Line      int
+	namedType types.Type
 }
 
 // ImplementsInterface returns true if the struct st conforms ot the given interface
 func (st *Struct) ImplementsInterface(inter *Struct) bool {
+	return st.implementsInterface(inter, nil)
+}
+
+// implementsInterface returns true if the struct st conforms to the given interface. When underlying is not nil the
+// parameters and return values are compared by their underlying types, underlying being used to resolve them when
+// there is no type information.
+func (st *Struct) implementsInterface(inter *Struct, underlying func(string) string) bool {
 	if len(inter.Functions) == 0 {
 		return false
 	}
+	typesImplements := typesImplementsInterface
+	if underlying != nil {
+		typesImplements = typesImplementsInterfaceByUnderlyingTypes
+	}
+	if implements, ok := typesImplements(st, inter); ok {
+		return implements
+	}
+	return st.implementsInterfaceBySignatures(inter, underlying)
+}
+
+// implementsInterfaceBySignatures returns true if st has a method with the signature of every method of inter. As
+// in Go, the unexported methods of an interface can only be implemented in its own package.
+func (st *Struct) implementsInterfaceBySignatures(inter *Struct, underlying func(string) string) bool {
 	for _, f1 := range inter.Functions {
+		if !ast.IsExported(f1.Name) && st.PackageName != inter.PackageName {
+			return false
+		}
 		foundMatch := false
 		for _, f2 := range st.Functions {
-			if f1.SignturesAreEqual(f2) {
+			if f1.signaturesAreEquivalent(f2, underlying) {
 				foundMatch = true
 				break
 			}
@@ -74,18 +132,36 @@ func (st *Struct) addToPrivateAggregation(fType string) {
 	st.PrivateAggregations[fType] = struct{}{}
 }
 
+// AddToConversions adds a "converts to" relationship to this struct, the given type is the package qualified name
+// of the type this struct is explicitly converted to
+func (st *Struct) AddToConversions(fType string) {
+	st.Conversions[fType] = struct{}{}
+}
+
+// AddToDependencies adds a "depends on" relationship to this struct, the given type is the package qualified name
+// of a type whose methods are called or which is constructed in the methods of this struct
+func (st *Struct) AddToDependencies(fType string) {
+	if st.Dependencies == nil {
+		st.Dependencies = map[string]struct{}{}
+	}
+	st.Dependencies[fType] = struct{}{}
+}
+
 // AddField adds a field into this structure. It parses the ast.Field and extract all
 // needed information
 func (st *Struct) AddField(field *ast.Field, aliases map[string]string) {
 	theType, fundamentalTypes := getFieldType(field.Type, aliases)
 	theType = replacePackageConstant(theType, "")
 	if field.Names != nil {
-		theType = replacePackageConstant(theType, "")
 		newField := &Field{
 			Name: field.Names[0].Name,
 			Type: theType,
+			Tag:  getFieldTag(field),
 		}
 		st.Fields = append(st.Fields, newField)
+		st.addQualifiers(newField, field.Type, aliases)
+		st.addMultiplicities(newField, field.Type, aliases)
+		st.addChannels(newField, field.Type, aliases)
 		if unicode.IsUpper(rune(newField.Name[0])) {
 			for _, t := range fundamentalTypes {
 				st.AddToAggregation(replacePackageConstant(t, st.PackageName))
@@ -95,14 +171,31 @@ func (st *Struct) AddField(field *ast.Field, aliases map[string]string) {
 				st.addToPrivateAggregation(replacePackageConstant(t, st.PackageName))
 			}
 		}
-	} else if field.Type != nil {
-		if theType[0] == "*"[0] {
-			theType = theType[1:]
+	} else if field.Type != nil && theType != "" {
+		theType = strings.TrimPrefix(theType, "*")
+		if generic := strings.Index(theType, "~"); generic > 0 {
+			// Embedded instantiations (e.g. Repo[User]) compose the generic type and use its type arguments
+			theType = theType[:generic]
+			for _, t := range fundamentalTypes[1:] {
+				st.AddToAggregation(replacePackageConstant(t, st.PackageName))
+			}
 		}
 		st.AddToComposition(theType)
 	}
 }
 
+// getFieldTag returns the unquoted tag of the field (e.g. json:"name") or an empty string if it has none
+func getFieldTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return field.Tag.Value
+	}
+	return tag
+}
+
 // AddMethod Parse the Field and if it is an ast.FuncType, then add the methods into the structure
 func (st *Struct) AddMethod(method *ast.Field, aliases map[string]string) {
 	f, ok := method.Type.(*ast.FuncType)
@@ -110,5 +203,6 @@ func (st *Struct) AddMethod(method *ast.Field, aliases map[string]string) {
 		return
 	}
 	function := getFunction(f, method.Names[0].Name, aliases, st.PackageName)
+	function.Doc = strings.TrimSpace(method.Doc.Text())
 	st.Functions = append(st.Functions, function)
 }
@@ -401,6 +401,39 @@ func TestAddField(t *testing.T) {
 	if !arrayContains(st.Aggregations, "main.FooComposed") {
 		t.Errorf("TestAddField: Expecting main.FooComposed to be part of the aggregations ,but the array had %v", st.Aggregations)
 	}
+	st.AddField(&ast.Field{
+		Names: []*ast.Ident{
+			{
+				Name: "Tagged",
+			},
+		},
+		Type: &ast.Ident{
+			Name: "string",
+		},
+		Tag: &ast.BasicLit{
+			Value: "`json:\"tagged\"`",
+		},
+	}, make(map[string]string))
+	if tag := st.Fields[len(st.Fields)-1].Tag; tag != `json:"tagged"` {
+		t.Errorf("TestAddField: Expecting the tag of the field to be json:\"tagged\", got %s", tag)
+	}
+	st.AddField(&ast.Field{
+		Names: nil,
+		Type: &ast.IndexExpr{
+			X: &ast.Ident{
+				Name: "Repo",
+			},
+			Index: &ast.Ident{
+				Name: "User",
+			},
+		},
+	}, make(map[string]string))
+	if !arrayContains(st.Composition, "Repo") {
+		t.Errorf("TestAddField: Expecting Repo to be part of the compositions ,but the array had %v", st.Composition)
+	}
+	if !arrayContains(st.Aggregations, "main.User") {
+		t.Errorf("TestAddField: Expecting main.User to be part of the aggregations ,but the array had %v", st.Aggregations)
+	}
 }
 
 func TestAddMethod(t *testing.T) {
@@ -0,0 +1,12 @@
+package parser
+
+import "strings"
+
+// getTestStereotype adds the test stereotype to the given one of a structure declared in a _test.go file, after its
+// spot if it has one (e.g. << (S,Aquamarine) test >>)
+func getTestStereotype(stereotype string) string {
+	if stereotype == "" {
+		return "<<test>>"
+	}
+	return strings.Replace(stereotype, ") >>", ") test >>", 1)
+}
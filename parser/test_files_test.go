@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIncludeTests(t *testing.T) {
+	tt := []struct {
+		Name         string
+		IncludeTests bool
+		Expected     []string
+		NotExpected  []string
+	}{
+		{
+			Name:         "without tests",
+			IncludeTests: false,
+			Expected:     []string{"class Client << (S,Aquamarine) >> {"},
+			NotExpected:  []string{"fakeGetter", "namespace testfiles_test"},
+		},
+		{
+			Name:         "with tests",
+			IncludeTests: true,
+			Expected: []string{
+				"class Client << (S,Aquamarine) >> {",
+				"class fakeGetter << (S,Aquamarine) test >> {",
+				`"testfiles.Getter" <|-- "testfiles.fakeGetter"`,
+				"namespace testfiles_test {",
+				"class suite << (S,Aquamarine) test >> {",
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:         afero.NewOsFs(),
+				Directories:        []string{"../testingsupport/testfiles"},
+				IgnoredDirectories: []string{},
+				RenderingOptions:   map[RenderingOption]interface{}{RenderPrivateMembers: true},
+				IncludeTests:       tc.IncludeTests,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			result := parser.Render()
+			for _, expected := range tc.Expected {
+				if !strings.Contains(result, expected) {
+					t.Errorf("expected the render to contain %s, got %s", expected, result)
+				}
+			}
+			for _, notExpected := range tc.NotExpected {
+				if strings.Contains(result, notExpected) {
+					t.Errorf("expected the render not to contain %s, got %s", notExpected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestGetTestStereotype(t *testing.T) {
+	tt := map[string]string{
+		"":                     "<<test>>",
+		"<< (S,Aquamarine) >>": "<< (S,Aquamarine) test >>",
+		"<< (T, #FF7700) >> ":  "<< (T, #FF7700) test >> ",
+	}
+	for stereotype, expected := range tt {
+		if result := getTestStereotype(stereotype); result != expected {
+			t.Errorf("TestGetTestStereotype: expected %s for %q, got %s", expected, stereotype, result)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderTheme renders the theme and the skinparams of the rendering options. The skinparams may be given with or
+// without the skinparam keyword.
+func (p *ClassParser) renderTheme(str *LineStringBuilder) {
+	if theme := strings.TrimSpace(p.renderingOptions.Theme); theme != "" {
+		str.WriteLineWithDepth(0, fmt.Sprintf("!theme %s", theme))
+	}
+	for _, line := range strings.Split(p.renderingOptions.SkinParams, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "skinparam ") {
+			line = fmt.Sprintf("skinparam %s", line)
+		}
+		str.WriteLineWithDepth(0, line)
+	}
+}
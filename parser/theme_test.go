@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTheme(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/subfolder3"}, []string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err.Error())
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{
+		RenderTheme: "cerulean",
+		SkinParams:  "linetype ortho\n\n  skinparam ArrowColor #333333  ",
+		RenderTitle: "Theme",
+	})
+	expected := "@startuml\n!theme cerulean\nskinparam linetype ortho\nskinparam ArrowColor #333333\ntitle Theme\n"
+	if rendered := parser.Render(); !strings.HasPrefix(rendered, expected) {
+		t.Errorf("TestRenderTheme: expected the render to start with\n%s\ngot\n%s", expected, rendered)
+	}
+	parser.SetRenderingOptions(map[RenderingOption]interface{}{RenderTheme: "", SkinParams: ""})
+	if rendered := parser.Render(); strings.Contains(rendered, "!theme") || strings.Contains(rendered, "skinparam") {
+		t.Errorf("TestRenderTheme: expected no theme nor skinparam, got\n%s", rendered)
+	}
+}
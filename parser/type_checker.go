@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stubImporter imports every package as a stub declaring, as opaque named types, the names the parsed packages select
+// from it (e.g. Buffer for bytes.Buffer), so type checking the parsed packages does not type check the packages they
+// import (see ClassDiagramOptions.TypeCheckImports). The signatures using these types are checked like the compiler
+// does, but nothing is known of their methods, fields or values. The types that cannot be stubbed (e.g. generic ones)
+// are invalid, and the interfaces using them fall back to the textual signature comparison.
+type stubImporter struct {
+	packages map[string]*types.Package
+}
+
+func newStubImporter() *stubImporter {
+	return &stubImporter{packages: map[string]*types.Package{}}
+}
+
+// Import returns the stub of the package of the given import path
+func (i *stubImporter) Import(importPath string) (*types.Package, error) {
+	if imported, ok := i.packages[importPath]; ok {
+		return imported, nil
+	}
+	imported := types.NewPackage(importPath, getImportName(importPath))
+	imported.MarkComplete()
+	i.packages[importPath] = imported
+	return imported, nil
+}
+
+// declare adds to the stubs of the packages imported by the given files the names the files select from them
+func (i *stubImporter) declare(files []*ast.File) {
+	for _, file := range files {
+		imports := map[string]string{}
+		for _, spec := range file.Imports {
+			importPath := strings.Trim(spec.Path.Value, "`\"")
+			name := getImportName(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = importPath
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := selector.X.(*ast.Ident); ok && imports[ident.Name] != "" {
+					i.declareType(imports[ident.Name], selector.Sel.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// declareType adds the given name to the stub of the package of the given import path as an opaque named type
+func (i *stubImporter) declareType(importPath string, name string) {
+	imported, _ := i.Import(importPath)
+	if imported.Scope().Lookup(name) != nil {
+		return
+	}
+	typeName := types.NewTypeName(token.NoPos, imported, name, nil)
+	types.NewNamed(typeName, types.NewStruct(nil, nil), nil)
+	imported.Scope().Insert(typeName)
+}
+
+// getImportName returns the name the package of the given import path is most likely declared with, which is the last
+// element of the path without its major version (e.g. yaml for gopkg.in/yaml.v3 and chi for github.com/go-chi/chi/v5)
+func getImportName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && isMajorVersion(name) {
+		name = elements[len(elements)-2]
+	}
+	name = strings.SplitN(name, ".", 2)[0]
+	return strings.ReplaceAll(strings.TrimPrefix(name, "go-"), "-", "")
+}
+
+// isMajorVersion returns true for the major version elements of the import paths (e.g. v2)
+func isMajorVersion(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(element[1:])
+	return err == nil
+}
+
+// typeCheckPackage runs go/types over the files of the given package so that interface implementations can be
+// resolved with the same rules the compiler uses (promoted methods, embedded interfaces, types from other packages).
+// Packages that do not type check cleanly are left untyped and fall back to the textual signature comparison, unless
+// their imports are stubbed: the errors (e.g. calls to the stubbed packages) are then expected and only the resolved
+// types are used.
+func (p *ClassParser) typeCheckPackage(directoryPath string, pack *ast.Package) {
+	defer p.recoverParseError(directoryPath)
+	var fileNames []string
+	for fileName := range pack.Files {
+		if !strings.HasSuffix(fileName, "_test.go") {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+	files := make([]*ast.File, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		files = append(files, pack.Files[fileName])
+	}
+	hasErrors := false
+	stubs, stubbedImports := p.typesImporter.(*stubImporter)
+	if stubbedImports {
+		stubs.declare(files)
+	}
+	conf := types.Config{
+		Importer: p.typesImporter,
+		Error: func(err error) {
+			hasErrors = !stubbedImports
+		},
+	}
+	info := p.newTypesInfo()
+	checked, _ := conf.Check(directoryPath, p.fileSet, files, info)
+	if checked == nil {
+		return
+	}
+	for _, imported := range checked.Imports() {
+		p.importedPackages[imported.Name()] = imported
+	}
+	// the uses and selections are only recorded when they are resolved, so they can be trusted even if some are not
+	p.inspectBodies(info, pack.Name, files)
+	if hasErrors {
+		return
+	}
+	p.addConversions(info, files)
+	for name, st := range p.structure[pack.Name] {
+		for _, value := range st.EnumValues {
+			if typed, ok := checked.Scope().Lookup(value.Name).(*types.Const); ok && typed.Val().Kind() != constant.Unknown {
+				value.Value = typed.Val().ExactString()
+			}
+		}
+		if typeName, ok := checked.Scope().Lookup(name).(*types.TypeName); ok {
+			st.namedType = typeName.Type()
+		}
+	}
+}
+
+// newTypesInfo returns the type information to record, which depends on the bodies to inspect (see inspectBodies)
+func (p *ClassParser) newTypesInfo() *types.Info {
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	if p.findDependencies {
+		info.Selections = make(map[*ast.SelectorExpr]*types.Selection)
+	}
+	if p.findCalls || p.findProviders {
+		info.Defs = make(map[*ast.Ident]types.Object)
+		info.Uses = make(map[*ast.Ident]types.Object)
+	}
+	return info
+}
+
+// inspectBodies records the dependencies, calls and providers found in the bodies of the given files of the package
+// pack when they were asked for
+func (p *ClassParser) inspectBodies(info *types.Info, pack string, files []*ast.File) {
+	if p.findDependencies {
+		p.addDependencies(info, files)
+	}
+	if p.findCalls {
+		p.addCalls(info, files)
+	}
+	if p.findProviders {
+		p.addProviders(info, pack, files)
+	}
+}
+
+// typesImplementsInterface uses the type information of both structures to check if st implements inter.
+// The second return value is false when there is not enough type information to decide.
+func typesImplementsInterface(st *Struct, inter *Struct) (bool, bool) {
+	if st.namedType == nil || inter.namedType == nil {
+		return false, false
+	}
+	iface, ok := inter.namedType.Underlying().(*types.Interface)
+	if !ok {
+		return false, false
+	}
+	if types.IsInterface(st.namedType) {
+		return false, true
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		if hasInvalidType(iface.Method(i).Type()) {
+			return false, false
+		}
+	}
+	if !haveSameUniverse(st, inter, iface) {
+		return false, false
+	}
+	return types.Implements(st.namedType, iface) || types.Implements(types.NewPointer(st.namedType), iface), true
+}
+
+// haveSameUniverse returns false if the signatures compared to check if st implements iface use two packages of the
+// same name. Every parsed package is type checked on its own while the other ones only see it through the importer,
+// so a type of a parsed package used by another one (e.g. buf.Buffer in a method of an interface of the package
+// iface) is never identical to the type of the parsed package itself.
+func haveSameUniverse(st *Struct, inter *Struct, iface *types.Interface) bool {
+	packages := map[string]*types.Package{}
+	for _, named := range []types.Type{st.namedType, inter.namedType} {
+		if !addTypePackages(named, packages) {
+			return false
+		}
+	}
+	methods := map[string]struct{}{}
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods[iface.Method(i).Name()] = struct{}{}
+		if !addTypePackages(iface.Method(i).Type(), packages) {
+			return false
+		}
+	}
+	methodSet := types.NewMethodSet(types.NewPointer(st.namedType))
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+		if _, ok := methods[method.Name()]; ok && !addTypePackages(method.Type(), packages) {
+			return false
+		}
+	}
+	return true
+}
+
+// addTypePackages adds the packages of the named types the given type is made of to packages, keyed by name. It
+// returns false if one of them is another package than the one already added with the same name.
+func addTypePackages(t types.Type, packages map[string]*types.Package) bool {
+	switch v := t.(type) {
+	case *types.Named:
+		pack := v.Obj().Pkg()
+		if pack == nil {
+			return true
+		}
+		if added, ok := packages[pack.Name()]; ok {
+			return added == pack
+		}
+		packages[pack.Name()] = pack
+		return true
+	case *types.Pointer:
+		return addTypePackages(v.Elem(), packages)
+	case *types.Slice:
+		return addTypePackages(v.Elem(), packages)
+	case *types.Array:
+		return addTypePackages(v.Elem(), packages)
+	case *types.Chan:
+		return addTypePackages(v.Elem(), packages)
+	case *types.Map:
+		return addTypePackages(v.Key(), packages) && addTypePackages(v.Elem(), packages)
+	case *types.Tuple:
+		for i := 0; i < v.Len(); i++ {
+			if !addTypePackages(v.At(i).Type(), packages) {
+				return false
+			}
+		}
+	case *types.Signature:
+		return addTypePackages(v.Params(), packages) && addTypePackages(v.Results(), packages)
+	}
+	return true
+}
+
+// hasInvalidType returns true if the given type, or one its signature, elements or keys are made of, could not be
+// resolved (e.g. a generic type of a package imported by the stubImporter)
+func hasInvalidType(t types.Type) bool {
+	switch v := t.(type) {
+	case *types.Basic:
+		return v.Kind() == types.Invalid
+	case *types.Pointer:
+		return hasInvalidType(v.Elem())
+	case *types.Slice:
+		return hasInvalidType(v.Elem())
+	case *types.Array:
+		return hasInvalidType(v.Elem())
+	case *types.Chan:
+		return hasInvalidType(v.Elem())
+	case *types.Map:
+		return hasInvalidType(v.Key()) || hasInvalidType(v.Elem())
+	case *types.Tuple:
+		for i := 0; i < v.Len(); i++ {
+			if hasInvalidType(v.At(i).Type()) {
+				return true
+			}
+		}
+	case *types.Signature:
+		return hasInvalidType(v.Params()) || hasInvalidType(v.Results())
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestTypesImplementsInterface(t *testing.T) {
+	parser, err := NewClassDiagram([]string{"../testingsupport/implementations"}, []string{}, false)
+	if err != nil {
+		t.Errorf("TestTypesImplementsInterface: expected no error but got %s", err.Error())
+		return
+	}
+	tt := []struct {
+		Name       string
+		Implements bool
+	}{
+		{
+			Name:       "implementations.Base",
+			Implements: true,
+		},
+		{
+			Name:       "implementations.Promoted",
+			Implements: true,
+		},
+		{
+			Name:       "implementations.NotImplementing",
+			Implements: false,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			st := parser.getStruct(tc.Name)
+			if st == nil {
+				t.Errorf("expected %s to exist", tc.Name)
+				return
+			}
+			if st.namedType == nil {
+				t.Errorf("expected %s to have type information", tc.Name)
+			}
+			if _, ok := st.Extends["implementations.Writer"]; ok != tc.Implements {
+				t.Errorf("expected %s implementing implementations.Writer to be %t", tc.Name, tc.Implements)
+			}
+		})
+	}
+}
+
+const stubImportsSource = `package shapes
+
+import "bytes"
+
+type Kind int
+
+const (
+	Circle Kind = iota + 1
+	Square
+)
+
+type Drawer interface {
+	Draw(b *bytes.Buffer) error
+	Kind() Kind
+}
+
+type Shape struct{}
+
+func (s *Shape) Draw(b *bytes.Buffer) error {
+	b.WriteString("shape")
+	return nil
+}
+
+func (s *Shape) Kind() Kind {
+	return Kind(1)
+}
+`
+
+func TestStubImports(t *testing.T) {
+	parser, err := NewClassDiagramFromSources(map[string]string{"shapes/shapes.go": stubImportsSource}, &ClassDiagramOptions{})
+	if err != nil {
+		t.Fatalf("TestStubImports: expected no error but got %s", err.Error())
+	}
+	if _, ok := parser.typesImporter.(*stubImporter); !ok {
+		t.Errorf("TestStubImports: expected the imports to be stubbed by default, got %T", parser.typesImporter)
+	}
+	shape := parser.getStruct("shapes.Shape")
+	if shape.namedType == nil {
+		t.Errorf("TestStubImports: expected shapes.Shape to have type information")
+	}
+	if _, ok := shape.Extends["shapes.Drawer"]; !ok {
+		t.Errorf("TestStubImports: expected shapes.Shape to implement shapes.Drawer by its signatures")
+	}
+	if values := parser.Structs()["shapes.Kind"].EnumValues; len(values) != 2 || values[0].Value != "1" || values[1].Value != "2" {
+		t.Errorf("TestStubImports: expected the values of the enum to be known, got %v", values)
+	}
+}
+
+func TestCrossPackageImplementations(t *testing.T) {
+	sources := map[string]string{
+		"buf/buf.go": `package buf
+
+type Buffer struct{}
+
+func (b *Buffer) WriteTo(o *Buffer) error { return nil }
+`,
+		"iface/iface.go": `package iface
+
+import "example.com/buf"
+
+type Writer interface {
+	WriteTo(o *buf.Buffer) error
+}
+`,
+	}
+	for _, typeCheckImports := range []bool{false, true} {
+		parser, err := NewClassDiagramFromSources(sources, &ClassDiagramOptions{TypeCheckImports: typeCheckImports})
+		if err != nil {
+			t.Fatalf("TestCrossPackageImplementations: expected no error but got %s", err.Error())
+		}
+		if _, ok := parser.getStruct("buf.Buffer").Extends["iface.Writer"]; !ok {
+			t.Errorf("TestCrossPackageImplementations: expected buf.Buffer to implement iface.Writer (TypeCheckImports %t)", typeCheckImports)
+		}
+	}
+}
+
+func TestGetImportName(t *testing.T) {
+	tt := []struct {
+		ImportPath string
+		Expected   string
+	}{
+		{ImportPath: "bytes", Expected: "bytes"},
+		{ImportPath: "go/ast", Expected: "ast"},
+		{ImportPath: "gopkg.in/yaml.v3", Expected: "yaml"},
+		{ImportPath: "github.com/go-chi/chi/v5", Expected: "chi"},
+		{ImportPath: "github.com/spf13/go-homedir", Expected: "homedir"},
+		{ImportPath: "github.com/google/go-cmp/cmp", Expected: "cmp"},
+	}
+	for _, tc := range tt {
+		if name := getImportName(tc.ImportPath); name != tc.Expected {
+			t.Errorf("TestGetImportName: expected %s for %s, got %s", tc.Expected, tc.ImportPath, name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// getFieldListNames returns the names declared by the given list of fields, nil if there is none
+func getFieldListNames(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var names []string
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// getIdentNames returns the names of the given identifiers, skipping the other expressions
+func getIdentNames(exprs []ast.Expr) []string {
+	names := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+// getReceiverType returns the type of the given method receiver without its pointer and type parameters (e.g. Repo
+// for *Repo[T]), and the names given to these parameters by the method
+func getReceiverType(receiver ast.Expr) (ast.Expr, []string) {
+	if star, ok := receiver.(*ast.StarExpr); ok {
+		receiver = star.X
+	}
+	return getReceiverBaseType(receiver)
+}
+
+// removeTypeParameters removes the relationships to the given type parameters of the structure, which are found as
+// types of its package (e.g. gen.T for the field Items []T of type Repo[T any] struct)
+func (st *Struct) removeTypeParameters(names []string) {
+	for _, name := range names {
+		fullName := fmt.Sprintf("%s.%s", st.PackageName, name)
+		for _, relationships := range []map[string]struct{}{st.Composition, st.Aggregations, st.PrivateAggregations} {
+			delete(relationships, fullName)
+			delete(relationships, name)
+		}
+		for _, labels := range []map[string]map[string]struct{}{
+			st.Qualifiers, st.PrivateQualifiers, st.Multiplicities, st.PrivateMultiplicities, st.Channels, st.PrivateChannels,
+		} {
+			delete(labels, fullName)
+		}
+	}
+}
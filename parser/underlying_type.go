@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"go/types"
+	"strings"
+)
+
+// getUnderlyingTypeName follows the parsed type declarations (e.g. type MyString string) to return the name of the
+// underlying type of the given one. Pointer, slice and array prefixes are kept.
+func (p *ClassParser) getUnderlyingTypeName(typeName string) string {
+	name := strings.TrimLeft(typeName, "*[]0123456789")
+	prefix := typeName[:len(typeName)-len(name)]
+	visited := map[string]struct{}{}
+	for {
+		alias, ok := p.allAliases[name]
+		if !ok {
+			break
+		}
+		if _, ok := visited[name]; ok {
+			break
+		}
+		visited[name] = struct{}{}
+		name = strings.TrimPrefix(alias.Name, builtinPackageName+".")
+		if strings.Count(name, ".") > 1 {
+			// types declared from another package are named after the declaring package first
+			name = strings.SplitN(name, ".", 2)[1]
+		}
+	}
+	return prefix + name
+}
+
+// typesImplementsInterfaceByUnderlyingTypes uses the type information of both structures to check if st has every
+// method of inter with parameters and return values of the same underlying types.
+// The second return value is false when there is not enough type information to decide.
+func typesImplementsInterfaceByUnderlyingTypes(st *Struct, inter *Struct) (bool, bool) {
+	if st.namedType == nil || inter.namedType == nil {
+		return false, false
+	}
+	iface, ok := inter.namedType.Underlying().(*types.Interface)
+	if !ok {
+		return false, false
+	}
+	if types.IsInterface(st.namedType) {
+		return false, true
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		object, _, _ := types.LookupFieldOrMethod(types.NewPointer(st.namedType), false, method.Pkg(), method.Name())
+		function, ok := object.(*types.Func)
+		if !ok || !underlyingSignaturesAreIdentical(function.Type().(*types.Signature), method.Type().(*types.Signature)) {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func underlyingSignaturesAreIdentical(s1 *types.Signature, s2 *types.Signature) bool {
+	return s1.Variadic() == s2.Variadic() && underlyingTuplesAreIdentical(s1.Params(), s2.Params()) && underlyingTuplesAreIdentical(s1.Results(), s2.Results())
+}
+
+func underlyingTuplesAreIdentical(t1 *types.Tuple, t2 *types.Tuple) bool {
+	if t1.Len() != t2.Len() {
+		return false
+	}
+	for i := 0; i < t1.Len(); i++ {
+		if !types.Identical(t1.At(i).Type().Underlying(), t2.At(i).Type().Underlying()) {
+			return false
+		}
+	}
+	return true
+}
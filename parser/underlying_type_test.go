@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestMatchUnderlyingTypes(t *testing.T) {
+	tt := []struct {
+		Name                 string
+		MatchUnderlyingTypes bool
+		Implementing         map[string]bool
+	}{
+		{
+			Name:                 "compiler rules",
+			MatchUnderlyingTypes: false,
+			Implementing: map[string]bool{
+				"underlyingtypes.StringWriter": false,
+				"underlyingtypes.NameWriter":   true,
+			},
+		},
+		{
+			Name:                 "underlying types",
+			MatchUnderlyingTypes: true,
+			Implementing: map[string]bool{
+				"underlyingtypes.StringWriter": true,
+				"underlyingtypes.NameWriter":   true,
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:           afero.NewOsFs(),
+				Directories:          []string{"../testingsupport/underlyingtypes"},
+				IgnoredDirectories:   []string{},
+				RenderingOptions:     map[RenderingOption]interface{}{},
+				MatchUnderlyingTypes: tc.MatchUnderlyingTypes,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			for name, implements := range tc.Implementing {
+				st := parser.getStruct(name)
+				if st == nil {
+					t.Fatalf("expected %s to exist", name)
+				}
+				if _, ok := st.Extends["underlyingtypes.Writer"]; ok != implements {
+					t.Errorf("expected %s implementing underlyingtypes.Writer to be %t", name, implements)
+				}
+			}
+		})
+	}
+}
+
+func TestImplementsInterfaceByUnderlyingTypesWithoutTypeInformation(t *testing.T) {
+	parser := &ClassParser{
+		allAliases: map[string]*Alias{
+			"main.MyString": getNewAlias("string", "main", "main.MyString"),
+			"main.Other":    getNewAlias("main.MyString", "main", "main.Other"),
+		},
+	}
+	inter := &Struct{
+		Functions: []*Function{
+			{Name: "Write", Parameters: []*Field{{FullType: "*string"}}, FullNameReturnValues: []string{"error"}},
+		},
+	}
+	st := &Struct{
+		Functions: []*Function{
+			{Name: "Write", Parameters: []*Field{{FullType: "*main.Other"}}, FullNameReturnValues: []string{"error"}},
+		},
+	}
+	if st.implementsInterface(inter, nil) {
+		t.Errorf("expected the struct not to implement the interface with the compiler rules")
+	}
+	if !st.implementsInterface(inter, parser.getUnderlyingTypeName) {
+		t.Errorf("expected the struct to implement the interface when matching underlying types")
+	}
+}
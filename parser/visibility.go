@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// getAccessModifier returns the symbol rendered before the given member name and false if the member should not be
+// rendered
+func (p *ClassParser) getAccessModifier(name string) (string, bool) {
+	if unicode.IsLower(rune(name[0])) {
+		return p.renderingOptions.PrivateMemberSymbol, p.renderingOptions.PrivateMembers
+	}
+	return p.renderingOptions.PublicMemberSymbol, true
+}
+
+// withAccessModifier prefixes the rendered member with the given access modifier unless it is empty
+func withAccessModifier(accessModifier string, member string) string {
+	if accessModifier == "" {
+		return member
+	}
+	return fmt.Sprintf(`%s %s`, accessModifier, member)
+}
+
+// renderLegend renders the notes, and the visibility legend and the package metrics when enabled, in the legend of
+// the diagram
+func (p *ClassParser) renderLegend(str *LineStringBuilder) {
+	lines := []string{}
+	if note := strings.TrimSpace(p.renderingOptions.Notes); note != "" {
+		lines = append(lines, note)
+	}
+	if p.renderingOptions.VisibilityLegend {
+		lines = append(lines, p.getVisibilityLegend()...)
+	}
+	if p.renderingOptions.MetricsLegend {
+		lines = append(lines, p.getMetricsLegend()...)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	str.WriteLineWithDepth(0, "legend")
+	for _, line := range lines {
+		str.WriteLineWithDepth(0, line)
+	}
+	str.WriteLineWithDepth(0, "end legend")
+}
+
+func (p *ClassParser) getVisibilityLegend() []string {
+	legend := []string{}
+	for _, member := range []struct {
+		symbol      string
+		description string
+		rendered    bool
+	}{
+		{p.renderingOptions.PublicMemberSymbol, "exported member", true},
+		{p.renderingOptions.PrivateMemberSymbol, "unexported member", p.renderingOptions.PrivateMembers},
+	} {
+		if !member.rendered {
+			continue
+		}
+		if member.symbol == "" {
+			legend = append(legend, fmt.Sprintf("no symbol: %s", member.description))
+		} else {
+			legend = append(legend, fmt.Sprintf(`""%s"": %s`, member.symbol, member.description))
+		}
+	}
+	return legend
+}
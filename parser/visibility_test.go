@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestRenderStructFieldsWithMemberSymbols(t *testing.T) {
+	tt := []struct {
+		Name            string
+		PublicSymbol    string
+		PrivateSymbol   string
+		ExpectedPrivate string
+		ExpectedPublic  string
+	}{
+		{
+			Name:            "custom symbols",
+			PublicSymbol:    "+",
+			PrivateSymbol:   "~",
+			ExpectedPrivate: "        ~ foo int\n",
+			ExpectedPublic:  "        + Bar string\n",
+		},
+		{
+			Name:            "no symbols",
+			ExpectedPrivate: "        foo int\n",
+			ExpectedPublic:  "        Bar string\n",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser := getEmptyParser("main")
+			parser.SetRenderingOptions(map[RenderingOption]interface{}{
+				PublicMemberSymbol:  tc.PublicSymbol,
+				PrivateMemberSymbol: tc.PrivateSymbol,
+			})
+			st := &Struct{
+				Fields: []*Field{
+					{Name: "foo", Type: "int"},
+					{Name: "Bar", Type: "string"},
+				},
+			}
+			privateFields := &LineStringBuilder{}
+			publicFields := &LineStringBuilder{}
+			parser.renderStructFields(st, privateFields, publicFields)
+			if privateFields.String() != tc.ExpectedPrivate {
+				t.Errorf("expected private fields to be %q, got %q", tc.ExpectedPrivate, privateFields.String())
+			}
+			if publicFields.String() != tc.ExpectedPublic {
+				t.Errorf("expected public fields to be %q, got %q", tc.ExpectedPublic, publicFields.String())
+			}
+		})
+	}
+}
+
+func TestRenderLegend(t *testing.T) {
+	tt := []struct {
+		Name             string
+		RenderingOptions map[RenderingOption]interface{}
+		Expected         string
+	}{
+		{
+			Name:             "nothing to render",
+			RenderingOptions: map[RenderingOption]interface{}{},
+			Expected:         "",
+		},
+		{
+			Name: "notes",
+			RenderingOptions: map[RenderingOption]interface{}{
+				RenderNotes: "a note",
+			},
+			Expected: "legend\na note\nend legend\n",
+		},
+		{
+			Name: "notes and visibility legend",
+			RenderingOptions: map[RenderingOption]interface{}{
+				RenderNotes:            "a note",
+				RenderVisibilityLegend: true,
+				PrivateMemberSymbol:    "",
+			},
+			Expected: "legend\na note\n\"\"+\"\": exported member\nno symbol: unexported member\nend legend\n",
+		},
+		{
+			Name: "visibility legend without private members",
+			RenderingOptions: map[RenderingOption]interface{}{
+				RenderVisibilityLegend: true,
+				RenderPrivateMembers:   false,
+			},
+			Expected: "legend\n\"\"+\"\": exported member\nend legend\n",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser := getEmptyParser("main")
+			parser.SetRenderingOptions(tc.RenderingOptions)
+			str := &LineStringBuilder{}
+			parser.renderLegend(str)
+			if str.String() != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, str.String())
+			}
+		})
+	}
+}
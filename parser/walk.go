@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// walker walks a directory tree like afero.Walk, following the symbolic links to directories when followSymlinks is
+// set. The directories are identified by their resolved path so the ones reached again through a link (e.g. a link to
+// one of their parents) are walked only once.
+type walker struct {
+	fs             afero.Fs
+	followSymlinks bool
+	visited        map[string]struct{}
+	walkFn         filepath.WalkFunc
+}
+
+// walk walks the file tree rooted at root, calling walkFn for each file or directory in it, including root. The links
+// are only followed on the OS file system, the only one able to resolve them.
+func walk(fs afero.Fs, root string, followSymlinks bool, walkFn filepath.WalkFunc) error {
+	_, isOsFs := fs.(*afero.OsFs)
+	w := &walker{
+		fs:             fs,
+		followSymlinks: followSymlinks && isOsFs,
+		visited:        map[string]struct{}{},
+		walkFn:         walkFn,
+	}
+	info, err := lstat(fs, root)
+	if err != nil {
+		return walkFn(root, nil, err)
+	}
+	return w.walk(root, info)
+}
+
+// walk walks the given file or directory. filepath.SkipDir is only returned when walkFn returns it for a file, to skip
+// the remaining files of its directory.
+func (w *walker) walk(path string, info os.FileInfo) error {
+	if w.followSymlinks && info.Mode()&os.ModeSymlink != 0 {
+		target, err := w.fs.Stat(path)
+		if err != nil {
+			// broken links are skipped like the ones that are not followed
+			return w.walkFn(path, info, nil)
+		}
+		info = target
+	}
+	if !info.IsDir() {
+		return w.walkFn(path, info, nil)
+	}
+	if w.followSymlinks {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return w.walkFn(path, info, err)
+		}
+		if _, ok := w.visited[resolved]; ok {
+			return nil
+		}
+		w.visited[resolved] = struct{}{}
+	}
+	if err := w.walkFn(path, info, nil); err != nil {
+		if err == filepath.SkipDir {
+			return nil
+		}
+		return err
+	}
+	children, err := afero.ReadDir(w.fs, path)
+	if err != nil {
+		if err := w.walkFn(path, info, err); err != filepath.SkipDir {
+			return err
+		}
+		return nil
+	}
+	for _, child := range children {
+		err := w.walk(filepath.Join(path, child.Name()), child)
+		if err == filepath.SkipDir {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lstat returns the information of the given path without following it if it is a link and the file system
+// supports it
+func lstat(fs afero.Fs, path string) (os.FileInfo, error) {
+	if lstater, ok := fs.(afero.Lstater); ok {
+		info, _, err := lstater.LstatIfPossible(path)
+		return info, err
+	}
+	return fs.Stat(path)
+}
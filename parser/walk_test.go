@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	modules := t.TempDir()
+	for directory, content := range map[string]string{
+		filepath.Join(root, "app"):        "package app\n\ntype App struct{}\n",
+		filepath.Join(modules, "storage"): "package storage\n\ntype Store struct{}\n",
+	} {
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(directory, filepath.Base(directory)+".go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a linked module and a link to a parent, which would be walked forever
+	if err := os.Symlink(filepath.Join(modules, "storage"), filepath.Join(root, "storage")); err != nil {
+		t.Skipf("symbolic links are not supported: %s", err.Error())
+	}
+	if err := os.Symlink(root, filepath.Join(root, "app", "loop")); err != nil {
+		t.Fatal(err)
+	}
+	tt := []struct {
+		Name           string
+		FollowSymlinks bool
+		Expected       []string
+	}{
+		{
+			Name:     "not followed",
+			Expected: []string{"app"},
+		},
+		{
+			Name:           "followed",
+			FollowSymlinks: true,
+			Expected:       []string{"app", "storage"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := NewClassDiagramWithOptions(&ClassDiagramOptions{
+				FileSystem:     afero.NewOsFs(),
+				Directories:    []string{root},
+				Recursive:      true,
+				FollowSymlinks: tc.FollowSymlinks,
+			})
+			if err != nil {
+				t.Fatalf("expected no error but got %s", err.Error())
+			}
+			packages := []string{}
+			for pack := range parser.structure {
+				packages = append(packages, pack)
+			}
+			sort.Strings(packages)
+			if !reflect.DeepEqual(packages, tc.Expected) {
+				t.Errorf("expected the packages %v, got %v", tc.Expected, packages)
+			}
+			if parser.parsedDirectories != len(tc.Expected)+1 {
+				t.Errorf("expected %d parsed directories, got %d", len(tc.Expected)+1, parser.parsedDirectories)
+			}
+		})
+	}
+}
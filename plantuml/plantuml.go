@@ -0,0 +1,88 @@
+/*
+Package plantuml renders PlantUML diagrams into images, either with a local plantuml.jar or with a PlantUML server.
+*/
+package plantuml
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ServerTimeout is the time NewServerRenderer gives the PlantUML server to render a diagram
+const ServerTimeout = time.Minute
+
+// Renderer renders a PlantUML diagram in the given format (e.g. svg or png)
+type Renderer interface {
+	Render(diagram string, format string) ([]byte, error)
+}
+
+// ServerRenderer renders the diagrams by posting them to a PlantUML server
+type ServerRenderer struct {
+	// URL of the PlantUML server (e.g. http://www.plantuml.com/plantuml)
+	URL    string
+	Client *http.Client
+}
+
+// NewServerRenderer returns a renderer that uses the PlantUML server at the given url, failing when it does not
+// respond within ServerTimeout
+func NewServerRenderer(url string) *ServerRenderer {
+	return &ServerRenderer{
+		URL:    strings.TrimSuffix(url, "/"),
+		Client: &http.Client{Timeout: ServerTimeout},
+	}
+}
+
+// Render posts the diagram to the PlantUML server and returns the rendered image
+func (r *ServerRenderer) Render(diagram string, format string) ([]byte, error) {
+	response, err := r.Client.Post(fmt.Sprintf("%s/%s", r.URL, format), "text/plain; charset=utf-8", bytes.NewBufferString(diagram))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PlantUML server responded %s", response.Status)
+	}
+	return body, nil
+}
+
+// JarRenderer renders the diagrams by piping them through a local plantuml.jar
+type JarRenderer struct {
+	// Jar is the path of plantuml.jar
+	Jar string
+	// Java is the java command, java if empty
+	Java string
+}
+
+// NewJarRenderer returns a renderer that uses the given plantuml.jar
+func NewJarRenderer(jar string) *JarRenderer {
+	return &JarRenderer{
+		Jar:  jar,
+		Java: "java",
+	}
+}
+
+// Render pipes the diagram through plantuml.jar and returns the rendered image
+func (r *JarRenderer) Render(diagram string, format string) ([]byte, error) {
+	java := r.Java
+	if java == "" {
+		java = "java"
+	}
+	cmd := exec.Command(java, "-jar", r.Jar, "-pipe", "-t"+format)
+	cmd.Stdin = strings.NewReader(diagram)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("plantuml.jar failed: %s %s", err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
@@ -0,0 +1,79 @@
+package plantuml
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerRenderer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/png" || string(body) != "@startuml\n@enduml\n" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("png"))
+	}))
+	defer server.Close()
+	renderer := NewServerRenderer(server.URL + "/")
+	image, err := renderer.Render("@startuml\n@enduml\n", "png")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err.Error())
+	}
+	if string(image) != "png" {
+		t.Errorf("expected png, got %s", string(image))
+	}
+	if _, err := renderer.Render("@startuml\n", "png"); err == nil {
+		t.Errorf("expected an error when the server does not respond 200")
+	}
+}
+
+func TestServerRendererTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	renderer := NewServerRenderer(server.URL)
+	if renderer.Client.Timeout != ServerTimeout {
+		t.Errorf("expected the client to time out after %s, got %s", ServerTimeout, renderer.Client.Timeout)
+	}
+	renderer.Client.Timeout = 10 * time.Millisecond
+	if _, err := renderer.Render("@startuml\n@enduml\n", "svg"); err == nil {
+		t.Errorf("expected an error when the server does not respond in time")
+	}
+}
+
+func TestJarRenderer(t *testing.T) {
+	// a fake java echoing its arguments and its standard input
+	dir, err := ioutil.TempDir("", "goplantuml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	java := filepath.Join(dir, "java")
+	if err := ioutil.WriteFile(java, []byte("#!/bin/sh\necho \"$@\"\ncat\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	renderer := NewJarRenderer("plantuml.jar")
+	renderer.Java = java
+	image, err := renderer.Render("@startuml\n@enduml\n", "svg")
+	if err != nil {
+		t.Skipf("cannot run the fake java: %s", err.Error())
+	}
+	expected := "-jar plantuml.jar -pipe -tsvg\n@startuml\n@enduml\n"
+	if string(image) != expected {
+		t.Errorf("expected %q, got %q", expected, string(image))
+	}
+	renderer.Java = filepath.Join(dir, "missing")
+	if _, err := renderer.Render("", "svg"); err == nil || !strings.HasPrefix(err.Error(), "plantuml.jar failed") {
+		t.Errorf("expected plantuml.jar failed error, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Package server exposes the class diagrams generated by github.com/jfeliu007/goplantuml/parser over HTTP.
+
+	GET /diagram?dir=path/to/gofiles&format=puml|dot|svg&recursive=true
+
+parses the given directories (dir can be repeated) on every request and returns the diagram. svg diagrams are
+rendered by the PlantUML server the Server is configured with.
+
+Only the directories inside the Server's AllowedDirectories can be parsed, and when a Token is set every request
+must carry it as an "Authorization: Bearer <token>" header.
+*/
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfeliu007/goplantuml/parser"
+	"github.com/jfeliu007/goplantuml/plantuml"
+	"github.com/spf13/afero"
+)
+
+// Server renders the class diagram of the directories requested over HTTP
+type Server struct {
+	// Renderer renders the svg diagrams. svg is not available when it is nil.
+	Renderer plantuml.Renderer
+	// RenderingOptions are used for every diagram
+	RenderingOptions map[parser.RenderingOption]interface{}
+	// AllowedDirectories are the roots of the directories that can be requested. Any directory can be requested
+	// when it is empty.
+	AllowedDirectories []string
+	// Token is the bearer token every request must be authorized with. Requests are not authenticated when it is
+	// empty.
+	Token string
+}
+
+// NewServer returns a Server that renders svg diagrams with the given PlantUML server (e.g.
+// http://www.plantuml.com/plantuml). svg is not available when it is empty.
+func NewServer(plantUMLServer string, renderingOptions map[parser.RenderingOption]interface{}) *Server {
+	var renderer plantuml.Renderer
+	if plantUMLServer != "" {
+		renderer = plantuml.NewServerRenderer(plantUMLServer)
+	}
+	return &Server{
+		Renderer:         renderer,
+		RenderingOptions: renderingOptions,
+	}
+}
+
+// Handler returns the handler serving the /diagram endpoint
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/diagram", s.handleDiagram)
+	return mux
+}
+
+func (s *Server) handleDiagram(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.isAuthorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="goplantuml"`)
+		http.Error(w, "a valid bearer token is required", http.StatusUnauthorized)
+		return
+	}
+	query := r.URL.Query()
+	directories := query["dir"]
+	if len(directories) == 0 {
+		http.Error(w, "the dir parameter is required", http.StatusBadRequest)
+		return
+	}
+	for _, directory := range directories {
+		if !s.isAllowed(directory) {
+			http.Error(w, fmt.Sprintf("directory %s is not allowed", directory), http.StatusForbidden)
+			return
+		}
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = "puml"
+	}
+	if format != "puml" && format != "dot" && format != "svg" {
+		http.Error(w, fmt.Sprintf("unknown format %s. One of puml, dot or svg", format), http.StatusBadRequest)
+		return
+	}
+	if format == "svg" && s.Renderer == nil {
+		http.Error(w, "svg is not available, no PlantUML server was configured", http.StatusNotImplemented)
+		return
+	}
+	// the parsing stops when the client goes away
+	result, err := parser.NewClassDiagramWithContext(r.Context(), &parser.ClassDiagramOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        directories,
+		IgnoredDirectories: []string{},
+		RenderingOptions:   s.RenderingOptions,
+		Recursive:          query.Get("recursive") == "true",
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	switch format {
+	case "puml":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, result.Render())
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		fmt.Fprint(w, result.RenderDot())
+	case "svg":
+		svg, err := s.Renderer.Render(result.Render(), "svg")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(svg)
+	}
+}
+
+// isAuthorized returns true if the request carries the Server's token, or if the Server has no token
+func (s *Server) isAuthorized(r *http.Request) bool {
+	if s.Token == "" {
+		return true
+	}
+	const prefix = "Bearer "
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, prefix)), []byte(s.Token)) == 1
+}
+
+// isAllowed returns true if the directory is one of the allowed directories or is inside one of them. Symbolic links
+// are resolved first so they cannot point outside of the allowed directories.
+func (s *Server) isAllowed(directory string) bool {
+	if len(s.AllowedDirectories) == 0 {
+		return true
+	}
+	path, err := resolvePath(directory)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range s.AllowedDirectories {
+		root, err := resolvePath(allowed)
+		if err != nil {
+			continue
+		}
+		relative, err := filepath.Rel(root, path)
+		if err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the absolute path of the given path with its symbolic links resolved. Paths that do not exist
+// are only made absolute.
+func resolvePath(path string) (string, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(absolute); err == nil {
+		return resolved, nil
+	}
+	return absolute, nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiagram(t *testing.T) {
+	plantUML := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/svg" || !strings.HasPrefix(string(body), "@startuml") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("<svg/>"))
+	}))
+	defer plantUML.Close()
+	handler := NewServer(plantUML.URL+"/", nil).Handler()
+	tt := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedPrefix string
+	}{
+		{name: "puml", url: "/diagram?dir=../testingsupport/connectionlabels", expectedStatus: http.StatusOK, expectedPrefix: "@startuml\nnamespace connectionlabels {"},
+		{name: "dot", url: "/diagram?dir=../testingsupport/connectionlabels&format=dot", expectedStatus: http.StatusOK, expectedPrefix: "digraph goplantuml {"},
+		{name: "svg", url: "/diagram?dir=../testingsupport/connectionlabels&format=svg", expectedStatus: http.StatusOK, expectedPrefix: "<svg/>"},
+		{name: "missing dir", url: "/diagram", expectedStatus: http.StatusBadRequest, expectedPrefix: "the dir parameter is required"},
+		{name: "unknown format", url: "/diagram?dir=../testingsupport&format=png", expectedStatus: http.StatusBadRequest, expectedPrefix: "unknown format png"},
+		{name: "missing directory", url: "/diagram?dir=../testingsupport/missing", expectedStatus: http.StatusInternalServerError},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.url, nil))
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tc.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if !strings.HasPrefix(recorder.Body.String(), tc.expectedPrefix) {
+				t.Errorf("expected the body to start with %q, got %q", tc.expectedPrefix, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestDiagramSVGWithoutPlantUMLServer(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewServer("", nil).Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/diagram?dir=../testingsupport&format=svg", nil))
+	if recorder.Code != http.StatusNotImplemented {
+		t.Errorf("expected status %d, got %d", http.StatusNotImplemented, recorder.Code)
+	}
+}
+
+func TestDiagramCanceledRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/diagram?dir=../testingsupport&recursive=true", nil).WithContext(ctx)
+	NewServer("", nil).Handler().ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), context.Canceled.Error()) {
+		t.Errorf("expected the parsing to be canceled, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestDiagramAllowedDirectories(t *testing.T) {
+	diagramServer := NewServer("", nil)
+	diagramServer.AllowedDirectories = []string{"../testingsupport/providers"}
+	handler := diagramServer.Handler()
+	tt := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "allowed root", url: "/diagram?dir=../testingsupport/providers", expectedStatus: http.StatusOK},
+		{name: "allowed subdirectory", url: "/diagram?dir=../testingsupport/providers/fx", expectedStatus: http.StatusOK},
+		{name: "parent", url: "/diagram?dir=../testingsupport", expectedStatus: http.StatusForbidden},
+		{name: "escape", url: "/diagram?dir=../testingsupport/providers/../../parser", expectedStatus: http.StatusForbidden},
+		{name: "one of many", url: "/diagram?dir=../testingsupport/providers&dir=/etc", expectedStatus: http.StatusForbidden},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.url, nil))
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tc.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestDiagramToken(t *testing.T) {
+	diagramServer := NewServer("", nil)
+	diagramServer.Token = "secret"
+	handler := diagramServer.Handler()
+	tt := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "valid token", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "wrong token", authorization: "Bearer other", expectedStatus: http.StatusUnauthorized},
+		{name: "missing token", expectedStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", authorization: "Basic secret", expectedStatus: http.StatusUnauthorized},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/diagram?dir=../testingsupport/connectionlabels", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tc.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
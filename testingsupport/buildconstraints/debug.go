@@ -0,0 +1,9 @@
+//go:build debug
+// +build debug
+
+package buildconstraints
+
+// Debugger dumps the settings of a store
+type Debugger struct {
+	Store *Store
+}
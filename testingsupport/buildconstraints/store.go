@@ -0,0 +1,6 @@
+package buildconstraints
+
+// Store saves the settings in the default location of the platform
+type Store struct {
+	Name string
+}
@@ -0,0 +1,6 @@
+package buildconstraints
+
+// Path returns the XDG configuration path
+func (s *Store) Path() string {
+	return "/etc/" + s.Name
+}
@@ -0,0 +1,6 @@
+package buildconstraints
+
+// Path returns the configuration path in the registry
+func (s *Store) Path() []string {
+	return []string{"HKEY_LOCAL_MACHINE", "SOFTWARE", s.Name}
+}
@@ -0,0 +1,6 @@
+package windows
+
+// Registry reads the settings from the windows registry
+type Registry struct {
+	Key string
+}
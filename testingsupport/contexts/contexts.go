@@ -0,0 +1,42 @@
+package contexts
+
+import (
+	"context"
+	stdcontext "context"
+)
+
+// Store propagates the context in most of its methods
+type Store struct {
+}
+
+// Get is for testing purposes
+func (s *Store) Get(ctx context.Context, id string) (string, error) {
+	return s.key(id), nil
+}
+
+// Put is for testing purposes
+func (s *Store) Put(ctx stdcontext.Context, id string, value string) error {
+	return nil
+}
+
+// List is for testing purposes
+func (s *Store) List(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// Delete does not take a context
+func (s *Store) Delete(id string) error {
+	return nil
+}
+
+func (s *Store) key(id string) string {
+	return id
+}
+
+// Cache does not take contexts either
+type Cache struct {
+}
+
+// Clear is for testing purposes
+func (c *Cache) Clear() {
+}
@@ -0,0 +1,28 @@
+package conversions
+
+import "fmt"
+
+// User is the domain model
+type User struct {
+	Name string
+}
+
+// UserDTO has the same layout as User and is converted from it
+type UserDTO struct {
+	Name string
+}
+
+// Status is converted from an int, which is not rendered
+type Status int
+
+// ToDTO converts the user into its transfer object
+func (u *User) ToDTO() *UserDTO {
+	dto := UserDTO(*u)
+	return &dto
+}
+
+// FromDTO converts the transfer object back into a user
+func FromDTO(dto UserDTO) User {
+	fmt.Println(Status(1))
+	return User(dto)
+}
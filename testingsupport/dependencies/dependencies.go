@@ -0,0 +1,39 @@
+package dependencies
+
+// User is registered by the Service
+type User struct {
+	Name string
+}
+
+// Repository stores the users
+type Repository struct{}
+
+// Save stores the given user
+func (r *Repository) Save(user *User) error {
+	return nil
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(to string) error
+}
+
+// Service registers users
+type Service struct {
+	repository *Repository
+	mailer     Mailer
+}
+
+// Register creates a user and sends them a welcome email
+func (s *Service) Register(name string) error {
+	user := &User{Name: name}
+	if err := s.repository.Save(user); err != nil {
+		return err
+	}
+	return s.mailer.Send(name)
+}
+
+// NewService is not a method, so its dependencies are not recorded
+func NewService(mailer Mailer) *Service {
+	return &Service{repository: &Repository{}, mailer: mailer}
+}
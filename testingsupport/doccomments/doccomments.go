@@ -0,0 +1,29 @@
+package doccomments
+
+// Documented is a struct with a doc comment that is long enough to be truncated when rendered
+type Documented struct {
+	Field int
+}
+
+// Public returns the value of the field.
+func (d *Documented) Public() int {
+	return d.Field
+}
+
+// private is only rendered with private members
+func (d *Documented) private() {
+}
+
+type (
+	// Grouped is declared inside a parenthesized type declaration
+	Grouped interface {
+		// Do does something
+		Do()
+	}
+
+	Undocumented struct{}
+)
+
+// Do implements Grouped
+func (u Undocumented) Do() {
+}
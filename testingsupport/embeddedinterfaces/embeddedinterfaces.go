@@ -0,0 +1,24 @@
+package embeddedinterfaces
+
+// Reader for testing purposes
+type Reader interface {
+	Read() string
+}
+
+// Closer for testing purposes
+type Closer interface {
+	Close() error
+}
+
+// ReadCloser embeds Reader and Closer
+type ReadCloser interface {
+	Reader
+	Closer
+	Reset()
+}
+
+// ReadResetCloser embeds Reader twice, directly and through ReadCloser
+type ReadResetCloser interface {
+	ReadCloser
+	Reader
+}
@@ -0,0 +1,23 @@
+package embeds
+
+import "embed"
+
+//go:embed static/*.css
+var static embed.FS
+
+var (
+	//go:embed "static/style.css"
+	style string
+
+	version = "1.0"
+)
+
+// Server serves the static assets
+type Server struct {
+	Name string
+}
+
+// Style returns the embedded stylesheet
+func (s *Server) Style() string {
+	return style
+}
@@ -0,0 +1,19 @@
+package embeds
+
+import "io/fs"
+
+// Handler lists the static assets declared in another file
+type Handler struct {
+}
+
+// Files is for testing purposes
+func (h *Handler) Files() ([]fs.DirEntry, error) {
+	static := static
+	return static.ReadDir("static")
+}
+
+// Name does not reference the assets even if a field has the same name
+func (h *Handler) Name(s *Server) string {
+	style := s.Name
+	return style
+}
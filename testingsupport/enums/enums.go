@@ -0,0 +1,39 @@
+package enums
+
+import "time"
+
+// Color is rendered as an enum
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	_
+	blue
+)
+
+const Yellow = Color(10)
+
+// Shape is a string enum declared in a separate block
+type Shape string
+
+const (
+	Circle Shape = "circle"
+	Square Shape = "square"
+)
+
+const (
+	untyped      = 1
+	timeout      = time.Second
+	typedInt int = 2
+)
+
+// Size has no constants so it stays an alias
+type Size int
+
+// Painter uses the enums
+type Painter struct {
+	Color Color
+	Shape Shape
+	Size  Size
+}
@@ -0,0 +1,17 @@
+package externaltypes
+
+import (
+	"io"
+	"time"
+)
+
+// Source implements io.Reader
+type Source struct {
+	Timeout time.Duration
+	Next    io.Reader
+}
+
+// Read is for testing purposes
+func (s *Source) Read(p []byte) (int, error) {
+	return 0, nil
+}
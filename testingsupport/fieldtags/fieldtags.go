@@ -0,0 +1,8 @@
+package fieldtags
+
+// User for testing purposes
+type User struct {
+	ID    int    `json:"id" gorm:"primaryKey"`
+	Email string `json:"email" validate:"required,email"`
+	Name  string
+}
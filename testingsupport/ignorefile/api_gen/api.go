@@ -0,0 +1,4 @@
+package api
+
+// Client is included back by the .plantumlignore file
+type Client struct{}
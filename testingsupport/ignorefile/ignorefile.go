@@ -0,0 +1,6 @@
+package ignorefile
+
+// Service is parsed
+type Service struct {
+	Name string
+}
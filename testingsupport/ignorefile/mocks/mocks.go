@@ -0,0 +1,4 @@
+package mocks
+
+// MockService is ignored by the .plantumlignore file
+type MockService struct{}
@@ -0,0 +1,4 @@
+package models
+
+// Model is ignored by the .plantumlignore file
+type Model struct{}
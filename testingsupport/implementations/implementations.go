@@ -0,0 +1,31 @@
+package implementations
+
+import "bytes"
+
+// Writer for testing purposes
+type Writer interface {
+	WriteTo(b *bytes.Buffer) error
+}
+
+// Base implements Writer directly
+type Base struct {
+}
+
+// WriteTo is for testing purposes
+func (b *Base) WriteTo(buf *bytes.Buffer) error {
+	return nil
+}
+
+// Promoted implements Writer through the method promoted from Base
+type Promoted struct {
+	*Base
+}
+
+// NotImplementing has the right method name with a different signature
+type NotImplementing struct {
+}
+
+// WriteTo is for testing purposes
+func (n *NotImplementing) WriteTo(buf bytes.Buffer) error {
+	return nil
+}
@@ -0,0 +1,57 @@
+package lifecycle
+
+import "context"
+
+// Server is started and stopped
+type Server struct {
+	address string
+}
+
+// NewServer returns a server listening on the given address
+func NewServer(address string) *Server {
+	return &Server{address: address}
+}
+
+// Start starts listening
+func (s *Server) Start() error {
+	return nil
+}
+
+// Stop stops listening
+func (s *Server) Stop(ctx context.Context) error {
+	return nil
+}
+
+// Worker runs until it is closed
+type Worker struct{}
+
+// NewWorker returns a worker
+func NewWorker() (*Worker, error) {
+	return &Worker{}, nil
+}
+
+// Run processes the jobs
+func (w *Worker) Run() error {
+	return nil
+}
+
+// Close releases the resources of the worker
+func (w *Worker) Close() error {
+	return nil
+}
+
+// File is only closed
+type File struct{}
+
+// Close closes the file
+func (f File) Close() error {
+	return nil
+}
+
+// Config has no lifecycle
+type Config struct{}
+
+// Validate validates the configuration
+func (c Config) Validate() error {
+	return nil
+}
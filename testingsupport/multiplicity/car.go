@@ -0,0 +1,26 @@
+package multiplicity
+
+// Wheel is for testing purposes
+type Wheel struct {
+}
+
+// Seat is for testing purposes
+type Seat struct {
+}
+
+// Passenger is for testing purposes
+type Passenger struct {
+}
+
+// Engine is for testing purposes
+type Engine struct {
+}
+
+// Car aggregates arrays, slices and maps of the other types
+type Car struct {
+	Wheels     [4]Wheel
+	Seats      []*Seat
+	Passengers map[string]*Passenger
+	Engine     *Engine
+	spares     []Wheel
+}
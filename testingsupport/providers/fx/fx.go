@@ -0,0 +1,20 @@
+// Package fx stubs the uber/fx functions used by the providers fixture
+package fx
+
+// Option configures an application
+type Option interface{}
+
+// Options combines the given options
+func Options(options ...Option) Option {
+	return options
+}
+
+// Provide registers the given constructors
+func Provide(constructors ...interface{}) Option {
+	return constructors
+}
+
+// Lifecycle is provided by the application
+type Lifecycle interface {
+	Append(hook interface{})
+}
@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"github.com/jfeliu007/goplantuml/testingsupport/providers/fx"
+	"github.com/jfeliu007/goplantuml/testingsupport/providers/wire"
+)
+
+// Config is given to the injector
+type Config struct {
+	DSN string
+}
+
+// Repository stores the users
+type Repository struct {
+	config Config
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(to string) error
+}
+
+// SMTPMailer sends emails with SMTP
+type SMTPMailer struct{}
+
+// Send sends an email to the given address
+func (m *SMTPMailer) Send(to string) error {
+	return nil
+}
+
+// Service registers users
+type Service struct {
+	repository *Repository
+	mailer     Mailer
+}
+
+// Clock is required but never provided
+type Clock interface {
+	Now() int64
+}
+
+// NewRepository returns a repository and its cleanup function
+func NewRepository(config Config) (*Repository, func(), error) {
+	return &Repository{config: config}, func() {}, nil
+}
+
+// NewSMTPMailer returns a mailer
+func NewSMTPMailer() *SMTPMailer {
+	return &SMTPMailer{}
+}
+
+// NewService returns a service
+func NewService(repository *Repository, mailer Mailer, clock Clock) *Service {
+	return &Service{repository: repository, mailer: mailer}
+}
+
+// Set provides the service and its dependencies
+var Set = wire.NewSet(NewRepository, NewSMTPMailer, wire.Bind(new(Mailer), new(*SMTPMailer)), NewService)
+
+// InitializeService is the wire injector of the service
+func InitializeService(config Config) (*Service, func(), error) {
+	wire.Build(Set)
+	return nil, nil, nil
+}
+
+// Module is the fx module of the service
+var Module = fx.Options(fx.Provide(NewService, func(lifecycle fx.Lifecycle, service *Service) string {
+	return "started"
+}))
@@ -0,0 +1,23 @@
+// Package wire stubs the google/wire functions used by the providers fixture
+package wire
+
+// ProviderSet is a set of providers
+type ProviderSet struct{}
+
+// NewSet returns a set of the given providers
+func NewSet(providers ...interface{}) ProviderSet {
+	return ProviderSet{}
+}
+
+// Build is called by the injectors
+func Build(providers ...interface{}) string {
+	return ""
+}
+
+// Binding binds an interface to an implementation
+type Binding struct{}
+
+// Bind binds the given interface to the given implementation
+func Bind(iface, to interface{}) Binding {
+	return Binding{}
+}
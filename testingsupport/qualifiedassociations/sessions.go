@@ -0,0 +1,15 @@
+package qualifiedassociations
+
+// UserID identifies a user
+type UserID string
+
+// Session is the state of a logged in user
+type Session struct {
+	User UserID
+}
+
+// Store keeps the sessions of the logged in users
+type Store struct {
+	Sessions map[UserID]*Session
+	byToken  map[string]*Session
+}
@@ -0,0 +1,72 @@
+package sequence
+
+import (
+	"errors"
+	"strings"
+)
+
+// Repository stores the names
+type Repository struct {
+	names []string
+}
+
+// Save stores the given name
+func (r *Repository) Save(name string) error {
+	r.names = append(r.names, name)
+	return nil
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(to string) error
+}
+
+// Service registers names
+type Service struct {
+	repository *Repository
+	mailer     Mailer
+}
+
+// Register validates, stores and welcomes the given name
+func (s *Service) Register(name string) error {
+	if err := validate(name); err != nil {
+		return err
+	}
+	if err := s.repository.Save(normalize(name)); err != nil {
+		return err
+	}
+	return s.mailer.Send(name)
+}
+
+// Handler handles the registration requests
+type Handler struct {
+	service *Service
+}
+
+// Handle registers the name of the request
+func (h *Handler) Handle(request string) error {
+	return h.service.Register(request)
+}
+
+// Tree is walked recursively
+type Tree struct {
+	children []*Tree
+}
+
+// Walk visits the tree and its children
+func (t *Tree) Walk() {
+	for _, child := range t.children {
+		child.Walk()
+	}
+}
+
+func validate(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	return nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(name)
+}
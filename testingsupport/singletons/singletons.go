@@ -0,0 +1,20 @@
+package singletons
+
+// Registry for testing purposes
+type Registry struct {
+	entries map[string]string
+}
+
+// Config for testing purposes
+type Config struct {
+	Name string
+}
+
+// DefaultRegistry is a pointer singleton
+var DefaultRegistry = &Registry{}
+
+var (
+	defaultConfig Config
+	counter       int
+	_             = Config{}
+)
@@ -0,0 +1,16 @@
+package testfiles
+
+// Getter fetches a resource
+type Getter interface {
+	Get(path string) (string, error)
+}
+
+// Client fetches resources from a server
+type Client struct {
+	URL string
+}
+
+// Get returns the resource at the given path
+func (c *Client) Get(path string) (string, error) {
+	return c.URL + path, nil
+}
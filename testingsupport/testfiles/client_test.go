@@ -0,0 +1,9 @@
+package testfiles
+
+type fakeGetter struct {
+	responses map[string]string
+}
+
+func (f *fakeGetter) Get(path string) (string, error) {
+	return f.responses[path], nil
+}
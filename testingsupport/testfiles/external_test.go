@@ -0,0 +1,7 @@
+package testfiles_test
+
+import "github.com/jfeliu007/goplantuml/testingsupport/testfiles"
+
+type suite struct {
+	client *testfiles.Client
+}
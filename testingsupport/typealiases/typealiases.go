@@ -0,0 +1,12 @@
+package typealiases
+
+// Config is for testing purposes
+type Config struct {
+	Name string
+}
+
+// Settings is a true alias of Config
+type Settings = Config
+
+// Options is a defined type of Config
+type Options Config
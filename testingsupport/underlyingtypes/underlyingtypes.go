@@ -0,0 +1,30 @@
+package underlyingtypes
+
+// MyString is a defined type of string
+type MyString string
+
+// Name is an alias of string
+type Name = string
+
+// Writer for testing purposes
+type Writer interface {
+	Write(s string) error
+}
+
+// StringWriter writes a defined type of string, it only implements Writer when matching underlying types
+type StringWriter struct {
+}
+
+// Write is for testing purposes
+func (w *StringWriter) Write(s MyString) error {
+	return nil
+}
+
+// NameWriter writes an alias of string, it always implements Writer
+type NameWriter struct {
+}
+
+// Write is for testing purposes
+func (w NameWriter) Write(s Name) error {
+	return nil
+}